@@ -5,12 +5,16 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
 	"fmt"
-	"log"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -18,24 +22,70 @@ import (
 	mcpauth "github.com/modelcontextprotocol/go-sdk/auth"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/pidgr/pidgr-mcp/internal/auth"
+	"github.com/pidgr/pidgr-mcp/internal/configfile"
+	"github.com/pidgr/pidgr-mcp/internal/convert"
 	"github.com/pidgr/pidgr-mcp/internal/observability"
+	"github.com/pidgr/pidgr-mcp/internal/secrets"
 	"github.com/pidgr/pidgr-mcp/internal/tools"
 	"github.com/pidgr/pidgr-mcp/internal/transport"
 	"go.opentelemetry.io/contrib/bridges/otelslog"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
-var version = "dev"
-
 func main() {
-	if err := run(); err != nil {
-		log.Fatalf("pidgr-mcp: %v", err)
+	// An optional subcommand comes before the flags.
+	args, command := os.Args[1:], ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+	flags := flag.NewFlagSet("pidgr-mcp", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: pidgr-mcp [version | validate] [flags]")
+		flags.PrintDefaults()
+	}
+	configFile := flags.String("config", os.Getenv("PIDGR_MCP_CONFIG"), "config file of KEY=value settings in .env format; environment variables override it")
+	envFile := flags.String("env-file", "", "`.env` file of KEY=value settings; environment variables override it, and it overrides -config")
+	showVersion := flags.Bool("version", false, "print build information and exit")
+	_ = flags.Parse(args)
+	files := []string{*envFile, *configFile}
+
+	switch {
+	case command == "version" || *showVersion:
+		printVersion(os.Stdout)
+	case command == "validate":
+		os.Exit(validate(os.Stdout, files))
+	case command != "":
+		flags.Usage()
+		os.Exit(2)
+	default:
+		if err := run(files); err != nil {
+			slog.Error("pidgr-mcp exited", "error", err)
+			os.Exit(1)
+		}
 	}
 }
 
-func run() error {
-	// Parse configuration from environment.
-	cfg, err := parseConfig()
+// loadConfig reads the .env and config files, if any, and parses the
+// configuration from them and the environment. files are in order of
+// precedence. It changes no settings, so a reloaded configuration is
+// validated whole before any of it is applied. Secret references found in
+// known, the secrets of a previous load, are not resolved again.
+func loadConfig(files []string, known map[string]string) (*config, error) {
+	file, err := configfile.ReadFiles(files...)
+	if err != nil {
+		return nil, fmt.Errorf("config file: %w", err)
+	}
+	env := settings{file: file, known: known, resolved: map[string]string{}}
+	cfg, err := parseConfig(env)
+	if err != nil {
+		return nil, err
+	}
+	cfg.secrets = env.resolved
+	return cfg, nil
+}
+
+func run(files []string) error {
+	cfg, err := loadConfig(files, nil)
 	if err != nil {
 		return err
 	}
@@ -54,10 +104,30 @@ func run() error {
 	}
 	defer func() { _ = lp.Shutdown(ctx) }()
 
-	// Fan out slog to both stdout (container logs) and OTEL (remote backend).
+	// Fan out slog to both the console (container logs) and OTEL (remote
+	// backend). In stdio mode stdout carries the MCP stream, so logs go to
+	// stderr. Setting the default also routes the log package through slog.
 	otelHandler := otelslog.NewHandler("pidgr-mcp", otelslog.WithLoggerProvider(lp))
-	stdoutHandler := slog.NewJSONHandler(os.Stdout, nil)
-	slog.SetDefault(slog.New(observability.NewFanoutHandler(stdoutHandler, otelHandler)))
+	console := os.Stdout
+	if cfg.Transport == "stdio" {
+		console = os.Stderr
+	}
+	consoleHandler := observability.NewStreamHandler(console, cfg.LogFormat, &logLevel)
+	logLevel.Set(cfg.LogLevel)
+	slog.SetDefault(slog.New(observability.NewLevelHandler(&logLevel, observability.NewFanoutHandler(consoleHandler, otelHandler))))
+
+	convert.SetMaxResultBytes(cfg.MaxResultBytes)
+	convert.SetDebugErrors(cfg.DebugErrors)
+	convert.SetUseProtoNames(cfg.UseProtoNames)
+	convert.SetRedaction(cfg.Redaction)
+	if err := convert.SetEnumStyle(cfg.EnumStyle); err != nil {
+		return fmt.Errorf("PIDGR_MCP_ENUM_STYLE: %w", err)
+	}
+	if err := convert.SetTimeRendering(cfg.timeLocation, cfg.TimeFormat, cfg.TimeCompanion); err != nil {
+		return fmt.Errorf("PIDGR_MCP_TIME_FORMAT: %w", err)
+	}
+	tools.SetBulkConcurrency(cfg.BulkConcurrency)
+	tools.SetToolFilter(cfg.Tools)
 
 	// Create MCP server.
 	server := mcp.NewServer(&mcp.Implementation{
@@ -68,34 +138,74 @@ func run() error {
 	// Create clients and register tools based on transport mode.
 	switch cfg.Transport {
 	case "stdio":
-		clients := transport.NewStaticTokenClients(cfg.ApiURL, cfg.apiKey)
+		clients := transport.NewStaticTokenClients(cfg.ApiURL, cfg.apiKey, cfg.Client)
+		if err := preflight(cfg, func(ctx context.Context) error {
+			return transport.Preflight(ctx, clients, cfg.ApiURL, true)
+		}); err != nil {
+			return err
+		}
 		tools.RegisterAll(server, clients)
-		return runStdio(server)
+		return runStdio(server, newReloader(files, cfg, clients))
 
 	case "http":
 		if !strings.HasPrefix(cfg.ApiURL, "https://") {
 			slog.Warn("PIDGR_API_URL is not HTTPS — traffic to the backend is unencrypted", "url", cfg.ApiURL)
 		}
-		clients := transport.NewDynamicTokenClients(cfg.ApiURL)
+		clients := transport.NewDynamicTokenClients(cfg.ApiURL, cfg.Client)
+		if err := preflight(cfg, func(ctx context.Context) error {
+			return transport.Preflight(ctx, clients, cfg.ApiURL, false)
+		}); err != nil {
+			return err
+		}
 		tools.RegisterAll(server, clients)
-		return runHTTP(server, cfg)
+		return runHTTP(server, cfg, newReloader(files, cfg, clients))
 
 	default:
 		return fmt.Errorf("invalid transport %q: must be 'stdio' or 'http'", cfg.Transport)
 	}
 }
 
-func runStdio(server *mcp.Server) error {
+// preflightTimeout bounds each startup connectivity check.
+const preflightTimeout = 15 * time.Second
+
+// preflight runs a startup connectivity check unless PIDGR_MCP_PREFLIGHT
+// disables it.
+func preflight(cfg *config, check func(ctx context.Context) error) error {
+	if !cfg.Preflight {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+	defer cancel()
+	if err := check(ctx); err != nil {
+		return fmt.Errorf("preflight: %w", err)
+	}
+	return nil
+}
+
+func runStdio(server *mcp.Server, r *reloader) error {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
+	go r.watch(ctx)
 	return server.Run(ctx, &mcp.StdioTransport{})
 }
 
-func runHTTP(server *mcp.Server, cfg *config) error {
+func runHTTP(server *mcp.Server, cfg *config, r *reloader) error {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
 	oidc := auth.NewOIDCVerifier(cfg.AuthIssuer, cfg.AuthClientID)
+	if cfg.Client.HTTP.Proxy != nil {
+		// The default client already follows HTTP_PROXY; only an explicit
+		// proxy needs a client of its own.
+		jwksTransport := http.DefaultTransport.(*http.Transport).Clone()
+		jwksTransport.Proxy = transport.ProxyFunc(cfg.Client.HTTP.Proxy)
+		oidc.SetHTTPClient(&http.Client{Transport: jwksTransport})
+	}
+	if err := preflight(cfg, oidc.Preflight); err != nil {
+		return err
+	}
+	r.oidc = oidc
+	go r.watch(ctx)
 	verifier := auth.NewCompositeVerifier(oidc)
 
 	resourceURL := "https://mcp.pidgr.com"
@@ -133,7 +243,7 @@ func runHTTP(server *mcp.Server, cfg *config) error {
 		}
 	}()
 
-	log.Printf("pidgr-mcp: listening on %s (http mode)", cfg.Addr)
+	slog.Info("listening", "addr", cfg.Addr, "transport", "http")
 	if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
 		return err
 	}
@@ -160,23 +270,316 @@ type config struct {
 	AuthIssuer   string
 	AuthClientID string
 	OTELEndpoint string
+	// apiKeyRef is the Secrets Manager, SSM, or Vault reference apiKey was
+	// resolved from, if any.
+	apiKeyRef string
+	// certFile and keyFile are the PIDGR_MCP_API_CERT_FILE and
+	// PIDGR_MCP_API_CLIENT_KEY_FILE settings, files or secret references.
+	certFile, keyFile string
+	// secrets holds the secret references resolved while loading, keyed by
+	// reference, for the next reload to reuse.
+	secrets map[string]string
+	// SecretRefresh is how often apiKeyRef and the client certificate are
+	// read again to pick up a rotated key; zero disables refreshing.
+	SecretRefresh time.Duration
+	// LogLevel is the minimum level of log records written.
+	LogLevel slog.Level
+	// LogFormat is the console log format, json or text.
+	LogFormat string
+	// MaxResultBytes caps serialized tool results; zero disables the limit.
+	MaxResultBytes int
+	// DebugErrors adds the error code and a redacted backend detail to error results.
+	DebugErrors bool
+	// Preflight checks the backend (and JWKS in http mode) at startup.
+	Preflight bool
+	// UseProtoNames renders result fields with snake_case proto names.
+	UseProtoNames bool
+	// EnumStyle selects how enum values are rendered: full, short, or label.
+	EnumStyle string
+	// TimeZone, TimeFormat, and TimeCompanion control how timestamps in
+	// results are rendered; an empty TimeZone leaves them in UTC.
+	TimeZone      string
+	TimeFormat    string
+	TimeCompanion bool
+	// timeLocation is TimeZone loaded, or nil for UTC.
+	timeLocation *time.Location
+	// Redaction lists the personal data removed from results.
+	Redaction convert.Redaction
+	// BulkConcurrency bounds the backend calls a bulk tool makes at once.
+	BulkConcurrency int
+	// Tools restricts the tools exposed to clients.
+	Tools tools.ToolFilter
+	// Client configures backend calls: retries, per-RPC timeouts, and the
+	// circuit breaker.
+	Client transport.Options
 }
 
-func parseConfig() (*config, error) {
+func parseConfig(env settings) (*config, error) {
+	var err error
+	if env.profile, err = selectProfile(env); err != nil {
+		return nil, err
+	}
 	cfg := &config{
-		Transport:    getEnv("PIDGR_MCP_TRANSPORT", "stdio"),
-		ApiURL:       getEnv("PIDGR_API_URL", "https://api.pidgr.com"),
-		apiKey:       os.Getenv("PIDGR_API_KEY"),
-		Addr:         getEnv("PIDGR_MCP_ADDR", ":8080"),
-		AuthIssuer:   os.Getenv("PIDGR_AUTH_ISSUER"),
-		AuthClientID: os.Getenv("PIDGR_AUTH_CLIENT_ID"),
-		OTELEndpoint: os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		Transport:    env.getEnv("PIDGR_MCP_TRANSPORT", "stdio"),
+		ApiURL:       env.getEnv("PIDGR_API_URL", "https://api.pidgr.com"),
+		Addr:         env.getEnv("PIDGR_MCP_ADDR", ":8080"),
+		AuthIssuer:   env.lookupEnv("PIDGR_AUTH_ISSUER"),
+		AuthClientID: env.lookupEnv("PIDGR_AUTH_CLIENT_ID"),
+		OTELEndpoint: env.lookupEnv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+	}
+
+	apiKey, err := env.secretEnv("PIDGR_API_KEY")
+	if err != nil {
+		return nil, err
+	}
+	if ciphertext := env.lookupEnv("PIDGR_API_KEY_KMS"); ciphertext != "" {
+		if apiKey != "" {
+			return nil, fmt.Errorf("set only one of PIDGR_API_KEY, PIDGR_API_KEY_FILE, and PIDGR_API_KEY_KMS")
+		}
+		if apiKey, err = env.decryptSecret("PIDGR_API_KEY_KMS", ciphertext); err != nil {
+			return nil, err
+		}
+	} else if secrets.IsReference(apiKey) {
+		cfg.apiKeyRef = apiKey
+		if apiKey, err = env.resolveSecret("PIDGR_API_KEY", apiKey); err != nil {
+			return nil, err
+		}
+	}
+	cfg.apiKey = strings.TrimSpace(apiKey)
+	if cfg.SecretRefresh, err = env.durationEnv("PIDGR_MCP_SECRET_REFRESH", defaultSecretRefresh); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.LogLevel.UnmarshalText([]byte(env.getEnv("PIDGR_LOG_LEVEL", "info"))); err != nil {
+		return nil, fmt.Errorf("PIDGR_LOG_LEVEL must be debug, info, warn, or error")
+	}
+	cfg.LogFormat = env.getEnv("PIDGR_LOG_FORMAT", "json")
+	if cfg.LogFormat != "json" && cfg.LogFormat != "text" {
+		return nil, fmt.Errorf("PIDGR_LOG_FORMAT must be 'json' or 'text', got %q", cfg.LogFormat)
+	}
+
+	maxResultBytes, err := strconv.Atoi(env.getEnv("PIDGR_MCP_MAX_RESULT_BYTES", strconv.Itoa(convert.DefaultMaxResultBytes)))
+	if err != nil || maxResultBytes < 0 {
+		return nil, fmt.Errorf("PIDGR_MCP_MAX_RESULT_BYTES must be a non-negative integer")
+	}
+	cfg.MaxResultBytes = maxResultBytes
+
+	bulkConcurrency, err := strconv.Atoi(env.getEnv("PIDGR_MCP_BULK_CONCURRENCY", strconv.Itoa(tools.DefaultBulkConcurrency)))
+	if err != nil || bulkConcurrency < 1 {
+		return nil, fmt.Errorf("PIDGR_MCP_BULK_CONCURRENCY must be a positive integer")
+	}
+	cfg.BulkConcurrency = bulkConcurrency
+
+	cfg.Tools = tools.ToolFilter{
+		Allow: splitList(env.lookupEnv("PIDGR_MCP_TOOLS_ALLOW")),
+		Deny:  splitList(env.lookupEnv("PIDGR_MCP_TOOLS_DENY")),
+	}
+	if err := cfg.Tools.Validate(); err != nil {
+		return nil, fmt.Errorf("PIDGR_MCP_TOOLS_ALLOW/PIDGR_MCP_TOOLS_DENY: %w", err)
+	}
+
+	debugErrors, err := strconv.ParseBool(env.getEnv("PIDGR_MCP_DEBUG_ERRORS", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("PIDGR_MCP_DEBUG_ERRORS must be a boolean")
+	}
+	cfg.DebugErrors = debugErrors
+
+	checkBackend, err := strconv.ParseBool(env.getEnv("PIDGR_MCP_PREFLIGHT", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("PIDGR_MCP_PREFLIGHT must be a boolean")
+	}
+	cfg.Preflight = checkBackend
+
+	useProtoNames, err := strconv.ParseBool(env.getEnv("PIDGR_MCP_PROTO_NAMES", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("PIDGR_MCP_PROTO_NAMES must be a boolean")
+	}
+	cfg.UseProtoNames = useProtoNames
+
+	cfg.EnumStyle = env.getEnv("PIDGR_MCP_ENUM_STYLE", convert.EnumsShort)
+	if err := convert.ValidateEnumStyle(cfg.EnumStyle); err != nil {
+		return nil, fmt.Errorf("PIDGR_MCP_ENUM_STYLE: %w", err)
+	}
+
+	cfg.TimeZone = env.lookupEnv("PIDGR_MCP_TIMEZONE")
+	cfg.TimeFormat = env.lookupEnv("PIDGR_MCP_TIME_FORMAT")
+	timeCompanion, err := strconv.ParseBool(env.getEnv("PIDGR_MCP_TIME_COMPANION", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("PIDGR_MCP_TIME_COMPANION must be a boolean")
+	}
+	cfg.TimeCompanion = timeCompanion
+	if cfg.TimeZone != "" {
+		if cfg.timeLocation, err = time.LoadLocation(cfg.TimeZone); err != nil {
+			return nil, fmt.Errorf("PIDGR_MCP_TIMEZONE: %w", err)
+		}
+	}
+	if err := convert.ValidateTimeFormat(cfg.TimeFormat); err != nil {
+		return nil, fmt.Errorf("PIDGR_MCP_TIME_FORMAT: %w", err)
+	}
+
+	for _, kind := range splitList(env.lookupEnv("PIDGR_MCP_REDACT")) {
+		switch strings.ToLower(kind) {
+		case "email":
+			cfg.Redaction.Emails = true
+		case "phone":
+			cfg.Redaction.Phones = true
+		default:
+			return nil, fmt.Errorf("PIDGR_MCP_REDACT: unknown kind %q: use email, phone", kind)
+		}
+	}
+	cfg.Redaction.Keys = splitList(env.lookupEnv("PIDGR_MCP_REDACT_KEYS"))
+
+	cfg.Client = transport.DefaultOptions()
+	cfg.Client.UserAgent = transport.UserAgent(version, cfg.Transport)
+	if cfg.Client.Timeouts.Read, err = env.durationEnv("PIDGR_MCP_RPC_TIMEOUT_READ", cfg.Client.Timeouts.Read); err != nil {
+		return nil, err
+	}
+	if cfg.Client.Timeouts.Write, err = env.durationEnv("PIDGR_MCP_RPC_TIMEOUT_WRITE", cfg.Client.Timeouts.Write); err != nil {
+		return nil, err
+	}
+	for _, entry := range splitList(env.lookupEnv("PIDGR_MCP_RPC_TIMEOUTS")) {
+		service, value, ok := strings.Cut(entry, "=")
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if !ok || err != nil || d < 0 {
+			return nil, fmt.Errorf("PIDGR_MCP_RPC_TIMEOUTS: %q must be Service=duration, e.g. ReplayService=60s", entry)
+		}
+		cfg.Client.Timeouts.Services[strings.TrimSpace(service)] = d
+	}
+
+	if cfg.Client.Breaker.Failures, err = env.intEnv("PIDGR_MCP_BREAKER_FAILURES", cfg.Client.Breaker.Failures); err != nil {
+		return nil, err
+	}
+	if cfg.Client.Breaker.Cooldown, err = env.durationEnv("PIDGR_MCP_BREAKER_COOLDOWN", cfg.Client.Breaker.Cooldown); err != nil {
+		return nil, err
+	}
+
+	if v := env.lookupEnv("PIDGR_MCP_RATE_LIMIT"); v != "" {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil || rate < 0 {
+			return nil, fmt.Errorf("PIDGR_MCP_RATE_LIMIT must be a non-negative number of calls per second")
+		}
+		cfg.Client.RateLimit.Rate = rate
+	}
+	if cfg.Client.RateLimit.Burst, err = env.intEnv("PIDGR_MCP_RATE_LIMIT_BURST", cfg.Client.RateLimit.Burst); err != nil {
+		return nil, err
+	}
+	if cfg.Client.RateLimit.MaxWait, err = env.durationEnv("PIDGR_MCP_RATE_LIMIT_WAIT", cfg.Client.RateLimit.MaxWait); err != nil {
+		return nil, err
+	}
+
+	httpCfg := &cfg.Client.HTTP
+	if httpCfg.MaxIdleConnsPerHost, err = env.intEnv("PIDGR_MCP_HTTP_MAX_IDLE_CONNS", httpCfg.MaxIdleConnsPerHost); err != nil {
+		return nil, err
+	}
+	if httpCfg.MaxConnsPerHost, err = env.intEnv("PIDGR_MCP_HTTP_MAX_CONNS", httpCfg.MaxConnsPerHost); err != nil {
+		return nil, err
+	}
+	if httpCfg.IdleConnTimeout, err = env.durationEnv("PIDGR_MCP_HTTP_IDLE_TIMEOUT", httpCfg.IdleConnTimeout); err != nil {
+		return nil, err
+	}
+	if httpCfg.DialTimeout, err = env.durationEnv("PIDGR_MCP_HTTP_DIAL_TIMEOUT", httpCfg.DialTimeout); err != nil {
+		return nil, err
+	}
+	if httpCfg.KeepAlive, err = env.durationEnv("PIDGR_MCP_HTTP_KEEPALIVE", httpCfg.KeepAlive); err != nil {
+		return nil, err
+	}
+	if httpCfg.TLSHandshakeTimeout, err = env.durationEnv("PIDGR_MCP_HTTP_TLS_TIMEOUT", httpCfg.TLSHandshakeTimeout); err != nil {
+		return nil, err
+	}
+	if caFile := env.lookupEnv("PIDGR_MCP_API_CA_FILE"); caFile != "" {
+		pem, err := env.readFileSetting("PIDGR_MCP_API_CA_FILE", caFile)
+		if err != nil {
+			return nil, err
+		}
+		httpCfg.RootCAs = x509.NewCertPool()
+		if !httpCfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("PIDGR_MCP_API_CA_FILE: no PEM certificates in %s", caFile)
+		}
+	}
+
+	certFile, keyFile := env.lookupEnv("PIDGR_MCP_API_CERT_FILE"), env.lookupEnv("PIDGR_MCP_API_CLIENT_KEY_FILE")
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("PIDGR_MCP_API_CERT_FILE and PIDGR_MCP_API_CLIENT_KEY_FILE must be set together")
+	}
+	if certFile != "" {
+		cert, err := env.loadClientCertificate(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		httpCfg.ClientCertificate = transport.NewClientCertificate(cert)
+		cfg.certFile, cfg.keyFile = certFile, keyFile
+	}
+
+	proxy, err := env.secretEnv("PIDGR_PROXY_URL")
+	if err != nil {
+		return nil, err
+	}
+	if proxy != "" {
+		u, err := url.Parse(proxy)
+		if err != nil {
+			// The URL may hold proxy credentials, so it is not echoed.
+			return nil, fmt.Errorf("PIDGR_PROXY_URL must be an http(s) or socks5 URL")
+		}
+		if (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") || u.Host == "" {
+			return nil, fmt.Errorf("PIDGR_PROXY_URL must be an http(s) or socks5 URL, got %q", u.Redacted())
+		}
+		httpCfg.Proxy = u
+	}
+
+	if cfg.Client.Cache.TTL, err = env.durationEnv("PIDGR_MCP_CACHE_TTL", cfg.Client.Cache.TTL); err != nil {
+		return nil, err
+	}
+	if cfg.Client.Cache.Revalidate, err = env.durationEnv("PIDGR_MCP_CACHE_REVALIDATE", cfg.Client.Cache.Revalidate); err != nil {
+		return nil, err
+	}
+	if cfg.Client.Cache.MaxEntries, err = env.intEnv("PIDGR_MCP_CACHE_MAX_ENTRIES", cfg.Client.Cache.MaxEntries); err != nil {
+		return nil, err
+	}
+	if cfg.Client.Cache.MaxBytes, err = env.intEnv("PIDGR_MCP_CACHE_MAX_BYTES", cfg.Client.Cache.MaxBytes); err != nil {
+		return nil, err
+	}
+	if cfg.Client.Cache.MaxEntryBytes, err = env.intEnv("PIDGR_MCP_CACHE_MAX_ENTRY_BYTES", cfg.Client.Cache.MaxEntryBytes); err != nil {
+		return nil, err
+	}
+
+	if fallback := env.lookupEnv("PIDGR_API_URL_FALLBACK"); fallback != "" {
+		u, err := url.Parse(fallback)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, fmt.Errorf("PIDGR_API_URL_FALLBACK must be an http(s) URL, got %q", fallback)
+		}
+		cfg.Client.Failover.URL = fallback
+	}
+	if cfg.Client.Failover.ProbeInterval, err = env.durationEnv("PIDGR_MCP_FAILOVER_PROBE_INTERVAL", cfg.Client.Failover.ProbeInterval); err != nil {
+		return nil, err
+	}
+
+	if cfg.Client.Hedge.Delay, err = env.durationEnv("PIDGR_MCP_HEDGE_DELAY", cfg.Client.Hedge.Delay); err != nil {
+		return nil, err
+	}
+	if services := splitList(env.lookupEnv("PIDGR_MCP_HEDGE_SERVICES")); services != nil {
+		cfg.Client.Hedge.Services = services
+	}
+
+	if cfg.Client.Protocol, err = transport.ParseProtocol(strings.ToLower(env.getEnv("PIDGR_MCP_API_PROTOCOL", string(transport.DefaultProtocol)))); err != nil {
+		return nil, fmt.Errorf("PIDGR_MCP_API_PROTOCOL: %w", err)
+	}
+
+	switch c := strings.ToLower(env.getEnv("PIDGR_MCP_COMPRESSION", "gzip")); c {
+	case "gzip":
+		cfg.Client.Compression.Gzip = true
+	case "none":
+		cfg.Client.Compression.Gzip = false
+	default:
+		return nil, fmt.Errorf("PIDGR_MCP_COMPRESSION must be 'gzip' or 'none', got %q", c)
+	}
+	if cfg.Client.Compression.MinBytes, err = env.intEnv("PIDGR_MCP_COMPRESS_MIN_BYTES", cfg.Client.Compression.MinBytes); err != nil {
+		return nil, err
 	}
 
 	switch cfg.Transport {
 	case "stdio":
 		if cfg.apiKey == "" {
-			return nil, fmt.Errorf("PIDGR_API_KEY is required for stdio mode")
+			return nil, fmt.Errorf("PIDGR_API_KEY, PIDGR_API_KEY_FILE, or PIDGR_API_KEY_KMS is required for stdio mode")
 		}
 	case "http":
 		if cfg.AuthIssuer == "" {
@@ -189,8 +592,192 @@ func parseConfig() (*config, error) {
 	return cfg, nil
 }
 
-func getEnv(key, defaultValue string) string {
-	if v := os.Getenv(key); v != "" {
+// settings is where the configuration is read from: the environment, then
+// the .env and config files, then the PIDGR_ENV profile.
+type settings struct {
+	file configfile.Settings
+	// profile holds the defaults of the selected PIDGR_ENV profile, if any.
+	profile map[string]string
+	// known holds the secrets an earlier load resolved or decrypted, keyed
+	// by reference or ciphertext; resolved collects those this load uses.
+	known, resolved map[string]string
+}
+
+// lookupEnv returns the value of key in the environment or a settings file
+// or, when neither sets it, the profile's default for it.
+func (s settings) lookupEnv(key string) string {
+	if v := s.file.Lookup(key); v != "" {
+		return v
+	}
+	return s.profile[key]
+}
+
+// intEnv reads a non-negative integer from the settings, returning def when
+// it is unset.
+func (s settings) intEnv(key string, def int) (int, error) {
+	v := s.lookupEnv(key)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", key)
+	}
+	return n, nil
+}
+
+// durationEnv reads a non-negative duration such as "15s" from the
+// settings, returning def when it is unset.
+func (s settings) durationEnv(key string, def time.Duration) (time.Duration, error) {
+	v := s.lookupEnv(key)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s must be a duration such as 15s", key)
+	}
+	return d, nil
+}
+
+// splitList splits a comma-separated environment value, dropping blanks.
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// secretEnv reads a secret from key or, for Docker and Kubernetes secret
+// mounts, from the file named by key_FILE, trimmed of surrounding
+// whitespace. Setting both is an error.
+func (s settings) secretEnv(key string) (string, error) {
+	path := s.lookupEnv(key + "_FILE")
+	if path == "" {
+		return s.lookupEnv(key), nil
+	}
+	if s.lookupEnv(key) != "" {
+		return "", fmt.Errorf("set only one of %s and %s_FILE", key, key)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("%s_FILE: %w", key, err)
+	}
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return "", fmt.Errorf("%s_FILE: %s is empty", key, path)
+	}
+	return secret, nil
+}
+
+// secretResolver resolves settings that reference a secret in AWS Secrets
+// Manager, SSM, or Vault.
+var secretResolver = secrets.NewResolver()
+
+// secretTimeout bounds resolving one secret reference.
+const secretTimeout = 15 * time.Second
+
+// defaultSecretRefresh is how often a referenced API key and the client
+// certificate are read again.
+const defaultSecretRefresh = 5 * time.Minute
+
+// resolveSecret returns value, or the secret it references when it names a
+// Secrets Manager secret, SSM parameter, or Vault secret (see
+// secrets.Resolver.Resolve).
+func resolveSecret(setting, value string) (string, error) {
+	if !secrets.IsReference(value) {
+		return value, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), secretTimeout)
+	defer cancel()
+	secret, err := secretResolver.Resolve(ctx, value)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", setting, err)
+	}
+	return secret, nil
+}
+
+// decryptSecret decrypts a setting holding a KMS ciphertext blob.
+func decryptSecret(setting, ciphertext string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), secretTimeout)
+	defer cancel()
+	secret, err := secretResolver.Decrypt(ctx, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", setting, err)
+	}
+	return secret, nil
+}
+
+// resolveSecret resolves value like the package-level resolveSecret, but
+// reuses a secret an earlier load resolved, so a reload fetches only the
+// references that changed.
+func (s settings) resolveSecret(setting, value string) (string, error) {
+	secret, ok := s.known[value]
+	if !ok {
+		var err error
+		if secret, err = resolveSecret(setting, value); err != nil {
+			return "", err
+		}
+	}
+	if secrets.IsReference(value) {
+		s.resolved[value] = secret
+	}
+	return secret, nil
+}
+
+// decryptSecret decrypts like the package-level decryptSecret, but reuses
+// the plaintext of a ciphertext an earlier load decrypted.
+func (s settings) decryptSecret(setting, ciphertext string) (string, error) {
+	secret, ok := s.known[ciphertext]
+	if !ok {
+		var err error
+		if secret, err = decryptSecret(setting, ciphertext); err != nil {
+			return "", err
+		}
+	}
+	s.resolved[ciphertext] = secret
+	return secret, nil
+}
+
+// loadClientCertificate reads the mTLS client certificate and private key
+// from the files or secrets the settings name.
+func (s settings) loadClientCertificate(certFile, keyFile string) (tls.Certificate, error) {
+	certPEM, err := s.readFileSetting("PIDGR_MCP_API_CERT_FILE", certFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyPEM, err := s.readFileSetting("PIDGR_MCP_API_CLIENT_KEY_FILE", keyFile)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("PIDGR_MCP_API_CERT_FILE: %w", err)
+	}
+	return cert, nil
+}
+
+// readFileSetting reads the file a setting names or, when the setting
+// references a Secrets Manager secret, SSM parameter, or Vault secret,
+// fetches the contents from there instead.
+func (s settings) readFileSetting(setting, value string) ([]byte, error) {
+	if secrets.IsReference(value) {
+		secret, err := s.resolveSecret(setting, value)
+		return []byte(secret), err
+	}
+	data, err := os.ReadFile(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", setting, err)
+	}
+	return data, nil
+}
+
+// getEnv returns the value of key, or defaultValue when it is unset.
+func (s settings) getEnv(key, defaultValue string) string {
+	if v := s.lookupEnv(key); v != "" {
 		return v
 	}
 	return defaultValue
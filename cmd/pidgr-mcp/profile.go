@@ -0,0 +1,64 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// profiles holds the defaults each PIDGR_ENV value applies, keyed by
+// environment variable. They sit between the environment (including the
+// config and .env files) and the built-in defaults: anything set explicitly
+// wins. The OIDC issuer differs per deployment and is not bundled.
+var profiles = map[string]map[string]string{
+	// dev targets a pidgr-api running locally, with verbose errors and logs
+	// and room to step through the backend in a debugger.
+	"dev": {
+		"PIDGR_API_URL":               "http://localhost:50051",
+		"PIDGR_MCP_DEBUG_ERRORS":      "true",
+		"PIDGR_LOG_LEVEL":             "debug",
+		"PIDGR_LOG_FORMAT":            "text",
+		"PIDGR_MCP_RPC_TIMEOUT_READ":  "60s",
+		"PIDGR_MCP_RPC_TIMEOUT_WRITE": "60s",
+	},
+	// staging keeps debug detail in errors and logs. It has no API URL of
+	// its own, so PIDGR_API_URL must be set rather than falling back to
+	// production.
+	"staging": {
+		"PIDGR_MCP_DEBUG_ERRORS": "true",
+		"PIDGR_LOG_LEVEL":        "debug",
+	},
+	"prod": {
+		"PIDGR_API_URL":               "https://api.pidgr.com",
+		"PIDGR_MCP_DEBUG_ERRORS":      "false",
+		"PIDGR_LOG_LEVEL":             "info",
+		"PIDGR_LOG_FORMAT":            "json",
+		"PIDGR_MCP_RPC_TIMEOUT_READ":  "15s",
+		"PIDGR_MCP_RPC_TIMEOUT_WRITE": "30s",
+	},
+}
+
+// selectProfile returns the defaults of the profile PIDGR_ENV names, or nil
+// when it is unset.
+func selectProfile(env settings) (map[string]string, error) {
+	name := env.lookupEnv("PIDGR_ENV")
+	if name == "" {
+		return nil, nil
+	}
+	p, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for n := range profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("PIDGR_ENV must be one of %s, got %q", strings.Join(names, ", "), name)
+	}
+	if name == "staging" && env.lookupEnv("PIDGR_API_URL") == "" {
+		return nil, fmt.Errorf("PIDGR_API_URL is required with PIDGR_ENV=staging")
+	}
+	return p, nil
+}
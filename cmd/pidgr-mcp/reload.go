@@ -0,0 +1,139 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pidgr/pidgr-mcp/internal/auth"
+	"github.com/pidgr/pidgr-mcp/internal/tools"
+	"github.com/pidgr/pidgr-mcp/internal/transport"
+)
+
+// logLevel is the minimum level of the default logger, set from
+// PIDGR_LOG_LEVEL at startup and on reload.
+var logLevel slog.LevelVar
+
+// reloader re-reads the configuration when the process receives SIGHUP and
+// applies the settings that can change without dropping MCP sessions: the
+// log level, the tool filter, the rate limit, the API key, and the OIDC
+// issuer and client ID used to validate tokens. Other settings take effect
+// on restart. It also re-resolves an API key kept in AWS or Vault and
+// re-reads the mTLS client certificate, so rotated credentials are picked
+// up without a reload.
+type reloader struct {
+	files   []string
+	clients *transport.Clients
+	// oidc is nil in stdio mode.
+	oidc *auth.OIDCVerifier
+
+	apiKeyRef     string
+	secretRefresh time.Duration
+	// clientCert is nil without mutual TLS.
+	clientCert        *transport.ClientCertificate
+	certFile, keyFile string
+	// secrets holds the secret references resolved by the last load, so a
+	// reload fetches only those that changed.
+	secrets map[string]string
+}
+
+func newReloader(files []string, cfg *config, clients *transport.Clients) *reloader {
+	return &reloader{
+		files:         files,
+		clients:       clients,
+		apiKeyRef:     cfg.apiKeyRef,
+		secretRefresh: cfg.SecretRefresh,
+		clientCert:    cfg.Client.HTTP.ClientCertificate,
+		certFile:      cfg.certFile,
+		keyFile:       cfg.keyFile,
+		secrets:       cfg.secrets,
+	}
+}
+
+// watch reloads the configuration on each SIGHUP, and refreshes the API key
+// and client certificate every secretRefresh, until ctx is done.
+func (r *reloader) watch(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	var refresh <-chan time.Time
+	if r.secretRefresh > 0 {
+		t := time.NewTicker(r.secretRefresh)
+		defer t.Stop()
+		refresh = t.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.reload()
+		case <-refresh:
+			r.refreshAPIKey()
+			r.refreshClientCertificate()
+		}
+	}
+}
+
+// refreshAPIKey resolves the API key reference again. On failure the
+// current key is kept, since it usually stays valid through a rotation.
+func (r *reloader) refreshAPIKey() {
+	if r.apiKeyRef == "" {
+		return
+	}
+	key, err := resolveSecret("PIDGR_API_KEY", r.apiKeyRef)
+	if err != nil {
+		slog.Warn("API key refresh failed; keeping the current key", "error", err)
+		return
+	}
+	r.secrets[r.apiKeyRef] = key
+	r.clients.SetAPIKey(strings.TrimSpace(key))
+}
+
+// refreshClientCertificate reads the mTLS client certificate and key again;
+// new connections present the result. On failure, including a certificate
+// and key caught mid-rotation that do not match, the current one is kept.
+func (r *reloader) refreshClientCertificate() {
+	if r.clientCert == nil {
+		return
+	}
+	env := settings{resolved: r.secrets}
+	cert, err := env.loadClientCertificate(r.certFile, r.keyFile)
+	if err != nil {
+		slog.Warn("client certificate refresh failed; keeping the current certificate", "error", err)
+		return
+	}
+	r.clientCert.Set(cert)
+}
+
+// reload re-reads the .env and config files and the environment. Reading
+// and parsing them change nothing, so an invalid configuration is logged and
+// the current settings are kept whole. Only the settings listed at reloader
+// are applied; the rest, such as enum and time rendering, which request
+// handlers read unsynchronized, wait for a restart.
+func (r *reloader) reload() {
+	cfg, err := loadConfig(r.files, r.secrets)
+	if err != nil {
+		slog.Error("configuration reload failed; keeping the current settings", "error", err)
+		return
+	}
+	logLevel.Set(cfg.LogLevel)
+	tools.SetToolFilter(cfg.Tools)
+	r.clients.SetRateLimit(cfg.Client.RateLimit)
+	if cfg.apiKey != "" {
+		r.clients.SetAPIKey(cfg.apiKey)
+	}
+	r.apiKeyRef = cfg.apiKeyRef
+	r.secrets = cfg.secrets
+	if r.oidc != nil {
+		r.oidc.SetIssuer(cfg.AuthIssuer, cfg.AuthClientID)
+	}
+	slog.Info("configuration reloaded", "log_level", cfg.LogLevel.String())
+}
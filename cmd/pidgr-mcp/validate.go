@@ -0,0 +1,86 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pidgr/pidgr-mcp/internal/transport"
+)
+
+// dnsTimeout bounds each host lookup in validate.
+const dnsTimeout = 5 * time.Second
+
+// validate checks the configuration without starting the server: it parses
+// the config file and environment, including mutually required settings,
+// and resolves the hosts the server will call. It prints one line per check
+// and returns the process exit code: 0 when every check passed, 1 otherwise.
+func validate(w io.Writer, files []string) int {
+	cfg, err := loadConfig(files, nil)
+	if err != nil {
+		fmt.Fprintf(w, "FAIL  configuration: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(w, "ok    configuration: %s mode\n", cfg.Transport)
+	if cfg.Transport == "http" && !strings.HasPrefix(cfg.ApiURL, "https://") {
+		fmt.Fprintf(w, "warn  PIDGR_API_URL: %s is not HTTPS; traffic to the backend is unencrypted\n", cfg.ApiURL)
+	}
+
+	type host struct{ setting, url string }
+	hosts := []host{
+		{"PIDGR_API_URL", cfg.ApiURL},
+		{"PIDGR_API_URL_FALLBACK", cfg.Client.Failover.URL},
+	}
+	if cfg.Transport == "http" {
+		hosts = append(hosts, host{"PIDGR_AUTH_ISSUER", cfg.AuthIssuer})
+	}
+	if cfg.Client.HTTP.Proxy != nil {
+		hosts = append(hosts, host{"PIDGR_PROXY_URL", cfg.Client.HTTP.Proxy.String()})
+	}
+
+	code := 0
+	proxy := transport.ProxyFunc(cfg.Client.HTTP.Proxy)
+	for _, h := range hosts {
+		if h.url == "" {
+			continue
+		}
+		if msg, ok := resolve(h.url, proxy); ok {
+			fmt.Fprintf(w, "ok    %s: %s\n", h.setting, msg)
+		} else {
+			fmt.Fprintf(w, "FAIL  %s: %s\n", h.setting, msg)
+			code = 1
+		}
+	}
+	return code
+}
+
+// resolve looks up the host of rawURL. Hosts reached through a proxy are
+// resolved by the proxy, so they are not looked up locally.
+func resolve(rawURL string, proxy func(*http.Request) (*url.URL, error)) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Sprintf("%q is not a valid URL", rawURL), false
+	}
+	host := u.Hostname()
+	if net.ParseIP(host) != nil {
+		return host + " is an IP address", true
+	}
+	if p, _ := proxy(&http.Request{URL: u}); p != nil {
+		return fmt.Sprintf("%s is resolved by proxy %s", host, p.Host), true
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dnsTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return fmt.Sprintf("cannot resolve %s: %v", host, err), false
+	}
+	return fmt.Sprintf("%s resolves to %s", host, strings.Join(addrs, ", ")), true
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Build information, set at release time with -ldflags "-X main.version=...
+// -X main.commit=... -X main.date=...". Builds without ldflags fall back to
+// the VCS stamp Go embeds (see buildInfo).
+var (
+	version = "dev"
+	commit  = ""
+	date    = ""
+)
+
+// features lists the optional capabilities compiled into this binary.
+var features = []string{
+	"stdio", "http", "oauth", "otel",
+	"grpc", "grpcweb", "connect",
+	"gzip", "cache", "hedge", "failover", "mtls", "proxy", "aws-secrets", "vault", "kms",
+}
+
+// buildInfo returns the commit and build date, taken from ldflags or, for
+// plain go build from a checkout, from the embedded VCS stamp.
+func buildInfo() (rev, built string, modified bool) {
+	rev, built = commit, date
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if rev == "" {
+					rev = s.Value
+				}
+			case "vcs.time":
+				if built == "" {
+					built = s.Value
+				}
+			case "vcs.modified":
+				modified = commit == "" && s.Value == "true"
+			}
+		}
+	}
+	if rev == "" {
+		rev = "unknown"
+	}
+	if built == "" {
+		built = "unknown"
+	}
+	return rev, built, modified
+}
+
+// printVersion writes the build information included in bug reports.
+func printVersion(w io.Writer) {
+	rev, built, modified := buildInfo()
+	if modified {
+		rev += " (modified)"
+	}
+	fmt.Fprintf(w, "pidgr-mcp %s\n", version)
+	fmt.Fprintf(w, "  commit:   %s\n", rev)
+	fmt.Fprintf(w, "  built:    %s\n", built)
+	fmt.Fprintf(w, "  go:       %s\n", runtime.Version())
+	fmt.Fprintf(w, "  platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(w, "  features: %s\n", strings.Join(features, ", "))
+}
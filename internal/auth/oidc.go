@@ -20,11 +20,12 @@ const jwksCacheTTL = time.Hour
 
 // OIDCVerifier validates OIDC JWTs using JWKS discovery.
 type OIDCVerifier struct {
-	clientID string
-	issuer   string
-	jwksURL  string
+	client *http.Client
 
 	mu          sync.RWMutex
+	clientID    string
+	issuer      string
+	jwksURL     string
 	keySet      jwk.Set
 	fetched     bool
 	lastFetched time.Time
@@ -40,8 +41,34 @@ func NewOIDCVerifier(issuerURL, clientID string) *OIDCVerifier {
 	}
 }
 
+// SetHTTPClient sets the client used to fetch the JWKS, e.g. one that goes
+// through the configured outbound proxy. It must be called before the
+// verifier is used; by default http.DefaultClient is used.
+func (v *OIDCVerifier) SetHTTPClient(c *http.Client) {
+	v.client = c
+}
+
+// SetIssuer changes the issuer and client ID tokens are validated against,
+// e.g. when the configuration is reloaded. Changing the issuer drops the
+// cached JWKS, so the new issuer's keys are fetched on the next token.
+func (v *OIDCVerifier) SetIssuer(issuerURL, clientID string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if issuerURL != v.issuer {
+		v.jwksURL = issuerURL + "/.well-known/jwks.json"
+		v.keySet = nil
+		v.fetched = false
+	}
+	v.issuer = issuerURL
+	v.clientID = clientID
+}
+
 // Verify implements auth.TokenVerifier for the MCP SDK.
 func (v *OIDCVerifier) Verify(ctx context.Context, token string, _ *http.Request) (*mcpauth.TokenInfo, error) {
+	v.mu.RLock()
+	issuer, clientID := v.issuer, v.clientID
+	v.mu.RUnlock()
+
 	keySet, err := v.getKeySet(ctx)
 	if err != nil {
 		slog.Warn("JWKS fetch failed", "error", err)
@@ -64,17 +91,17 @@ func (v *OIDCVerifier) Verify(ctx context.Context, token string, _ *http.Request
 	}
 
 	// Validate issuer.
-	if parsed.Issuer() != v.issuer {
+	if parsed.Issuer() != issuer {
 		slog.Warn("token issuer mismatch")
 		return nil, fmt.Errorf("%w: token validation failed", mcpauth.ErrInvalidToken)
 	}
 
 	// Validate audience if client ID is configured.
-	if v.clientID != "" {
+	if clientID != "" {
 		aud := parsed.Audience()
 		found := false
 		for _, a := range aud {
-			if a == v.clientID {
+			if a == clientID {
 				found = true
 				break
 			}
@@ -125,7 +152,11 @@ func (v *OIDCVerifier) refreshKeySet(ctx context.Context) (jwk.Set, error) {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 
-	keySet, err := jwk.Fetch(ctx, v.jwksURL)
+	var opts []jwk.FetchOption
+	if v.client != nil {
+		opts = append(opts, jwk.WithHTTPClient(v.client))
+	}
+	keySet, err := jwk.Fetch(ctx, v.jwksURL, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch key set: %w", err)
 	}
@@ -135,7 +166,20 @@ func (v *OIDCVerifier) refreshKeySet(ctx context.Context) (jwk.Set, error) {
 	return keySet, nil
 }
 
+// Preflight fetches the JWKS once at startup, so an unreachable or
+// misconfigured issuer fails fast instead of rejecting every token.
+func (v *OIDCVerifier) Preflight(ctx context.Context) error {
+	if _, err := v.refreshKeySet(ctx); err != nil {
+		v.mu.RLock()
+		defer v.mu.RUnlock()
+		return fmt.Errorf("PIDGR_AUTH_ISSUER: cannot fetch signing keys from %s: %w", v.jwksURL, err)
+	}
+	return nil
+}
+
 // Issuer returns the OIDC issuer URL.
 func (v *OIDCVerifier) Issuer() string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
 	return v.issuer
 }
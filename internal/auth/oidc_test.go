@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -113,6 +114,30 @@ func TestOIDCVerifier_ValidToken(t *testing.T) {
 	}
 }
 
+func TestOIDCVerifier_HTTPClient(t *testing.T) {
+	setup := newTestKeySetup(t)
+	defer setup.server.Close()
+
+	// The JWKS host only resolves through the client's proxy, which serves
+	// the key set for any request.
+	proxyURL, _ := url.Parse(setup.server.URL)
+	v := NewOIDCVerifier(testIssuer, "")
+	v.jwksURL = "http://jwks.pidgr.invalid/.well-known/jwks.json"
+	v.SetHTTPClient(&http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}})
+
+	token, err := jwt.NewBuilder().Issuer(v.issuer).Subject("user-123").Expiration(time.Now().Add(time.Hour)).Build()
+	if err != nil {
+		t.Fatalf("failed to build token: %v", err)
+	}
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.RS256, setup.jwkKey))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), string(signed), nil); err != nil {
+		t.Fatalf("Verify() error: %v", err)
+	}
+}
+
 func TestOIDCVerifier_ExpiredToken(t *testing.T) {
 	setup := newTestKeySetup(t)
 	defer setup.server.Close()
@@ -300,6 +325,47 @@ func TestOIDCVerifier_AudienceValidation(t *testing.T) {
 	})
 }
 
+func TestOIDCVerifier_SetIssuer(t *testing.T) {
+	setup := newTestKeySetup(t)
+	defer setup.server.Close()
+
+	v := NewOIDCVerifier(testIssuer, "old-client-id")
+	v.jwksURL = setup.server.URL
+
+	token, _ := jwt.NewBuilder().
+		Issuer(testIssuer).
+		Subject("user-123").
+		Audience([]string{"new-client-id"}).
+		Expiration(time.Now().Add(time.Hour)).
+		Build()
+	signed, _ := jwt.Sign(token, jwt.WithKey(jwa.RS256, setup.jwkKey))
+
+	if _, err := v.Verify(context.Background(), string(signed), nil); err == nil {
+		t.Fatal("expected error for the old client ID")
+	}
+
+	// Same issuer: the cached keys are kept.
+	v.SetIssuer(testIssuer, "new-client-id")
+	if _, err := v.Verify(context.Background(), string(signed), nil); err != nil {
+		t.Fatalf("Verify() error after SetIssuer: %v", err)
+	}
+	if !v.fetched {
+		t.Error("cached JWKS should be kept when the issuer is unchanged")
+	}
+
+	// New issuer: the old keys are dropped and fetched from the new issuer.
+	v.SetIssuer("https://auth.example.com/other-pool", "new-client-id")
+	if v.fetched || v.keySet != nil {
+		t.Error("cached JWKS should be dropped when the issuer changes")
+	}
+	if want := "https://auth.example.com/other-pool/.well-known/jwks.json"; v.jwksURL != want {
+		t.Errorf("jwksURL = %q, want %q", v.jwksURL, want)
+	}
+	if got := v.Issuer(); got != "https://auth.example.com/other-pool" {
+		t.Errorf("Issuer() = %q after SetIssuer", got)
+	}
+}
+
 func TestOIDCVerifier_JWKSCacheTTL(t *testing.T) {
 	setup := newTestKeySetup(t)
 	defer setup.server.Close()
@@ -365,3 +431,20 @@ func TestNewProtectedResourceMetadata(t *testing.T) {
 		t.Errorf("unexpected bearer methods: %v", metadata.BearerMethodsSupported)
 	}
 }
+
+func TestOIDCVerifier_Preflight(t *testing.T) {
+	setup := newTestKeySetup(t)
+	defer setup.server.Close()
+
+	v := NewOIDCVerifier(testIssuer, "")
+	v.jwksURL = setup.server.URL
+	if err := v.Preflight(context.Background()); err != nil {
+		t.Fatalf("Preflight() error: %v", err)
+	}
+
+	v.jwksURL = "http://localhost:1/nonexistent"
+	err := v.Preflight(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "PIDGR_AUTH_ISSUER") {
+		t.Errorf("expected an error naming PIDGR_AUTH_ISSUER, got %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+// Package configfile reads settings from config files. Settings are keyed by
+// environment variable name, and the environment overrides them.
+package configfile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// keyPattern matches setting names, which are environment variable names
+// such as PIDGR_API_URL.
+var keyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// Setting is one key and value read from a file.
+type Setting struct {
+	Key   string
+	Value string
+}
+
+// Settings holds the settings read from a config file, keyed by environment
+// variable name.
+type Settings map[string]string
+
+// Lookup returns the environment variable key or, when it is not set, the
+// file's setting for it, so environment variables override the file.
+func (s Settings) Lookup(key string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return s[key]
+}
+
+// ReadFile reads a config file into Settings.
+//
+// The file is a variant of a .env file: a flat list of settings keyed by
+// environment variable name, one per line, written KEY=value or KEY: value,
+// with optionally quoted values. Blank lines and # comments are ignored. It
+// is not structured YAML or TOML: sections and nesting are rejected.
+//
+// ReadFile leaves the environment unchanged, so a reloaded file can be read
+// and validated before any of it is applied.
+func ReadFile(path string) (Settings, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	parsed, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", path, err)
+	}
+	settings := make(Settings, len(parsed))
+	for _, setting := range parsed {
+		settings[setting.Key] = setting.Value
+	}
+	return settings, nil
+}
+
+// ReadFiles is like ReadFile for several files, such as a .env file and a
+// config file. A setting in more than one file takes its value from the
+// first; the environment still overrides them all. Empty paths are skipped.
+func ReadFiles(paths ...string) (Settings, error) {
+	settings := Settings{}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		s, err := ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range s {
+			if _, ok := settings[key]; !ok {
+				settings[key] = value
+			}
+		}
+	}
+	return settings, nil
+}
+
+// Parse reads settings in the env-file format described at ReadFile. As in
+// .env files, a line may start with "export ". Errors are prefixed with the
+// line number.
+func Parse(r io.Reader) ([]Setting, error) {
+	var settings []Setting
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") || raw[0] == ' ' || raw[0] == '\t' {
+			return nil, fmt.Errorf("%d: sections and nested values are not supported; the config file is an env file with one KEY=value or KEY: value per line", n)
+		}
+		line = strings.TrimPrefix(line, "export ")
+		sep := strings.IndexAny(line, ":=")
+		if sep < 0 {
+			return nil, fmt.Errorf("%d: expected KEY: value or KEY = value", n)
+		}
+		key := strings.TrimSpace(line[:sep])
+		if !keyPattern.MatchString(key) {
+			return nil, fmt.Errorf("%d: %q is not a setting name; use the environment variable name, e.g. PIDGR_API_URL", n, key)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%d: %s is set twice", n, key)
+		}
+		seen[key] = true
+		value, err := parseValue(strings.TrimSpace(line[sep+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("%d: %s: %w", n, key, err)
+		}
+		settings = append(settings, Setting{Key: key, Value: value})
+	}
+	return settings, scanner.Err()
+}
+
+// parseValue unquotes a double- or single-quoted value, or strips a
+// trailing comment from a bare one.
+func parseValue(v string) (string, error) {
+	switch {
+	case strings.HasPrefix(v, `"`):
+		end := closingQuote(v)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		if rest := strings.TrimSpace(v[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected text after string")
+		}
+		return strconv.Unquote(v[:end+1])
+	case strings.HasPrefix(v, "'"):
+		end := strings.LastIndex(v, "'")
+		if end == 0 {
+			return "", fmt.Errorf("unterminated string")
+		}
+		return strings.ReplaceAll(v[1:end], "''", "'"), nil
+	}
+	if i := strings.Index(v, " #"); i >= 0 {
+		v = strings.TrimSpace(v[:i])
+	}
+	return v, nil
+}
+
+// closingQuote returns the index of the quote ending the double-quoted
+// string at the start of v, or -1.
+func closingQuote(v string) int {
+	for i := 1; i < len(v); i++ {
+		switch v[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
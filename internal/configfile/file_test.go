@@ -0,0 +1,122 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package configfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	input := `# pidgr-mcp settings
+PIDGR_MCP_TRANSPORT: http
+PIDGR_API_URL: https://api.pidgr.com  # production
+PIDGR_AUTH_ISSUER = "https://auth.example.com/pool"
+PIDGR_MCP_REDACT = 'email,phone'
+PIDGR_MCP_DEBUG_ERRORS: "false # not a comment"
+PIDGR_MCP_ADDR: ':9090'
+
+PIDGR_MCP_NOTE: 'it''s quoted'
+PIDGR_MCP_EMPTY:
+`
+	settings, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := fmt.Sprint(settings)
+	want := "[{PIDGR_MCP_TRANSPORT http} {PIDGR_API_URL https://api.pidgr.com} {PIDGR_AUTH_ISSUER https://auth.example.com/pool} " +
+		"{PIDGR_MCP_REDACT email,phone} {PIDGR_MCP_DEBUG_ERRORS false # not a comment} {PIDGR_MCP_ADDR :9090} " +
+		"{PIDGR_MCP_NOTE it's quoted} {PIDGR_MCP_EMPTY }]"
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"[server]\nPIDGR_MCP_ADDR = ':80'", "1: sections"},
+		{"transport:\n  mode: http", "1: \"transport\" is not a setting name"},
+		{"PIDGR_MCP_ADDR: ':80'\n  nested: true", "2: sections and nested values"},
+		{"PIDGR_MCP_ADDR", "1: expected KEY: value"},
+		{"PIDGR_MCP_ADDR: 1\nPIDGR_MCP_ADDR: 2", "2: PIDGR_MCP_ADDR is set twice"},
+		{`PIDGR_API_URL = "https://x`, "1: PIDGR_API_URL: unterminated string"},
+		{`PIDGR_API_URL = "a" b`, "unexpected text after string"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(strings.NewReader(tt.input))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) = %v, want an error containing %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestReadFileEnvironmentWins(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pidgr-mcp.env")
+	if err := os.WriteFile(path, []byte("PIDGR_TEST_FROM_FILE: file\nPIDGR_TEST_OVERRIDDEN: file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PIDGR_TEST_OVERRIDDEN", "env")
+	t.Setenv("PIDGR_TEST_FROM_FILE", "")
+	_ = os.Unsetenv("PIDGR_TEST_FROM_FILE")
+
+	settings, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := settings.Lookup("PIDGR_TEST_FROM_FILE"); got != "file" {
+		t.Errorf("PIDGR_TEST_FROM_FILE = %q, want the file's value", got)
+	}
+	if got := settings.Lookup("PIDGR_TEST_OVERRIDDEN"); got != "env" {
+		t.Errorf("PIDGR_TEST_OVERRIDDEN = %q, want the environment to win", got)
+	}
+	if _, ok := os.LookupEnv("PIDGR_TEST_FROM_FILE"); ok {
+		t.Error("ReadFile should leave the environment unchanged")
+	}
+
+	if err := os.WriteFile(path, []byte("bad line\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadFile(path); err == nil || !strings.HasPrefix(err.Error(), path+":1:") {
+		t.Errorf("expected an error prefixed with %s:1:, got %v", path, err)
+	}
+}
+
+func TestReadFilesPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	dotenv, config := filepath.Join(dir, ".env"), filepath.Join(dir, "pidgr-mcp.yaml")
+	if err := os.WriteFile(dotenv, []byte("export PIDGR_TEST_DOTENV=dotenv\nPIDGR_TEST_BOTH=\"dotenv\"\nPIDGR_TEST_OVERRIDDEN=dotenv\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(config, []byte("PIDGR_TEST_BOTH: config\nPIDGR_TEST_CONFIG: config\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"PIDGR_TEST_DOTENV", "PIDGR_TEST_BOTH", "PIDGR_TEST_CONFIG"} {
+		t.Setenv(key, "")
+		_ = os.Unsetenv(key)
+	}
+	t.Setenv("PIDGR_TEST_OVERRIDDEN", "env")
+
+	settings, err := ReadFiles(dotenv, "", config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for key, want := range map[string]string{
+		"PIDGR_TEST_DOTENV":     "dotenv",
+		"PIDGR_TEST_BOTH":       "dotenv",
+		"PIDGR_TEST_CONFIG":     "config",
+		"PIDGR_TEST_OVERRIDDEN": "env",
+	} {
+		if got := settings.Lookup(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+	if _, ok := os.LookupEnv("PIDGR_TEST_DOTENV"); ok {
+		t.Error("ReadFiles should leave the environment unchanged")
+	}
+}
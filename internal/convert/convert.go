@@ -4,31 +4,158 @@
 package convert
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"connectrpc.com/connect"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
 )
 
-var marshaler = protojson.MarshalOptions{
-	EmitUnpopulated: false,
+// useProtoNames renders proto results with the snake_case field names from
+// the proto definitions instead of lowerCamel JSON names.
+var useProtoNames bool
+
+// SetUseProtoNames selects snake_case proto field names for results. It must
+// be called before the server starts handling requests.
+func SetUseProtoNames(enabled bool) {
+	useProtoNames = enabled
 }
 
-// ProtoResult serializes a proto message to JSON and wraps it in an MCP CallToolResult.
-func ProtoResult(msg proto.Message) (*mcp.CallToolResult, error) {
+// ProtoResult serializes a proto message to JSON and wraps it in an MCP
+// CallToolResult, honoring the result options carried by ctx.
+func ProtoResult(ctx context.Context, msg proto.Message) (*mcp.CallToolResult, error) {
+	opts := OptionsFrom(ctx)
+	marshaler := protojson.MarshalOptions{
+		EmitUnpopulated: opts.EmitUnpopulated,
+		UseProtoNames:   useProtoNames,
+	}
 	data, err := marshaler.Marshal(msg)
 	if err != nil {
 		return nil, fmt.Errorf("marshal proto response: %w", err)
 	}
-	return &mcp.CallToolResult{
+	return finishResult(ctx, data, msg.ProtoReflect().Descriptor())
+}
+
+// TypedResult serializes a tool's typed output struct to JSON and wraps it in
+// an MCP CallToolResult, honoring the result options carried by ctx like
+// ProtoResult does. Typed outputs are a stable contract: their JSON names are
+// fixed by struct tags, so PIDGR_MCP_PROTO_NAMES does not apply, and their
+// enum and timestamp values are rendered by the tool with EnumValue and
+// TimeValue.
+func TypedResult(ctx context.Context, v any) (*mcp.CallToolResult, error) {
+	data, err := marshalCompact(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal result: %w", err)
+	}
+	return finishResult(ctx, data, nil)
+}
+
+// finishResult applies the per-call result options, deployment rendering and
+// redaction to a serialized result. md describes proto results and is nil for
+// typed ones.
+func finishResult(ctx context.Context, data []byte, md protoreflect.MessageDescriptor) (*mcp.CallToolResult, error) {
+	opts := OptionsFrom(ctx)
+	var (
+		unknown []string
+		err     error
+	)
+	if len(opts.Fields) > 0 {
+		// Typed results keep their declared lowerCamel names.
+		if data, unknown, err = selectFields(data, opts.Fields, md != nil && useProtoNames); err != nil {
+			return nil, fmt.Errorf("select fields: %w", err)
+		}
+	}
+	if md != nil && (enumStyle != EnumsFull || renderTimes()) {
+		if data, err = renderFields(data, md); err != nil {
+			return nil, fmt.Errorf("render fields: %w", err)
+		}
+	}
+	if opts.SummaryOnly {
+		if data, err = summarizeList(data, md, len(opts.Fields) > 0); err != nil {
+			return nil, fmt.Errorf("summarize: %w", err)
+		}
+	}
+	data = redactJSON(data)
+
+	format := strings.ToLower(opts.OutputFormat)
+	var res *mcp.CallToolResult
+	if format == FormatNDJSON {
+		if res, err = ndjsonResult(data); err != nil {
+			return nil, fmt.Errorf("render ndjson: %w", err)
+		}
+	} else {
+		res = dataResult(data)
+	}
+
+	// Render the text in the requested format. Results cut mid-JSON by the
+	// size limit have no structured content and stay as raw text.
+	switch format {
+	case "", FormatJSON, FormatNDJSON:
+	case FormatMarkdown, "md", FormatYAML, "yml":
+		if raw, ok := res.StructuredContent.(json.RawMessage); ok {
+			if format == "md" {
+				format = FormatMarkdown
+			} else if format == "yml" {
+				format = FormatYAML
+			}
+			text, err := renderFormat(raw, format)
+			if err != nil {
+				return nil, fmt.Errorf("render %s: %w", format, err)
+			}
+			res.Content[0] = &mcp.TextContent{Text: text}
+		}
+	default:
+		return nil, fmt.Errorf("unknown output_format %q: use json, markdown, yaml, or ndjson", opts.OutputFormat)
+	}
+
+	if len(unknown) > 0 {
+		res.Content = append(res.Content, &mcp.TextContent{
+			Text: "Unknown fields ignored: " + strings.Join(unknown, ", "),
+		})
+	}
+	return res, nil
+}
+
+// JSONResult serializes a plain Go value to JSON and wraps it in an MCP
+// CallToolResult. Used by tools that compose several backend responses.
+func JSONResult(v any) (*mcp.CallToolResult, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal result: %w", err)
+	}
+	return dataResult(redactJSON(data)), nil
+}
+
+// dataResult wraps serialized JSON as both a text block, for clients that
+// only read Content, and StructuredContent, so structured-output-aware
+// clients can skip parsing the text. MCP requires structured content to be
+// an object, so arrays and scalars are returned as text only. Results over
+// the size limit are truncated, with a note explaining how to continue.
+func dataResult(data []byte) *mcp.CallToolResult {
+	var note string
+	valid := true
+	if maxResultBytes > 0 && len(data) > maxResultBytes {
+		data, note, valid = truncateJSON(data, maxResultBytes)
+	}
+	res := &mcp.CallToolResult{
 		Content: []mcp.Content{
 			&mcp.TextContent{Text: string(data)},
 		},
-	}, nil
+	}
+	if valid && len(data) > 0 && data[0] == '{' {
+		res.StructuredContent = json.RawMessage(data)
+	}
+	if note != "" {
+		res.Content = append(res.Content, &mcp.TextContent{Text: note})
+	}
+	return res
 }
 
 // genericMessage maps Connect error codes to safe, user-facing messages.
@@ -62,19 +189,47 @@ var detailCodes = map[connect.Code]bool{
 	connect.CodeUnauthenticated:    true,
 }
 
-// ErrorResult converts an error into an MCP error result with sanitized messages.
+// ErrorResult converts an error into an MCP error result with sanitized
+// messages. Its _meta says whether the call can be retried and after how many
+// seconds (see retryMeta).
 func ErrorResult(err error) (*mcp.CallToolResult, error) {
+	return &mcp.CallToolResult{
+		Meta:    retryMeta(err),
+		IsError: true,
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: ErrorMessage(err)},
+		},
+	}, nil
+}
+
+// userMessager is implemented by errors raised inside this server, such as
+// transport.CircuitOpenError, whose own explanation is safe and more useful
+// than the generic message for their code.
+type userMessager interface {
+	UserMessage() string
+}
+
+// ErrorMessage returns the sanitized, user-facing message for an error. It is
+// used directly by tools that report per-item failures inside a larger result.
+// Backend details kept in the message, such as "already exists" conflicts
+// that name an email address, are redacted like other results.
+func ErrorMessage(err error) string {
+	return redactString(errorMessage(err))
+}
+
+func errorMessage(err error) string {
 	if connect.IsNotModifiedError(err) {
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				&mcp.TextContent{Text: "Not modified"},
-			},
-		}, nil
+		return "Not modified"
 	}
 
+	reqID := requestID(err)
+	var um userMessager
+	if errors.As(err, &um) {
+		slog.Warn("backend error", "code", connect.CodeOf(err), "detail", err, "request_id", reqID)
+		return withRequestID(um.UserMessage(), reqID)
+	}
 	if code := connect.CodeOf(err); code != connect.CodeUnknown {
-		slog.Warn("backend error", "code", code, "detail", err)
+		slog.Warn("backend error", "code", code, "detail", err, "request_id", reqID)
 		msg := "Request failed"
 		if m, ok := genericMessage[code]; ok {
 			msg = m
@@ -84,21 +239,47 @@ func ErrorResult(err error) (*mcp.CallToolResult, error) {
 				msg = msg + ": " + detail
 			}
 		}
-		return &mcp.CallToolResult{
-			IsError: true,
-			Content: []mcp.Content{
-				&mcp.TextContent{Text: msg},
-			},
-		}, nil
+		if debugErrors {
+			msg += debugSuffix(code, err)
+		}
+		return withRequestID(msg, reqID)
 	}
 
-	slog.Warn("unexpected error", "detail", err)
-	return &mcp.CallToolResult{
-		IsError: true,
-		Content: []mcp.Content{
-			&mcp.TextContent{Text: "Request failed"},
-		},
-	}, nil
+	slog.Warn("unexpected error", "detail", err, "request_id", reqID)
+	msg := "Request failed"
+	if debugErrors {
+		if detail := redactDetail(err.Error()); detail != "" {
+			msg += " [detail=" + detail + "]"
+		}
+	}
+	return withRequestID(msg, reqID)
+}
+
+// requestIDHeaders are the backend response headers that identify a request,
+// in order of preference. They are safe to show users, who can quote them to
+// support.
+var requestIDHeaders = []string{"X-Request-Id", "X-Trace-Id"}
+
+// requestID returns the backend request ID carried by a Connect error's
+// response metadata, or "" if there is none.
+func requestID(err error) string {
+	var ce *connect.Error
+	if !errors.As(err, &ce) {
+		return ""
+	}
+	for _, h := range requestIDHeaders {
+		if id := strings.TrimSpace(ce.Meta().Get(h)); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+func withRequestID(msg, id string) string {
+	if id == "" {
+		return msg
+	}
+	return msg + " (request ID: " + id + ")"
 }
 
 // connectMessage extracts the user-facing message from a Connect error.
@@ -110,11 +291,62 @@ func connectMessage(err error) string {
 	return ""
 }
 
-// SuccessResult returns a simple success message for void responses.
+// SuccessResult returns a simple success message for void responses. The
+// text is redacted, since messages may echo input such as an email address.
 func SuccessResult(text string) *mcp.CallToolResult {
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
-			&mcp.TextContent{Text: text},
+			&mcp.TextContent{Text: redactString(text)},
+		},
+	}
+}
+
+// ResourceResult returns a short text summary together with an embedded text
+// resource (e.g. a CSV export), so large payloads are delivered as a document
+// instead of being inlined as JSON. The resource text is redacted like other
+// results.
+func ResourceResult(summary, uri, mimeType, text string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: summary},
+			&mcp.EmbeddedResource{
+				Resource: &mcp.ResourceContents{
+					URI:      uri,
+					MIMEType: mimeType,
+					Text:     redactText(mimeType, text),
+				},
+			},
 		},
 	}
 }
+
+// ResourceLinkResult returns a short text summary together with a link to a
+// resource the client reads separately, so the payload never enters the
+// conversation unless the client fetches it. size is the payload length in
+// bytes.
+func ResourceLinkResult(summary, uri, name, mimeType string, size int) *mcp.CallToolResult {
+	n := int64(size)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: summary},
+			&mcp.ResourceLink{
+				URI:      uri,
+				Name:     name,
+				MIMEType: mimeType,
+				Size:     &n,
+			},
+		},
+	}
+}
+
+// ReadResult returns the contents of a text resource read with
+// resources/read, redacted like tool results.
+func ReadResult(uri, mimeType, text string) *mcp.ReadResourceResult {
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{
+			URI:      uri,
+			MIMEType: mimeType,
+			Text:     redactText(mimeType, text),
+		}},
+	}
+}
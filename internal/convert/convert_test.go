@@ -4,12 +4,17 @@
 package convert
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"connectrpc.com/connect"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	pidgrv1 "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/sourcecontextpb"
 )
 
 func TestProtoResult(t *testing.T) {
@@ -19,7 +24,7 @@ func TestProtoResult(t *testing.T) {
 			Name: "Test Campaign",
 		},
 	}
-	result, err := ProtoResult(msg)
+	result, err := ProtoResult(context.Background(), msg)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -34,9 +39,30 @@ func TestProtoResult(t *testing.T) {
 	}
 }
 
+func TestProtoResultStructuredContent(t *testing.T) {
+	msg := &pidgrv1.GetCampaignResponse{
+		Campaign: &pidgrv1.Campaign{Id: "test-id"},
+	}
+	result, err := ProtoResult(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	raw, ok := result.StructuredContent.(json.RawMessage)
+	if !ok {
+		t.Fatalf("expected json.RawMessage structured content, got %T", result.StructuredContent)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("structured content is not a JSON object: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; text != string(raw) {
+		t.Errorf("text %q and structured content %q differ", text, raw)
+	}
+}
+
 func TestProtoResultEmpty(t *testing.T) {
 	msg := &pidgrv1.DeleteGroupResponse{}
-	result, err := ProtoResult(msg)
+	result, err := ProtoResult(context.Background(), msg)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -45,6 +71,36 @@ func TestProtoResultEmpty(t *testing.T) {
 	}
 }
 
+func TestJSONResult(t *testing.T) {
+	result, err := JSONResult(map[string]int{"count": 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatal("expected IsError to be false")
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if text != `{"count":3}` {
+		t.Errorf("expected %q, got %q", `{"count":3}`, text)
+	}
+}
+
+func TestJSONResultArrayHasNoStructuredContent(t *testing.T) {
+	result, err := JSONResult([]int{1, 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.StructuredContent != nil {
+		t.Errorf("expected no structured content for an array, got %v", result.StructuredContent)
+	}
+}
+
+func TestJSONResultUnsupported(t *testing.T) {
+	if _, err := JSONResult(make(chan int)); err == nil {
+		t.Fatal("expected error for unsupported value")
+	}
+}
+
 func TestErrorResultConnectNotFound(t *testing.T) {
 	err := connect.NewError(connect.CodeNotFound, fmt.Errorf("campaign not found"))
 	result, resultErr := ErrorResult(err)
@@ -132,6 +188,25 @@ func TestErrorResultDoesNotLeakServerDetails(t *testing.T) {
 	}
 }
 
+func TestErrorResultIncludesRequestID(t *testing.T) {
+	err := connect.NewError(connect.CodeInternal, fmt.Errorf("pq: deadlock detected"))
+	err.Meta().Set("X-Request-Id", "req-123")
+	result, _ := ErrorResult(err)
+	text := result.Content[0].(*mcp.TextContent).Text
+	if text != "Internal error (request ID: req-123)" {
+		t.Errorf("expected sanitized message with request ID, got %q", text)
+	}
+}
+
+func TestErrorMessageTraceIDFallback(t *testing.T) {
+	err := connect.NewError(connect.CodeNotFound, fmt.Errorf("campaign not found"))
+	err.Meta().Set("X-Trace-Id", "4bf92f3577b34da6")
+	want := "Not found: campaign not found (request ID: 4bf92f3577b34da6)"
+	if got := ErrorMessage(err); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
 func TestSuccessResult(t *testing.T) {
 	result := SuccessResult("deleted successfully")
 	if result == nil {
@@ -144,3 +219,72 @@ func TestSuccessResult(t *testing.T) {
 		t.Fatalf("expected 1 content item, got %d", len(result.Content))
 	}
 }
+
+func TestResourceResult(t *testing.T) {
+	result := ResourceResult("2 rows", "pidgr://export.csv", "text/csv", "a,b\n1,2\n")
+	if result.IsError {
+		t.Fatal("expected IsError to be false")
+	}
+	if len(result.Content) != 2 {
+		t.Fatalf("expected 2 content items, got %d", len(result.Content))
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; text != "2 rows" {
+		t.Errorf("expected summary %q, got %q", "2 rows", text)
+	}
+	res := result.Content[1].(*mcp.EmbeddedResource).Resource
+	if res.URI != "pidgr://export.csv" || res.MIMEType != "text/csv" || res.Text != "a,b\n1,2\n" {
+		t.Errorf("unexpected resource: %+v", res)
+	}
+}
+
+func TestResourceLinkResult(t *testing.T) {
+	result := ResourceLinkResult("2 rows", "pidgr://export.csv", "export.csv", "text/csv", 8)
+	if len(result.Content) != 2 {
+		t.Fatalf("expected 2 content items, got %d", len(result.Content))
+	}
+	link, ok := result.Content[1].(*mcp.ResourceLink)
+	if !ok {
+		t.Fatalf("expected a resource link, got %T", result.Content[1])
+	}
+	if link.URI != "pidgr://export.csv" || link.Name != "export.csv" || link.MIMEType != "text/csv" || link.Size == nil || *link.Size != 8 {
+		t.Errorf("unexpected link: %+v", link)
+	}
+}
+
+func TestReadResult(t *testing.T) {
+	result := ReadResult("pidgr://export.csv", "text/csv", "a,b\n1,2\n")
+	if len(result.Contents) != 1 {
+		t.Fatalf("expected 1 resource, got %d", len(result.Contents))
+	}
+	res := result.Contents[0]
+	if res.URI != "pidgr://export.csv" || res.MIMEType != "text/csv" || res.Text != "a,b\n1,2\n" {
+		t.Errorf("unexpected resource: %+v", res)
+	}
+}
+
+func TestProtoResultUseProtoNames(t *testing.T) {
+	SetUseProtoNames(true)
+	defer SetUseProtoNames(false)
+
+	msg := &apipb.Api{Name: "pidgr.v1.CampaignService", SourceContext: &sourcecontextpb.SourceContext{FileName: "campaign.proto"}}
+	result, err := ProtoResult(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, `"source_context":{"file_name":"campaign.proto"}`) {
+		t.Errorf("expected proto field names, got %s", text)
+	}
+}
+
+type userFacingError struct{}
+
+func (userFacingError) Error() string       { return "circuit open for pidgr.v1.HeatmapService" }
+func (userFacingError) UserMessage() string { return "The heatmap service is temporarily unavailable" }
+
+func TestErrorMessageUserMessage(t *testing.T) {
+	err := connect.NewError(connect.CodeUnavailable, userFacingError{})
+	if got := ErrorMessage(err); got != "The heatmap service is temporarily unavailable" {
+		t.Errorf("expected the error's own message, got %q", got)
+	}
+}
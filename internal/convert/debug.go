@@ -0,0 +1,70 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"connectrpc.com/connect"
+)
+
+// debugErrors appends the error code and a redacted backend detail to error
+// results. It is off by default; see SetDebugErrors.
+var debugErrors bool
+
+// maxDebugDetail caps the backend detail shown in debug mode.
+const maxDebugDetail = 200
+
+// SetDebugErrors enables or disables verbose error results. It must be
+// called before the server starts handling requests.
+func SetDebugErrors(enabled bool) {
+	debugErrors = enabled
+}
+
+// debugRedactions strip infrastructure details that must never reach users,
+// even in debug mode.
+var debugRedactions = []struct {
+	re   *regexp.Regexp
+	with string
+}{
+	{regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://\S+`), "<url>"},
+	{regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), "<addr>"},
+	{regexp.MustCompile(`\[?[0-9a-fA-F]*:[0-9a-fA-F:]*:[0-9a-fA-F]+\]?(:\d+)?`), "<addr>"},
+	{regexp.MustCompile(`\b[a-zA-Z0-9-]+(\.[a-zA-Z0-9-]+)+:\d+\b`), "<addr>"},
+	{regexp.MustCompile(`(^|\s)(/[\w.-]+){2,}`), "$1<path>"},
+}
+
+// debugSuffix describes a backend error for debug mode: its code, its
+// redacted message when the code is otherwise sanitized, and the types of
+// any structured error details.
+func debugSuffix(code connect.Code, err error) string {
+	parts := []string{"code=" + code.String()}
+	if !detailCodes[code] {
+		if detail := redactDetail(connectMessage(err)); detail != "" {
+			parts = append(parts, "detail="+detail)
+		}
+	}
+	var ce *connect.Error
+	if errors.As(err, &ce) {
+		for _, d := range ce.Details() {
+			parts = append(parts, "type="+d.Type())
+		}
+	}
+	return " [" + strings.Join(parts, "; ") + "]"
+}
+
+// redactDetail removes URLs, addresses, and file paths from an error message
+// and shortens it to maxDebugDetail runes.
+func redactDetail(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	for _, r := range debugRedactions {
+		s = r.re.ReplaceAllString(s, r.with)
+	}
+	if runes := []rune(s); len(runes) > maxDebugDetail {
+		s = string(runes[:maxDebugDetail]) + "…"
+	}
+	return s
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+)
+
+func TestErrorMessageDebugOffByDefault(t *testing.T) {
+	err := connect.NewError(connect.CodeInternal, fmt.Errorf("pq: connection refused to 10.0.1.50:5432"))
+	if got := ErrorMessage(err); got != "Internal error" {
+		t.Errorf("expected plain sanitized message, got %q", got)
+	}
+}
+
+func TestErrorMessageDebug(t *testing.T) {
+	SetDebugErrors(true)
+	defer SetDebugErrors(false)
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "server error is redacted",
+			err:  connect.NewError(connect.CodeInternal, fmt.Errorf("pq: connection refused to 10.0.1.50:5432")),
+			want: "Internal error [code=internal; detail=pq: connection refused to <addr>]",
+		},
+		{
+			name: "client error detail is not repeated",
+			err:  connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("name too long")),
+			want: "Invalid input: name too long [code=invalid_argument]",
+		},
+		{
+			name: "hosts, URLs and paths",
+			err:  connect.NewError(connect.CodeUnavailable, fmt.Errorf("dial db.internal.pidgr.com:5432 via https://10.0.0.1/x failed reading /etc/pidgr/config.yaml")),
+			want: "Service unavailable [code=unavailable; detail=dial <addr> via <url> failed reading <path>]",
+		},
+		{
+			name: "local error",
+			err:  fmt.Errorf("marshal result: unsupported type"),
+			want: "Request failed [detail=marshal result: unsupported type]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorMessage(tt.err); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRedactDetailTruncates(t *testing.T) {
+	got := redactDetail(strings.Repeat("a", maxDebugDetail+50))
+	if len([]rune(got)) != maxDebugDetail+1 || !strings.HasSuffix(got, "…") {
+		t.Errorf("expected truncation to %d runes plus ellipsis, got %d", maxDebugDetail, len([]rune(got)))
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Enum styles for proto results.
+const (
+	// EnumsFull keeps enum values as declared, e.g. DELIVERY_STATUS_DELIVERED.
+	EnumsFull = "full"
+	// EnumsShort strips the enum type prefix, e.g. DELIVERED. Tools accept
+	// both forms as input, so short values can be passed straight back.
+	EnumsShort = "short"
+	// EnumsLabeled strips the prefix and adds a human-readable companion
+	// field, e.g. "statusLabel": "Delivered".
+	EnumsLabeled = "label"
+)
+
+// enumStyle controls how enum values are rendered in proto results.
+var enumStyle = EnumsShort
+
+// ValidateEnumStyle reports whether style names an enum style, without
+// changing the current one.
+func ValidateEnumStyle(style string) error {
+	switch style {
+	case EnumsFull, EnumsShort, EnumsLabeled:
+		return nil
+	}
+	return fmt.Errorf("unknown enum style %q: use %s, %s, or %s", style, EnumsFull, EnumsShort, EnumsLabeled)
+}
+
+// SetEnumStyle sets how enum values are rendered in proto results. It must be
+// called before the server starts handling requests.
+func SetEnumStyle(style string) error {
+	if err := ValidateEnumStyle(style); err != nil {
+		return err
+	}
+	enumStyle = style
+	return nil
+}
+
+// EnumValue renders an enum value name for a typed result in the configured
+// enum style, stripping prefix (e.g. "CAMPAIGN_STATUS_") unless the style
+// is full. Typed results have no label companions.
+func EnumValue(name, prefix string) string {
+	if enumStyle == EnumsFull {
+		return name
+	}
+	if short := strings.TrimPrefix(name, prefix); short != "" {
+		return short
+	}
+	return name
+}
+
+// shortEnum strips the enum type prefix from a value name, so
+// DELIVERY_STATUS_DELIVERED becomes DELIVERED. Unknown values, which
+// protojson renders as numbers, are returned unchanged.
+func shortEnum(v any, ed protoreflect.EnumDescriptor) any {
+	s, ok := v.(string)
+	if !ok || enumStyle == EnumsFull {
+		return v
+	}
+	if short := strings.TrimPrefix(s, enumPrefix(ed)); short != "" {
+		return short
+	}
+	return s
+}
+
+// enumPrefix returns the UPPER_SNAKE form of the enum's name plus an
+// underscore, the prefix its values are declared with by convention.
+func enumPrefix(ed protoreflect.EnumDescriptor) string {
+	name := []rune(string(ed.Name()))
+	var b strings.Builder
+	for i, r := range name {
+		upper := r >= 'A' && r <= 'Z'
+		if upper && i > 0 {
+			prev := name[i-1]
+			nextLower := i+1 < len(name) && name[i+1] >= 'a' && name[i+1] <= 'z'
+			if (prev >= 'a' && prev <= 'z') || (prev >= '0' && prev <= '9') || (prev >= 'A' && prev <= 'Z' && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteString(strings.ToUpper(string(r)))
+	}
+	return b.String() + "_"
+}
+
+// enumLabel turns a short enum value into a sentence-case label, e.g.
+// RAGE_TAP becomes "Rage tap".
+func enumLabel(v any) any {
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return v
+	}
+	s = strings.ToLower(strings.ReplaceAll(s, "_", " "))
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+func enumResult(t *testing.T, style string) string {
+	t.Helper()
+	if err := SetEnumStyle(style); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = SetEnumStyle(EnumsShort) })
+
+	msg := &apipb.Api{
+		Name:    "pidgr.v1.CampaignService",
+		Syntax:  typepb.Syntax_SYNTAX_PROTO3,
+		Methods: []*apipb.Method{{Name: "ListCampaigns", Syntax: typepb.Syntax_SYNTAX_EDITIONS}},
+	}
+	result, err := ProtoResult(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return result.Content[0].(*mcp.TextContent).Text
+}
+
+func TestProtoResultEnumStyles(t *testing.T) {
+	tests := []struct {
+		style string
+		want  string
+	}{
+		{EnumsFull, `{"name":"pidgr.v1.CampaignService","methods":[{"name":"ListCampaigns","syntax":"SYNTAX_EDITIONS"}],"syntax":"SYNTAX_PROTO3"}`},
+		{EnumsShort, `{"name":"pidgr.v1.CampaignService","methods":[{"name":"ListCampaigns","syntax":"EDITIONS"}],"syntax":"PROTO3"}`},
+		{EnumsLabeled, `{"name":"pidgr.v1.CampaignService","methods":[{"name":"ListCampaigns","syntax":"EDITIONS","syntaxLabel":"Editions"}],"syntax":"PROTO3","syntaxLabel":"Proto3"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			got := enumResult(t, tt.style)
+			if !reflect.DeepEqual(decodeJSON(t, []byte(got)), decodeJSON(t, []byte(tt.want))) {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEnumPrefix(t *testing.T) {
+	tests := []struct {
+		ed   protoreflect.EnumDescriptor
+		want string
+	}{
+		{typepb.Syntax(0).Descriptor(), "SYNTAX_"},
+		{typepb.Field_Cardinality(0).Descriptor(), "CARDINALITY_"},
+		{structpb.NullValue(0).Descriptor(), "NULL_VALUE_"},
+	}
+	for _, tt := range tests {
+		if got := enumPrefix(tt.ed); got != tt.want {
+			t.Errorf("enumPrefix(%s) = %q, want %q", tt.ed.FullName(), got, tt.want)
+		}
+	}
+}
+
+func TestShortEnumKeepsUnprefixedValues(t *testing.T) {
+	// Field.Kind values are declared as TYPE_*, not KIND_*.
+	if got := shortEnum("TYPE_STRING", typepb.Field_Kind(0).Descriptor()); got != "TYPE_STRING" {
+		t.Errorf("expected value without the enum prefix to be unchanged, got %v", got)
+	}
+	if got := shortEnum("CARDINALITY_REPEATED", typepb.Field_Cardinality(0).Descriptor()); got != "REPEATED" {
+		t.Errorf("expected REPEATED, got %v", got)
+	}
+}
+
+func TestEnumLabel(t *testing.T) {
+	if got := enumLabel("RAGE_TAP"); got != "Rage tap" {
+		t.Errorf("expected \"Rage tap\", got %v", got)
+	}
+}
+
+func TestSetEnumStyleRejectsUnknown(t *testing.T) {
+	if err := SetEnumStyle("fancy"); err == nil {
+		t.Error("expected an error for an unknown style")
+	}
+}
+
+func TestEnumValue(t *testing.T) {
+	if got := EnumValue("CAMPAIGN_STATUS_RUNNING", "CAMPAIGN_STATUS_"); got != "RUNNING" {
+		t.Errorf("expected RUNNING, got %q", got)
+	}
+	if err := SetEnumStyle(EnumsFull); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = SetEnumStyle(EnumsShort) }()
+	if got := EnumValue("CAMPAIGN_STATUS_RUNNING", "CAMPAIGN_STATUS_"); got != "CAMPAIGN_STATUS_RUNNING" {
+		t.Errorf("expected the full name, got %q", got)
+	}
+}
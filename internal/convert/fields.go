@@ -0,0 +1,175 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// fieldTree is a set of field paths. A nil subtree keeps the whole value.
+type fieldTree map[string]fieldTree
+
+func (t fieldTree) add(path []string) {
+	for i, seg := range path {
+		sub, ok := t[seg]
+		if ok && sub == nil {
+			return
+		}
+		if i == len(path)-1 {
+			t[seg] = nil
+			return
+		}
+		if !ok {
+			sub = fieldTree{}
+			t[seg] = sub
+		}
+		t = sub
+	}
+}
+
+// selectFields keeps only the requested fields of a JSON object result. Each
+// field is a dot-separated path; snake_case and lowerCamel segments both match
+// the naming used in results (see fieldPath). A path that does not start at a
+// top-level key is applied inside each top-level object or list instead, so
+// list results can be trimmed with item fields like "id" rather than
+// "campaigns.id". Lists are transparent to paths, and pagination metadata is
+// always kept. protoNames selects snake_case result names. It returns the
+// filtered JSON and the fields that matched nothing.
+func selectFields(data []byte, fields []string, protoNames bool) ([]byte, []string, error) {
+	var obj map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&obj); err != nil {
+		return nil, nil, err
+	}
+
+	pageKey := paginationKey(obj)
+	tree := fieldTree{}
+	var unknown []string
+	for _, field := range fields {
+		path := fieldPath(field, protoNames)
+		if len(path) == 0 {
+			continue
+		}
+		matched := false
+		if hasPath(obj, path) {
+			tree.add(path)
+			matched = true
+		} else {
+			for key, v := range obj {
+				if key == pageKey {
+					continue
+				}
+				switch v.(type) {
+				case map[string]any, []any:
+					if hasPath(v, path) {
+						tree.add(append([]string{key}, path...))
+						matched = true
+					}
+				}
+			}
+		}
+		if !matched {
+			unknown = append(unknown, field)
+		}
+	}
+	if pageKey != "" {
+		tree.add([]string{pageKey})
+	}
+
+	out, err := marshalCompact(filterFields(obj, tree))
+	return out, unknown, err
+}
+
+// fieldPath splits a field into path segments named the way results name
+// them: lowerCamel, or snake_case when results use proto names.
+func fieldPath(field string, protoNames bool) []string {
+	var path []string
+	for _, seg := range strings.Split(strings.TrimSpace(field), ".") {
+		if seg == "" {
+			continue
+		}
+		if protoNames {
+			path = append(path, snakeCase(seg))
+		} else {
+			path = append(path, camelCase(seg))
+		}
+	}
+	return path
+}
+
+// camelCase converts a snake_case name to lowerCamel.
+func camelCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// snakeCase converts a lowerCamel name to snake_case.
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// hasPath reports whether path exists in v. Lists match if any element
+// matches; empty lists match anything, since their items cannot be checked.
+func hasPath(v any, path []string) bool {
+	if len(path) == 0 {
+		return true
+	}
+	switch x := v.(type) {
+	case map[string]any:
+		child, ok := x[path[0]]
+		return ok && hasPath(child, path[1:])
+	case []any:
+		if len(x) == 0 {
+			return true
+		}
+		for _, item := range x {
+			if hasPath(item, path) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// filterFields returns v reduced to the paths in tree.
+func filterFields(v any, tree fieldTree) any {
+	if tree == nil {
+		return v
+	}
+	switch x := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(tree))
+		for key, sub := range tree {
+			if child, ok := x[key]; ok {
+				out[key] = filterFields(child, sub)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i, item := range x {
+			out[i] = filterFields(item, tree)
+		}
+		return out
+	}
+	return v
+}
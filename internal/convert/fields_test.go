@@ -0,0 +1,107 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	pidgrv1 "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/apipb"
+)
+
+func decodeJSON(t *testing.T, data []byte) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Fatalf("invalid JSON %s: %v", data, err)
+	}
+	return v
+}
+
+func TestSelectFieldsListItems(t *testing.T) {
+	resp := &pidgrv1.ListCampaignsResponse{
+		Campaigns: []*pidgrv1.Campaign{
+			{Id: "a", Name: "A", SenderName: "HR", Status: pidgrv1.CampaignStatus_CAMPAIGN_STATUS_RUNNING},
+			{Id: "b", Name: "B", SenderName: "IT"},
+		},
+		PaginationMeta: &pidgrv1.PaginationMeta{NextPageToken: "t"},
+	}
+	tests := []struct {
+		name       string
+		protoNames bool
+		want       string
+	}{
+		{"camel", false, `{"campaigns":[{"id":"a","senderName":"HR"},{"id":"b","senderName":"IT"}],"paginationMeta":{"nextPageToken":"t"}}`},
+		{"proto names", true, `{"campaigns":[{"id":"a","sender_name":"HR"},{"id":"b","sender_name":"IT"}],"pagination_meta":{"next_page_token":"t"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := protojson.MarshalOptions{UseProtoNames: tt.protoNames}.Marshal(resp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, unknown, err := selectFields(data, []string{"id", "sender_name"}, tt.protoNames)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(unknown) != 0 {
+				t.Errorf("unexpected unknown fields %v", unknown)
+			}
+			if got := decodeJSON(t, out); !reflect.DeepEqual(got, decodeJSON(t, []byte(tt.want))) {
+				t.Errorf("got %s, want %s", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectFieldsNestedPaths(t *testing.T) {
+	data := []byte(`{"campaign":{"id":"a","workflow":{"steps":[{"id":"s1","type":"SEND"}]},"name":"A"}}`)
+
+	out, unknown, err := selectFields(data, []string{"campaign.workflow.steps.id", "name", "bogus"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := decodeJSON(t, []byte(`{"campaign":{"name":"A","workflow":{"steps":[{"id":"s1"}]}}}`))
+	if got := decodeJSON(t, out); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %s", out)
+	}
+	if !reflect.DeepEqual(unknown, []string{"bogus"}) {
+		t.Errorf("unknown = %v, want [bogus]", unknown)
+	}
+}
+
+func TestSelectFieldsEmptyList(t *testing.T) {
+	out, unknown, err := selectFields([]byte(`{"users":[]}`), []string{"email"}, false)
+	if err != nil || len(unknown) != 0 || string(out) != `{"users":[]}` {
+		t.Errorf("got %s unknown=%v err=%v", out, unknown, err)
+	}
+}
+
+func TestProtoResultFields(t *testing.T) {
+	ctx := WithOptions(context.Background(), Options{Fields: []string{"name", "nope"}})
+	result, err := ProtoResult(ctx, &apipb.Api{Name: "pidgr.v1.CampaignService", Version: "v1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; text != `{"name":"pidgr.v1.CampaignService"}` {
+		t.Errorf("unexpected filtered result %s", text)
+	}
+	if len(result.Content) != 2 || result.Content[1].(*mcp.TextContent).Text != "Unknown fields ignored: nope" {
+		t.Errorf("expected an unknown-fields note, got %d content items", len(result.Content))
+	}
+}
+
+func TestFieldPathProtoNames(t *testing.T) {
+	if got := fieldPath("sender_name.firstName", false); !reflect.DeepEqual(got, []string{"senderName", "firstName"}) {
+		t.Errorf("camel path = %v", got)
+	}
+	if got := fieldPath("sender_name.firstName", true); !reflect.DeepEqual(got, []string{"sender_name", "first_name"}) {
+		t.Errorf("proto name path = %v", got)
+	}
+}
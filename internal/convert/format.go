@@ -0,0 +1,371 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Output formats for the text content of proto results. Structured content
+// is always JSON.
+const (
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+	FormatYAML     = "yaml"
+	FormatNDJSON   = "ndjson"
+)
+
+// object is a decoded JSON object that remembers its key order, so rendered
+// output lists fields in the order the API returned them.
+type object struct {
+	keys   []string
+	values map[string]any
+}
+
+// decodeOrdered decodes JSON like json.Unmarshal with UseNumber, but returns
+// objects as *object to preserve key order.
+func decodeOrdered(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return decodeValue(dec)
+}
+
+func decodeValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := &object{values: map[string]any{}}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := keyTok.(string)
+			v, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := obj.values[key]; !dup {
+				obj.keys = append(obj.keys, key)
+			}
+			obj.values[key] = v
+		}
+		_, err := dec.Token()
+		return obj, err
+	case json.Delim('['):
+		items := []any{}
+		for dec.More() {
+			v, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		_, err := dec.Token()
+		return items, err
+	}
+	return tok, nil
+}
+
+// renderFormat renders a JSON object result in the given output format.
+func renderFormat(data []byte, format string) (string, error) {
+	v, err := decodeOrdered(data)
+	if err != nil {
+		return "", err
+	}
+	switch format {
+	case FormatYAML:
+		var b strings.Builder
+		writeYAML(&b, v, 0)
+		return b.String(), nil
+	case FormatMarkdown:
+		obj, ok := v.(*object)
+		if !ok {
+			return "", fmt.Errorf("markdown output requires an object result")
+		}
+		return renderMarkdown(obj), nil
+	}
+	return "", fmt.Errorf("unknown output format %q", format)
+}
+
+// ── YAML ────────────────────────────────────────────────────────────────────
+
+func writeYAML(b *strings.Builder, v any, indent int) {
+	pad := strings.Repeat(" ", indent)
+	switch x := v.(type) {
+	case *object:
+		if len(x.keys) == 0 {
+			b.WriteString(pad + "{}\n")
+			return
+		}
+		for _, key := range x.keys {
+			b.WriteString(pad + yamlScalar(key) + ":")
+			writeYAMLChild(b, x.values[key], indent)
+		}
+	case []any:
+		if len(x) == 0 {
+			b.WriteString(pad + "[]\n")
+			return
+		}
+		for _, item := range x {
+			if isContainer(item) && !isEmptyContainer(item) {
+				// Render the item one level deeper, then hang its first line off "- ".
+				var child strings.Builder
+				writeYAML(&child, item, indent+2)
+				b.WriteString(pad + "- " + child.String()[indent+2:])
+				continue
+			}
+			b.WriteString(pad + "-")
+			writeYAMLChild(b, item, indent)
+		}
+	default:
+		b.WriteString(pad + yamlScalar(v) + "\n")
+	}
+}
+
+// writeYAMLChild writes the value after "key:" or "-": inline for scalars and
+// empty containers, on the following lines otherwise.
+func writeYAMLChild(b *strings.Builder, v any, indent int) {
+	switch {
+	case isEmptyContainer(v):
+		if _, ok := v.([]any); ok {
+			b.WriteString(" []\n")
+		} else {
+			b.WriteString(" {}\n")
+		}
+	case isContainer(v):
+		b.WriteString("\n")
+		writeYAML(b, v, indent+2)
+	default:
+		b.WriteString(" " + yamlScalar(v) + "\n")
+	}
+}
+
+// yamlScalar formats a scalar, double-quoting strings that YAML would
+// otherwise read as another type or misparse.
+func yamlScalar(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(x)
+	case json.Number:
+		return x.String()
+	case string:
+		if yamlNeedsQuotes(x) {
+			return strconv.Quote(x)
+		}
+		return x
+	}
+	return fmt.Sprint(v)
+}
+
+func yamlNeedsQuotes(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~":
+		return true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return true
+	}
+	return strings.ContainsAny(s, "\n\t") || strings.Contains(s, ": ") || strings.Contains(s, " #")
+}
+
+func isContainer(v any) bool {
+	switch v.(type) {
+	case *object, []any:
+		return true
+	}
+	return false
+}
+
+func isEmptyContainer(v any) bool {
+	switch x := v.(type) {
+	case *object:
+		return len(x.keys) == 0
+	case []any:
+		return len(x) == 0
+	}
+	return false
+}
+
+// ── Markdown ────────────────────────────────────────────────────────────────
+
+// renderMarkdown renders the result's main list as a table, with one row per
+// item and nested fields flattened into dotted columns. Remaining top-level
+// fields, or the whole result if it has no list, are shown as a two-column
+// field/value table.
+func renderMarkdown(obj *object) string {
+	var b strings.Builder
+	listKey := mainList(obj)
+
+	if listKey != "" {
+		items := obj.values[listKey].([]any)
+		var columns []string
+		seen := map[string]bool{}
+		rows := make([]map[string]string, len(items))
+		for i, item := range items {
+			rows[i] = map[string]string{}
+			flatten(item, "", func(path, value string) {
+				if !seen[path] {
+					seen[path] = true
+					columns = append(columns, path)
+				}
+				rows[i][path] = value
+			})
+		}
+		fmt.Fprintf(&b, "**%s** (%d)\n\n", listKey, len(items))
+		b.WriteString("| " + strings.Join(columns, " | ") + " |\n")
+		b.WriteString("|" + strings.Repeat(" --- |", len(columns)) + "\n")
+		for _, row := range rows {
+			cells := make([]string, len(columns))
+			for i, col := range columns {
+				cells[i] = markdownCell(row[col])
+			}
+			b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+	}
+
+	rest := &object{values: obj.values}
+	for _, key := range obj.keys {
+		if key != listKey {
+			rest.keys = append(rest.keys, key)
+		}
+	}
+	if len(rest.keys) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("| Field | Value |\n| --- | --- |\n")
+		flatten(rest, "", func(path, value string) {
+			b.WriteString("| " + markdownCell(path) + " | " + markdownCell(value) + " |\n")
+		})
+	}
+	return b.String()
+}
+
+// mainList returns the key of the result's longest non-empty list of
+// objects, or "" if it has none.
+func mainList(obj *object) string {
+	listKey := ""
+	for _, key := range obj.keys {
+		items, ok := obj.values[key].([]any)
+		if !ok || len(items) == 0 {
+			continue
+		}
+		if _, ok := items[0].(*object); ok && (listKey == "" || len(items) > len(obj.values[listKey].([]any))) {
+			listKey = key
+		}
+	}
+	return listKey
+}
+
+// listField is like mainList, but falls back to the first list field so an
+// empty page still reports its list.
+func listField(obj *object) string {
+	if key := mainList(obj); key != "" {
+		return key
+	}
+	for _, key := range obj.keys {
+		if _, ok := obj.values[key].([]any); ok {
+			return key
+		}
+	}
+	return ""
+}
+
+// flatten calls emit for each leaf of v with its dotted path. Lists of
+// scalars are joined with commas; lists of objects are emitted as JSON.
+func flatten(v any, prefix string, emit func(path, value string)) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+	switch x := v.(type) {
+	case *object:
+		for _, key := range x.keys {
+			flatten(x.values[key], join(key), emit)
+		}
+	case []any:
+		parts := make([]string, len(x))
+		for i, item := range x {
+			if isContainer(item) {
+				emit(prefix, compactJSON(v))
+				return
+			}
+			parts[i] = yamlScalar(item)
+			if s, ok := item.(string); ok {
+				parts[i] = s
+			}
+		}
+		emit(prefix, strings.Join(parts, ", "))
+	case string:
+		emit(prefix, x)
+	default:
+		emit(prefix, yamlScalar(v))
+	}
+}
+
+// compactJSON re-encodes an ordered value as compact JSON.
+func compactJSON(v any) string {
+	var b strings.Builder
+	var write func(v any)
+	write = func(v any) {
+		switch x := v.(type) {
+		case *object:
+			b.WriteString("{")
+			for i, key := range x.keys {
+				if i > 0 {
+					b.WriteString(",")
+				}
+				b.WriteString(jsonString(key) + ":")
+				write(x.values[key])
+			}
+			b.WriteString("}")
+		case []any:
+			b.WriteString("[")
+			for i, item := range x {
+				if i > 0 {
+					b.WriteString(",")
+				}
+				write(item)
+			}
+			b.WriteString("]")
+		case string:
+			b.WriteString(jsonString(x))
+		default:
+			b.WriteString(yamlScalar(x))
+		}
+	}
+	write(v)
+	return b.String()
+}
+
+// jsonString encodes s as a JSON string literal.
+func jsonString(s string) string {
+	out, _ := marshalCompact(s)
+	return string(out)
+}
+
+// markdownCell escapes a value for use inside a Markdown table cell.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\r\n", "<br>")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/types/known/apipb"
+)
+
+func TestRenderYAML(t *testing.T) {
+	data := []byte(`{"name":"Launch","status":"ACTIVE","count":"12","tags":[],"audience":[{"userId":"u1","variables":{"first":"Ana"}},{"userId":"u2"}],"note":"a: b","empty":""}`)
+
+	got, err := renderFormat(data, FormatYAML)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `name: Launch
+status: ACTIVE
+count: "12"
+tags: []
+audience:
+  - userId: u1
+    variables:
+      first: Ana
+  - userId: u2
+note: "a: b"
+empty: ""
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderMarkdownList(t *testing.T) {
+	data := []byte(`{"users":[{"id":"u1","email":"a@x.com","profile":{"department":"Sales"}},{"id":"u2","email":"b|c@x.com","roles":["admin","editor"]}],"pagination":{"nextPageToken":"t1"}}`)
+
+	got, err := renderFormat(data, FormatMarkdown)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `**users** (2)
+
+| id | email | profile.department | roles |
+| --- | --- | --- | --- |
+| u1 | a@x.com | Sales |  |
+| u2 | b\|c@x.com |  | admin, editor |
+
+| Field | Value |
+| --- | --- |
+| pagination.nextPageToken | t1 |
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderMarkdownObject(t *testing.T) {
+	got, err := renderFormat([]byte(`{"campaign":{"id":"c1","title":"Line one\nline two"}}`), FormatMarkdown)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(got, "| campaign.title | Line one<br>line two |") {
+		t.Errorf("unexpected markdown:\n%s", got)
+	}
+}
+
+func TestProtoResultOutputFormat(t *testing.T) {
+	msg := &apipb.Api{Name: "pidgr.v1.CampaignService", Version: "v1"}
+
+	ctx := WithOptions(context.Background(), Options{OutputFormat: "YAML"})
+	result, err := ProtoResult(ctx, msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; text != "name: pidgr.v1.CampaignService\nversion: v1\n" {
+		t.Errorf("unexpected yaml %q", text)
+	}
+	if result.StructuredContent == nil {
+		t.Error("expected structured content to stay JSON")
+	}
+
+	ctx = WithOptions(context.Background(), Options{OutputFormat: "xml"})
+	if _, err := ProtoResult(ctx, msg); err == nil {
+		t.Error("expected error for unknown output format")
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ndjsonMIMEType is the media type of newline-delimited JSON attachments.
+const ndjsonMIMEType = "application/x-ndjson"
+
+// ndjsonResult returns a list result as an embedded NDJSON resource, one item
+// per line, with only a summary inline: the item count and the remaining
+// top-level fields such as paginationMeta. The attachment is not subject to the
+// result size limit, which applies to inline text.
+func ndjsonResult(data []byte) (*mcp.CallToolResult, error) {
+	v, err := decodeOrdered(data)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := v.(*object)
+	if !ok {
+		return nil, fmt.Errorf("ndjson output requires an object result")
+	}
+	listKey := listField(obj)
+	if listKey == "" {
+		return nil, fmt.Errorf("ndjson output requires a list result")
+	}
+
+	items := obj.values[listKey].([]any)
+	var lines strings.Builder
+	for _, item := range items {
+		lines.WriteString(compactJSON(item) + "\n")
+	}
+
+	rest := &object{values: obj.values}
+	for _, key := range obj.keys {
+		if key != listKey {
+			rest.keys = append(rest.keys, key)
+		}
+	}
+	summary := fmt.Sprintf("Attached %d %s as NDJSON, one JSON record per line.", len(items), listKey)
+	if token := nextPageToken(obj); token != "" {
+		summary += " More results are available: repeat the call with page_token=" + token + "."
+	}
+	restJSON := compactJSON(rest)
+	if len(rest.keys) > 0 {
+		summary += "\n" + restJSON
+	}
+
+	res := ResourceResult(summary, "pidgr://results/"+listKey+".ndjson", ndjsonMIMEType, lines.String())
+	res.StructuredContent = json.RawMessage(restJSON)
+	return res, nil
+}
+
+// nextPageToken returns the token in the result's pagination metadata, in
+// either field naming style, or "" on the last page.
+func nextPageToken(obj *object) string {
+	p, ok := obj.values[paginationKey(obj.values)].(*object)
+	if !ok {
+		return ""
+	}
+	for _, name := range []string{"nextPageToken", "next_page_token"} {
+		if token, ok := p.values[name].(string); ok {
+			return token
+		}
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	pidgrv1 "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/apipb"
+)
+
+func TestNDJSONResult(t *testing.T) {
+	resp := &pidgrv1.ListCampaignsResponse{
+		Campaigns:      []*pidgrv1.Campaign{{Id: "a", Name: "A"}, {Id: "b", Name: "B"}},
+		PaginationMeta: &pidgrv1.PaginationMeta{NextPageToken: "t2"},
+	}
+	for _, protoNames := range []bool{false, true} {
+		data, err := protojson.MarshalOptions{UseProtoNames: protoNames}.Marshal(resp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := ndjsonResult(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Content) != 2 {
+			t.Fatalf("expected summary and attachment, got %d content items", len(result.Content))
+		}
+		summary := result.Content[0].(*mcp.TextContent).Text
+		if !strings.Contains(summary, "Attached 2 campaigns") || !strings.Contains(summary, "page_token=t2") {
+			t.Errorf("unexpected summary %q (proto names %v)", summary, protoNames)
+		}
+		res := result.Content[1].(*mcp.EmbeddedResource).Resource
+		if res.MIMEType != ndjsonMIMEType || res.URI != "pidgr://results/campaigns.ndjson" {
+			t.Errorf("unexpected resource %s (%s)", res.URI, res.MIMEType)
+		}
+		if want := "{\"id\":\"a\",\"name\":\"A\"}\n{\"id\":\"b\",\"name\":\"B\"}\n"; res.Text != want {
+			t.Errorf("expected %q, got %q", want, res.Text)
+		}
+	}
+}
+
+func TestNDJSONResultEmptyList(t *testing.T) {
+	result, err := ndjsonResult([]byte(`{"campaigns":[]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res := result.Content[1].(*mcp.EmbeddedResource).Resource; res.Text != "" {
+		t.Errorf("expected an empty attachment, got %q", res.Text)
+	}
+}
+
+func TestProtoResultNDJSONRequiresList(t *testing.T) {
+	ctx := WithOptions(context.Background(), Options{OutputFormat: "ndjson"})
+	if _, err := ProtoResult(ctx, &apipb.Api{Name: "pidgr.v1.CampaignService"}); err == nil {
+		t.Error("expected an error for a result without a list")
+	}
+}
+
+func TestProtoResultNDJSON(t *testing.T) {
+	ctx := WithOptions(context.Background(), Options{OutputFormat: "NDJSON"})
+	msg := &apipb.Api{Name: "pidgr.v1.CampaignService", Methods: []*apipb.Method{{Name: "ListCampaigns"}, {Name: "GetCampaign"}}}
+	result, err := ProtoResult(ctx, msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res := result.Content[1].(*mcp.EmbeddedResource).Resource
+	if lines := strings.Split(strings.TrimSpace(res.Text), "\n"); len(lines) != 2 {
+		t.Errorf("expected 2 lines, got %q", res.Text)
+	}
+}
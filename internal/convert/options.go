@@ -0,0 +1,76 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MetaEmitUnpopulated is the tools/call _meta key that requests zero-valued
+// proto fields in the result.
+const MetaEmitUnpopulated = "pidgr.com/emitUnpopulated"
+
+// Options controls how a single tool call's result is serialized.
+type Options struct {
+	// EmitUnpopulated includes fields with zero values in proto output, so
+	// an empty field can be told apart from one the backend did not send.
+	EmitUnpopulated bool
+	// Fields limits proto output to these field paths (see selectFields).
+	Fields []string
+	// OutputFormat selects how proto output is rendered as text: json (the
+	// default), markdown, or yaml.
+	OutputFormat string
+	// SummaryOnly reduces list results to counts, enum breakdowns, and the
+	// first few items (see summarizeList).
+	SummaryOnly bool
+}
+
+type optionsKey struct{}
+
+// WithOptions returns a context carrying result options for a tool call.
+func WithOptions(ctx context.Context, opts Options) context.Context {
+	return context.WithValue(ctx, optionsKey{}, opts)
+}
+
+// OptionsFrom returns the result options carried by ctx, or the zero
+// Options if none were set.
+func OptionsFrom(ctx context.Context) Options {
+	opts, _ := ctx.Value(optionsKey{}).(Options)
+	return opts
+}
+
+// OptionsFromMeta reads result options from a tools/call request's _meta.
+func OptionsFromMeta(meta map[string]any) Options {
+	var opts Options
+	opts.EmitUnpopulated, _ = meta[MetaEmitUnpopulated].(bool)
+	return opts
+}
+
+// OptionsMiddleware attaches result options to the handler context of each
+// tools/call request, where ProtoResult picks them up. Options come from the
+// request's _meta, plus the "fields", "output_format", and "summary_only"
+// arguments accepted by read tools.
+func OptionsMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if call, ok := req.(*mcp.CallToolRequest); ok && call.Params != nil {
+			opts := OptionsFromMeta(call.Params.GetMeta())
+			var args struct {
+				Fields       []string `json:"fields"`
+				OutputFormat string   `json:"output_format"`
+				SummaryOnly  bool     `json:"summary_only"`
+			}
+			// Malformed arguments are reported by the tool's own input validation.
+			if json.Unmarshal(call.Params.Arguments, &args) == nil {
+				opts.Fields = args.Fields
+				opts.OutputFormat = args.OutputFormat
+				opts.SummaryOnly = args.SummaryOnly
+			}
+			ctx = WithOptions(ctx, opts)
+		}
+		return next(ctx, method, req)
+	}
+}
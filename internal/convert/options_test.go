@@ -0,0 +1,73 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/types/known/apipb"
+)
+
+func TestProtoResultEmitUnpopulated(t *testing.T) {
+	msg := &apipb.Api{Name: "pidgr.v1.CampaignService"}
+
+	result, err := ProtoResult(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; strings.Contains(text, `"version"`) {
+		t.Errorf("expected empty version to be omitted by default, got %s", text)
+	}
+
+	ctx := WithOptions(context.Background(), Options{EmitUnpopulated: true})
+	result, err = ProtoResult(ctx, msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, `"version":""`) {
+		t.Errorf("expected empty version to be emitted, got %s", text)
+	}
+}
+
+func TestOptionsFromMeta(t *testing.T) {
+	if got := OptionsFromMeta(nil); got.EmitUnpopulated {
+		t.Error("expected zero options for nil meta")
+	}
+	if got := OptionsFromMeta(map[string]any{MetaEmitUnpopulated: "yes"}); got.EmitUnpopulated {
+		t.Error("expected non-bool value to be ignored")
+	}
+	if got := OptionsFromMeta(map[string]any{MetaEmitUnpopulated: true}); !got.EmitUnpopulated {
+		t.Error("expected EmitUnpopulated from meta")
+	}
+}
+
+func TestOptionsMiddleware(t *testing.T) {
+	var got Options
+	handler := OptionsMiddleware(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		got = OptionsFrom(ctx)
+		return nil, nil
+	})
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{
+		Meta:      mcp.Meta{MetaEmitUnpopulated: true},
+		Name:      "get_campaign",
+		Arguments: json.RawMessage(`{"campaign_id":"c1","fields":["id","name"],"summary_only":true}`),
+	}}
+	if _, err := handler(context.Background(), "tools/call", req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.EmitUnpopulated {
+		t.Error("expected middleware to attach options from _meta")
+	}
+	if !reflect.DeepEqual(got.Fields, []string{"id", "name"}) {
+		t.Errorf("expected fields from arguments, got %v", got.Fields)
+	}
+	if !got.SummaryOnly {
+		t.Error("expected summary_only from arguments")
+	}
+}
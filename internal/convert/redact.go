@@ -0,0 +1,177 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"bytes"
+	"encoding/csv"
+	"regexp"
+	"strings"
+)
+
+// Redaction configures which personal data is removed from tool results
+// before they reach the client.
+type Redaction struct {
+	// Emails replaces email addresses anywhere in string values.
+	Emails bool
+	// Phones replaces values that are phone numbers, and international
+	// (+-prefixed) numbers inside longer text.
+	Phones bool
+	// Keys lists field names whose values are replaced entirely, e.g.
+	// custom attribute keys. Matching ignores case and underscores, so
+	// employee_id also matches employeeId.
+	Keys []string
+}
+
+func (r Redaction) enabled() bool {
+	return r.Emails || r.Phones || len(r.Keys) > 0
+}
+
+// Placeholders that replace redacted data.
+const (
+	redactedEmail = "[email redacted]"
+	redactedPhone = "[phone redacted]"
+	redactedValue = "[redacted]"
+)
+
+var (
+	redaction     Redaction
+	redactionKeys map[string]bool
+
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	phoneValue    = regexp.MustCompile(`^\+?[0-9 ().-]+$`)
+	phoneInText   = regexp.MustCompile(`\+[0-9][0-9 ().-]{5,}[0-9]`)
+	isoDatePrefix = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}`)
+)
+
+const (
+	phoneSeparators = " ().-"
+	minPhoneDigits  = 7
+	maxPhoneDigits  = 15
+)
+
+// SetRedaction configures redaction of personal data in results. It must be
+// called before the server starts handling requests.
+func SetRedaction(r Redaction) {
+	redaction = r
+	redactionKeys = make(map[string]bool, len(r.Keys))
+	for _, key := range r.Keys {
+		if key = normalizeKey(key); key != "" {
+			redactionKeys[key] = true
+		}
+	}
+}
+
+// redactJSON applies the configured redaction to a JSON result. Key order
+// is preserved; data that is not valid JSON is returned unchanged.
+func redactJSON(data []byte) []byte {
+	if !redaction.enabled() {
+		return data
+	}
+	v, err := decodeOrdered(data)
+	if err != nil {
+		return data
+	}
+	return []byte(compactJSON(redactValue(v)))
+}
+
+func redactValue(v any) any {
+	switch x := v.(type) {
+	case *object:
+		for _, key := range x.keys {
+			if redactionKeys[normalizeKey(key)] {
+				x.values[key] = redactedValue
+				continue
+			}
+			x.values[key] = redactValue(x.values[key])
+		}
+	case []any:
+		for i, item := range x {
+			x[i] = redactValue(item)
+		}
+	case string:
+		return redactString(x)
+	}
+	return v
+}
+
+// redactText applies the configured redaction to a text resource. CSV is
+// redacted cell by cell, with whole columns removed when their header names
+// a redacted key.
+func redactText(mimeType, text string) string {
+	if !redaction.enabled() {
+		return text
+	}
+	if mimeType != "text/csv" {
+		return redactString(text)
+	}
+	records, err := csv.NewReader(strings.NewReader(text)).ReadAll()
+	if err != nil || len(records) == 0 {
+		return redactString(text)
+	}
+	drop := map[int]bool{}
+	for i, header := range records[0] {
+		drop[i] = redactionKeys[normalizeKey(header)]
+	}
+	for _, record := range records[1:] {
+		for i, cell := range record {
+			if drop[i] {
+				record[i] = redactedValue
+			} else {
+				record[i] = redactString(cell)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(records); err != nil {
+		return redactString(text)
+	}
+	return buf.String()
+}
+
+// redactString removes emails and phone numbers from a single value.
+func redactString(s string) string {
+	if redaction.Phones {
+		if isPhone(s) {
+			return redactedPhone
+		}
+		s = phoneInText.ReplaceAllStringFunc(s, func(m string) string {
+			if isPhone(m) {
+				return redactedPhone
+			}
+			return m
+		})
+	}
+	if redaction.Emails {
+		s = emailPattern.ReplaceAllString(s, redactedEmail)
+	}
+	return s
+}
+
+// isPhone reports whether s as a whole looks like a phone number: digits and
+// separators only, 7 to 15 digits, and either a leading + or a separator so
+// bare numeric IDs are left alone. Dates are not phone numbers.
+func isPhone(s string) bool {
+	s = strings.TrimSpace(s)
+	if !phoneValue.MatchString(s) || isoDatePrefix.MatchString(s) {
+		return false
+	}
+	digits := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	if digits < minPhoneDigits || digits > maxPhoneDigits {
+		return false
+	}
+	return strings.HasPrefix(s, "+") || strings.ContainsAny(strings.Trim(s, phoneSeparators), phoneSeparators)
+}
+
+// normalizeKey lowercases a field name and drops underscores, so snake_case
+// and lowerCamel spellings compare equal.
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(key), "_", ""))
+}
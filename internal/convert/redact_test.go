@@ -0,0 +1,96 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func withRedaction(t *testing.T, r Redaction) {
+	t.Helper()
+	SetRedaction(r)
+	t.Cleanup(func() { SetRedaction(Redaction{}) })
+}
+
+func TestRedactJSON(t *testing.T) {
+	withRedaction(t, Redaction{Emails: true, Phones: true, Keys: []string{"employee_id", "ssn"}})
+
+	data := []byte(`{"users":[{"id":"8f14e45f-ceea-467a-a866-0c2c2b3b4a5e","email":"ana@example.com","phone":"(555) 123-4567","employeeId":"E-1001","attributes":{"ssn":"123-45-6789","team":"Ops"},"note":"Call +1 555 123 4567 or mail ana@example.com","createdAt":"2026-03-02T14:30:00Z","badge":"1234567"}]}`)
+	got := decodeJSON(t, redactJSON(data))
+	want := decodeJSON(t, []byte(`{"users":[{"id":"8f14e45f-ceea-467a-a866-0c2c2b3b4a5e","email":"[email redacted]","phone":"[phone redacted]","employeeId":"[redacted]","attributes":{"ssn":"[redacted]","team":"Ops"},"note":"Call [phone redacted] or mail [email redacted]","createdAt":"2026-03-02T14:30:00Z","badge":"1234567"}]}`))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestRedactJSONDisabled(t *testing.T) {
+	data := []byte(`{"email": "ana@example.com"}`)
+	if got := redactJSON(data); string(got) != string(data) {
+		t.Errorf("expected data unchanged, got %s", got)
+	}
+}
+
+func TestRedactTextCSV(t *testing.T) {
+	withRedaction(t, Redaction{Emails: true, Keys: []string{"Employee ID"}})
+
+	text := "name,email,Employee ID\nAna,ana@example.com,E-1001\n"
+	want := "name,email,Employee ID\nAna,[email redacted],[redacted]\n"
+	if got := redactText("text/csv", text); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestJSONResultRedacts(t *testing.T) {
+	withRedaction(t, Redaction{Emails: true})
+
+	result, err := JSONResult(map[string]string{"email": "ana@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; text != `{"email":"[email redacted]"}` {
+		t.Errorf("unexpected result %s", text)
+	}
+}
+
+func TestErrorResultRedacts(t *testing.T) {
+	withRedaction(t, Redaction{Emails: true})
+
+	err := connect.NewError(connect.CodeAlreadyExists, errors.New("user ana@example.com already exists"))
+	result, _ := ErrorResult(err)
+	if text := result.Content[0].(*mcp.TextContent).Text; strings.Contains(text, "ana@example.com") || !strings.Contains(text, redactedEmail) {
+		t.Errorf("expected the email to be redacted, got %q", text)
+	}
+}
+
+func TestSuccessResultRedacts(t *testing.T) {
+	withRedaction(t, Redaction{Emails: true})
+
+	result := SuccessResult("Invitation sent to ana@example.com")
+	if text := result.Content[0].(*mcp.TextContent).Text; text != "Invitation sent to [email redacted]" {
+		t.Errorf("unexpected result %q", text)
+	}
+}
+
+func TestIsPhone(t *testing.T) {
+	tests := map[string]bool{
+		"+15551234567":   true,
+		"555-123-4567":   true,
+		"(555) 123 4567": true,
+		"1234567":        false,
+		"2026-03-02":     false,
+		"555-1234":       true,
+		"1.2.3":          false,
+	}
+	for s, want := range tests {
+		if got := isPhone(s); got != want {
+			t.Errorf("isPhone(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// renderFields rewrites the enum and timestamp values of a protojson object
+// result for message type md, following the configured enum style and
+// timestamp rendering. Key order is preserved.
+func renderFields(data []byte, md protoreflect.MessageDescriptor) ([]byte, error) {
+	v, err := decodeOrdered(data)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := v.(*object)
+	if !ok {
+		return data, nil
+	}
+	rewriteFields(obj, md)
+	return []byte(compactJSON(obj)), nil
+}
+
+// specialJSON lists the well-known types that protojson renders as strings or
+// free-form JSON rather than as objects of their fields.
+var specialJSON = map[protoreflect.FullName]bool{
+	"google.protobuf.Any":       true,
+	"google.protobuf.Struct":    true,
+	"google.protobuf.Value":     true,
+	"google.protobuf.ListValue": true,
+	timestampName:               true,
+}
+
+// rewriteFields walks obj alongside its message descriptor, rewriting enum
+// and timestamp values and recursing into nested messages. Companion fields
+// (enum labels, local times) are inserted after the field they describe.
+func rewriteFields(obj *object, md protoreflect.MessageDescriptor) {
+	if specialJSON[md.FullName()] {
+		return
+	}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		name := resultName(fd)
+		v, ok := obj.values[name]
+		if !ok {
+			continue
+		}
+
+		switch {
+		case fd.IsMap():
+			entries, ok := v.(*object)
+			if !ok {
+				continue
+			}
+			for _, key := range entries.keys {
+				entries.values[key] = rewriteValue(entries.values[key], fd.MapValue())
+			}
+
+		case fd.Kind() == protoreflect.EnumKind:
+			if fd.Enum().FullName() == "google.protobuf.NullValue" {
+				continue
+			}
+			obj.values[name] = rewriteValue(v, fd)
+			if enumStyle == EnumsLabeled {
+				obj.insertAfter(name, companionKey(name, "label"), mapValues(obj.values[name], enumLabel))
+			}
+
+		case isTimestamp(fd) && timeCompanion:
+			obj.insertAfter(name, companionKey(name, "local"), mapValues(v, localTime))
+
+		default:
+			obj.values[name] = rewriteValue(v, fd)
+		}
+	}
+}
+
+// rewriteValue rewrites a singular or repeated enum, timestamp, or message
+// value in place of the original.
+func rewriteValue(v any, fd protoreflect.FieldDescriptor) any {
+	switch {
+	case fd.Kind() == protoreflect.EnumKind:
+		ed := fd.Enum()
+		return mapValues(v, func(v any) any { return shortEnum(v, ed) })
+	case isTimestamp(fd):
+		return mapValues(v, localTime)
+	case fd.Kind() == protoreflect.MessageKind, fd.Kind() == protoreflect.GroupKind:
+		md := fd.Message()
+		mapValues(v, func(v any) any {
+			if o, ok := v.(*object); ok {
+				rewriteFields(o, md)
+			}
+			return v
+		})
+	}
+	return v
+}
+
+// mapValues applies fn to v, or to each element if v is a list.
+func mapValues(v any, fn func(any) any) any {
+	items, ok := v.([]any)
+	if !ok {
+		return fn(v)
+	}
+	out := make([]any, len(items))
+	for i, item := range items {
+		out[i] = fn(item)
+	}
+	return out
+}
+
+// companionKey names a field derived from another, in the result's naming
+// style: statusLabel, or status_label with proto names.
+func companionKey(name, suffix string) string {
+	if useProtoNames {
+		return name + "_" + suffix
+	}
+	return name + strings.ToUpper(suffix[:1]) + suffix[1:]
+}
+
+// insertAfter adds key with value v directly after an existing key.
+func (o *object) insertAfter(after, key string, v any) {
+	if _, ok := o.values[key]; !ok {
+		for i, k := range o.keys {
+			if k == after {
+				o.keys = append(o.keys[:i+1], append([]string{key}, o.keys[i+1:]...)...)
+				break
+			}
+		}
+	}
+	o.values[key] = v
+}
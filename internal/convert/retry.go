@@ -0,0 +1,76 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// Error result _meta keys describing whether and when a failed call can be
+// retried, so agent frameworks can back off instead of retrying at once.
+const (
+	MetaRetryable  = "pidgr.com/retryable"
+	MetaRetryAfter = "pidgr.com/retryAfter"
+)
+
+// retryableCodes are the error codes where repeating the same call may
+// succeed. Client errors fail the same way every time.
+var retryableCodes = map[connect.Code]bool{
+	connect.CodeUnavailable:       true,
+	connect.CodeResourceExhausted: true,
+	connect.CodeDeadlineExceeded:  true,
+	connect.CodeAborted:           true,
+}
+
+// defaultRetryAfter is the suggested wait, in seconds, for codes that signal
+// an overloaded backend when it sends no Retry-After header.
+var defaultRetryAfter = map[connect.Code]int{
+	connect.CodeResourceExhausted: 30,
+	connect.CodeUnavailable:       5,
+}
+
+// retryMeta returns the retry metadata for an error result: whether the call
+// is worth retrying and, if known, how many seconds to wait first.
+func retryMeta(err error) map[string]any {
+	code := connect.CodeOf(err)
+	if connect.IsNotModifiedError(err) || !retryableCodes[code] {
+		return map[string]any{MetaRetryable: false}
+	}
+	meta := map[string]any{MetaRetryable: true}
+	after := retryAfter(err, time.Now())
+	if after == 0 {
+		after = defaultRetryAfter[code]
+	}
+	if after > 0 {
+		meta[MetaRetryAfter] = after
+	}
+	return meta
+}
+
+// retryAfter reads the backend's Retry-After header, in delay-seconds or
+// HTTP-date form, as whole seconds from now. It returns 0 if there is none.
+func retryAfter(err error, now time.Time) int {
+	var ce *connect.Error
+	if !errors.As(err, &ce) {
+		return 0
+	}
+	v := strings.TrimSpace(ce.Meta().Get("Retry-After"))
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return max(secs, 0)
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(int(math.Ceil(at.Sub(now).Seconds())), 0)
+	}
+	return 0
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+func TestErrorResultRetryMeta(t *testing.T) {
+	throttled := connect.NewError(connect.CodeResourceExhausted, fmt.Errorf("rate limited"))
+	throttled.Meta().Set("Retry-After", "12")
+
+	tests := []struct {
+		name string
+		err  error
+		want map[string]any
+	}{
+		{"client error", connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("bad")), map[string]any{MetaRetryable: false}},
+		{"unavailable default", connect.NewError(connect.CodeUnavailable, fmt.Errorf("down")), map[string]any{MetaRetryable: true, MetaRetryAfter: 5}},
+		{"retry-after header", throttled, map[string]any{MetaRetryable: true, MetaRetryAfter: 12}},
+		{"timeout", connect.NewError(connect.CodeDeadlineExceeded, fmt.Errorf("slow")), map[string]any{MetaRetryable: true}},
+		{"local error", fmt.Errorf("boom"), map[string]any{MetaRetryable: false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _ := ErrorResult(tt.err)
+			if !reflect.DeepEqual(map[string]any(result.Meta), tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, result.Meta)
+			}
+		})
+	}
+}
+
+func TestRetryAfterHTTPDate(t *testing.T) {
+	now := time.Date(2026, 3, 2, 14, 30, 0, 0, time.UTC)
+	err := connect.NewError(connect.CodeUnavailable, fmt.Errorf("down"))
+	err.Meta().Set("Retry-After", now.Add(90*time.Second).Format(http.TimeFormat))
+	if got := retryAfter(err, now); got != 90 {
+		t.Errorf("expected 90 seconds, got %d", got)
+	}
+}
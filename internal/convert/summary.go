@@ -0,0 +1,162 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// summaryItems is how many list items a summary keeps.
+const summaryItems = 5
+
+// summarizeList reduces a list result to the shape of its data: the item
+// count, how many items fall under each value of the items' enum and bool
+// fields, and the first summaryItems items. Other top-level fields, such as
+// pagination, are kept. Counts cover the returned page only. Results without
+// a list are returned unchanged. Typed results, which have no descriptor
+// (md is nil), are broken down by their status fields. When the result was
+// filtered with fields, enum and bool fields that no item carries are left
+// out of the breakdown.
+func summarizeList(data []byte, md protoreflect.MessageDescriptor, filtered bool) ([]byte, error) {
+	v, err := decodeOrdered(data)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := v.(*object)
+	if !ok {
+		return data, nil
+	}
+	listKey := listField(obj)
+	if listKey == "" {
+		return data, nil
+	}
+	items := obj.values[listKey].([]any)
+
+	summary := &object{values: map[string]any{}}
+	summary.insert("count", json.Number(strconv.Itoa(len(items))))
+	summary.insert("shown", json.Number(strconv.Itoa(min(len(items), summaryItems))))
+	var breakdown *object
+	if md == nil {
+		breakdown = statusBreakdowns(items)
+	} else if fd := fieldByKey(md, listKey); fd != nil && fd.Kind() == protoreflect.MessageKind {
+		breakdown = breakdowns(items, fd.Message(), filtered)
+	}
+	if breakdown != nil && len(breakdown.keys) > 0 {
+		summary.insert("breakdown", breakdown)
+	}
+
+	out := &object{values: map[string]any{}}
+	out.insert("summary", summary)
+	for _, key := range obj.keys {
+		if key == listKey {
+			out.insert(key, items[:min(len(items), summaryItems)])
+		} else {
+			out.insert(key, obj.values[key])
+		}
+	}
+	return []byte(compactJSON(out)), nil
+}
+
+// breakdowns counts list items by the value of each enum and bool field of
+// their message type. Fields protojson omitted count under their zero value,
+// unless no item has the field and the result was filtered.
+func breakdowns(items []any, md protoreflect.MessageDescriptor, filtered bool) *object {
+	out := &object{values: map[string]any{}}
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.IsList() || fd.IsMap() {
+			continue
+		}
+		var zero string
+		switch fd.Kind() {
+		case protoreflect.EnumKind:
+			zero, _ = shortEnum(string(fd.Enum().Values().Get(0).Name()), fd.Enum()).(string)
+		case protoreflect.BoolKind:
+			zero = "false"
+		default:
+			continue
+		}
+		name := resultName(fd)
+		counts := &object{values: map[string]any{}}
+		seen := false
+		for _, item := range items {
+			o, ok := item.(*object)
+			if !ok {
+				continue
+			}
+			value := zero
+			if v, ok := o.values[name]; ok {
+				seen = true
+				value = yamlScalar(v)
+				if s, ok := v.(string); ok {
+					value = s
+				}
+			}
+			n, _ := jsonInt(counts.values[value])
+			counts.insert(value, json.Number(strconv.Itoa(n+1)))
+		}
+		if seen || !filtered {
+			out.insert(name, counts)
+		}
+	}
+	return out
+}
+
+// statusBreakdowns counts list items by the value of each string field named
+// status or ending in Status.
+func statusBreakdowns(items []any) *object {
+	out := &object{values: map[string]any{}}
+	for _, item := range items {
+		o, ok := item.(*object)
+		if !ok {
+			continue
+		}
+		for _, key := range o.keys {
+			value, ok := o.values[key].(string)
+			if !ok || (key != "status" && !strings.HasSuffix(key, "Status")) {
+				continue
+			}
+			counts, ok := out.values[key].(*object)
+			if !ok {
+				counts = &object{values: map[string]any{}}
+				out.insert(key, counts)
+			}
+			n, _ := jsonInt(counts.values[value])
+			counts.insert(value, json.Number(strconv.Itoa(n+1)))
+		}
+	}
+	return out
+}
+
+// fieldByKey returns the field of md that a result key names.
+func fieldByKey(md protoreflect.MessageDescriptor, key string) protoreflect.FieldDescriptor {
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if fd := fields.Get(i); resultName(fd) == key {
+			return fd
+		}
+	}
+	return nil
+}
+
+// resultName is the key a field has in results.
+func resultName(fd protoreflect.FieldDescriptor) string {
+	if useProtoNames {
+		return string(fd.Name())
+	}
+	return fd.JSONName()
+}
+
+// insert sets key to v, appending key if it is new.
+func (o *object) insert(key string, v any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = v
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+func TestProtoResultSummaryOnly(t *testing.T) {
+	var methods []*apipb.Method
+	for i, name := range []string{"A", "B", "C", "D", "E", "F", "G"} {
+		m := &apipb.Method{Name: name}
+		if i < 3 {
+			m.Syntax = typepb.Syntax_SYNTAX_PROTO3
+			m.RequestStreaming = true
+		}
+		methods = append(methods, m)
+	}
+	ctx := WithOptions(context.Background(), Options{SummaryOnly: true})
+	result, err := ProtoResult(ctx, &apipb.Api{Name: "pidgr.v1.CampaignService", Methods: methods})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := decodeJSON(t, []byte(result.Content[0].(*mcp.TextContent).Text)).(map[string]any)
+	want := decodeJSON(t, []byte(`{
+		"count": 7,
+		"shown": 5,
+		"breakdown": {
+			"requestStreaming": {"true": 3, "false": 4},
+			"responseStreaming": {"false": 7},
+			"syntax": {"PROTO3": 3, "PROTO2": 4}
+		}
+	}`))
+	if !reflect.DeepEqual(got["summary"], want) {
+		t.Errorf("expected summary %v, got %v", want, got["summary"])
+	}
+	if items := got["methods"].([]any); len(items) != summaryItems {
+		t.Errorf("expected %d items, got %d", summaryItems, len(items))
+	}
+	if got["name"] != "pidgr.v1.CampaignService" {
+		t.Errorf("expected other fields to be kept, got %v", got)
+	}
+}
+
+func TestProtoResultSummaryOnlyFiltered(t *testing.T) {
+	ctx := WithOptions(context.Background(), Options{SummaryOnly: true, Fields: []string{"name", "syntax"}})
+	msg := &apipb.Api{Methods: []*apipb.Method{{Name: "A", Syntax: typepb.Syntax_SYNTAX_PROTO3}, {Name: "B"}}}
+	result, err := ProtoResult(ctx, msg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := decodeJSON(t, []byte(result.Content[0].(*mcp.TextContent).Text)).(map[string]any)
+	want := decodeJSON(t, []byte(`{"count": 2, "shown": 2, "breakdown": {"syntax": {"PROTO3": 1, "PROTO2": 1}}}`))
+	if !reflect.DeepEqual(got["summary"], want) {
+		t.Errorf("expected summary %v, got %v", want, got["summary"])
+	}
+}
+
+func TestSummarizeListWithoutList(t *testing.T) {
+	data := []byte(`{"name":"x"}`)
+	got, err := summarizeList(data, (&apipb.Api{}).ProtoReflect().Descriptor(), false)
+	if err != nil || string(got) != string(data) {
+		t.Errorf("expected result unchanged, got %s (%v)", got, err)
+	}
+}
+
+func TestTypedResultSummaryOnly(t *testing.T) {
+	type item struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	out := struct {
+		Items []item `json:"items"`
+	}{Items: []item{{"a", "RUNNING"}, {"b", "COMPLETED"}, {"c", "RUNNING"}}}
+
+	ctx := WithOptions(context.Background(), Options{SummaryOnly: true})
+	result, err := TypedResult(ctx, out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := decodeJSON(t, []byte(result.Content[0].(*mcp.TextContent).Text)).(map[string]any)
+	want := decodeJSON(t, []byte(`{"count": 3, "shown": 3, "breakdown": {"status": {"RUNNING": 2, "COMPLETED": 1}}}`))
+	if !reflect.DeepEqual(got["summary"], want) {
+		t.Errorf("expected summary %v, got %v", want, got["summary"])
+	}
+}
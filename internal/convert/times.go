@@ -0,0 +1,103 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const timestampName protoreflect.FullName = "google.protobuf.Timestamp"
+
+// timeFormats are the named timestamp formats. Any other format must be a Go
+// reference-time layout with at least one time element.
+var timeFormats = map[string]string{
+	"rfc3339":  time.RFC3339,
+	"rfc1123":  time.RFC1123,
+	"datetime": "2006-01-02 15:04:05 MST",
+}
+
+// Timestamp rendering for proto results. By default timestamps are left as
+// protojson renders them: RFC 3339 in UTC.
+var (
+	timeLocation  *time.Location
+	timeLayout    = time.RFC3339
+	timeCompanion bool
+)
+
+// SetTimeRendering renders proto timestamps in results in loc using format,
+// a name from timeFormats or a Go layout. With companion set the original
+// UTC value is kept and the converted one is added alongside it as
+// <field>Local. A nil loc leaves timestamps unchanged. It must be called
+// before the server starts handling requests.
+func SetTimeRendering(loc *time.Location, format string, companion bool) error {
+	layout, err := timeLayoutFor(format)
+	if err != nil {
+		return err
+	}
+	timeLocation, timeLayout, timeCompanion = loc, layout, companion && loc != nil
+	return nil
+}
+
+// ValidateTimeFormat reports whether format is accepted by SetTimeRendering,
+// without changing the current rendering.
+func ValidateTimeFormat(format string) error {
+	_, err := timeLayoutFor(format)
+	return err
+}
+
+// timeLayoutFor returns the Go layout for format; empty selects RFC 3339.
+func timeLayoutFor(format string) (string, error) {
+	if format == "" {
+		return time.RFC3339, nil
+	}
+	if named, ok := timeFormats[strings.ToLower(format)]; ok {
+		return named, nil
+	}
+	if time.Unix(0, 0).UTC().Format(format) != format {
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown time format %q: use rfc3339, rfc1123, datetime, or a Go layout", format)
+}
+
+// renderTimes reports whether timestamps in results need rewriting.
+func renderTimes() bool {
+	return timeLocation != nil
+}
+
+func isTimestamp(fd protoreflect.FieldDescriptor) bool {
+	return fd.Kind() == protoreflect.MessageKind && fd.Message().FullName() == timestampName
+}
+
+// TimeValue renders a proto timestamp for a typed result: in the configured
+// location and layout, or RFC 3339 in UTC by default and in companion mode.
+// A nil timestamp renders as "".
+func TimeValue(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	t := ts.AsTime()
+	if timeLocation == nil || timeCompanion {
+		return t.Format(time.RFC3339Nano)
+	}
+	return t.In(timeLocation).Format(timeLayout)
+}
+
+// localTime renders a protojson timestamp in the configured location and
+// layout. Values that do not parse are returned unchanged.
+func localTime(v any) any {
+	s, ok := v.(string)
+	if !ok || timeLocation == nil {
+		return v
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return v
+	}
+	return t.In(timeLocation).Format(timeLayout)
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// eventMessage builds a message with a singular and a repeated timestamp:
+// message Event { Timestamp created_at = 1; repeated Timestamp seen_at = 2; }
+func eventMessage(t *testing.T) *dynamicpb.Message {
+	t.Helper()
+	ts := ".google.protobuf.Timestamp"
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("event.proto"),
+		Package:    proto.String("test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Event"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("created_at"), JsonName: proto.String("createdAt"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: &ts, Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				{Name: proto.String("seen_at"), JsonName: proto.String("seenAt"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), TypeName: &ts, Label: descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()},
+			},
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatalf("build descriptor: %v", err)
+	}
+	md := file.Messages().Get(0)
+	msg := dynamicpb.NewMessage(md)
+	at := time.Date(2026, 3, 2, 14, 30, 0, 0, time.UTC)
+	msg.Set(md.Fields().ByName("created_at"), protoreflect.ValueOfMessage(timestamppb.New(at).ProtoReflect()))
+	seen := msg.Mutable(md.Fields().ByName("seen_at")).List()
+	seen.Append(protoreflect.ValueOfMessage(timestamppb.New(at.Add(time.Hour)).ProtoReflect()))
+	return msg
+}
+
+func timeResult(t *testing.T, loc *time.Location, format string, companion bool) any {
+	t.Helper()
+	if err := SetTimeRendering(loc, format, companion); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = SetTimeRendering(nil, "", false) })
+
+	result, err := ProtoResult(context.Background(), eventMessage(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return decodeJSON(t, []byte(result.Content[0].(*mcp.TextContent).Text))
+}
+
+func TestProtoResultTimeRendering(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		loc       *time.Location
+		format    string
+		companion bool
+		want      string
+	}{
+		{"default", nil, "", false, `{"createdAt":"2026-03-02T14:30:00Z","seenAt":["2026-03-02T15:30:00Z"]}`},
+		{"converted", ny, "", false, `{"createdAt":"2026-03-02T09:30:00-05:00","seenAt":["2026-03-02T10:30:00-05:00"]}`},
+		{"named format", ny, "datetime", false, `{"createdAt":"2026-03-02 09:30:00 EST","seenAt":["2026-03-02 10:30:00 EST"]}`},
+		{"companion", ny, "Mon Jan 2 15:04 MST", true, `{"createdAt":"2026-03-02T14:30:00Z","createdAtLocal":"Mon Mar 2 09:30 EST","seenAt":["2026-03-02T15:30:00Z"],"seenAtLocal":["Mon Mar 2 10:30 EST"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := timeResult(t, tt.loc, tt.format, tt.companion)
+			if want := decodeJSON(t, []byte(tt.want)); !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestSetTimeRenderingRejectsUnknownFormat(t *testing.T) {
+	if err := SetTimeRendering(time.UTC, "fancy", false); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
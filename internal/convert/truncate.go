@@ -0,0 +1,128 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// DefaultMaxResultBytes is the default cap on a serialized tool result,
+// sized to stay well inside typical client context limits.
+const DefaultMaxResultBytes = 100_000
+
+// maxResultBytes caps the size of serialized tool results. Zero disables it.
+var maxResultBytes = DefaultMaxResultBytes
+
+// SetMaxResultBytes sets the result size limit; zero disables it. It must be
+// called before the server starts handling requests.
+func SetMaxResultBytes(n int) {
+	maxResultBytes = n
+}
+
+// truncateJSON shrinks a JSON result to at most limit bytes. When the result
+// is an object with a top-level array, trailing array elements are dropped
+// until it fits, keeping the output valid JSON; otherwise the raw bytes are
+// cut. It returns the shortened data, a note describing the truncation and
+// how to continue, and whether the data is still valid JSON.
+func truncateJSON(data []byte, limit int) ([]byte, string, bool) {
+	var obj map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&obj); err == nil {
+		if field := largestArray(obj); field != "" {
+			items := obj[field].([]any)
+			// Smallest element count that no longer fits; one less is the most that does.
+			n := sort.Search(len(items)+1, func(n int) bool {
+				obj[field] = items[:n]
+				out, err := marshalCompact(obj)
+				return err != nil || len(out) > limit
+			}) - 1
+			if n >= 0 {
+				obj[field] = items[:n]
+				note := truncationNote(obj, field, n, len(items), limit)
+				if out, err := marshalCompact(obj); err == nil {
+					return out, note, true
+				}
+			}
+		}
+	}
+
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(data[cut]) {
+		cut--
+	}
+	return data[:cut], fmt.Sprintf("Result truncated to the first %d of %d bytes, so the JSON above is incomplete. Narrow the request with filters or a smaller page_size.", cut, len(data)), false
+}
+
+// largestArray returns the top-level array field with the largest encoded
+// size, breaking ties by name so truncation is deterministic.
+func largestArray(obj map[string]any) string {
+	best, bestSize := "", -1
+	for key, v := range obj {
+		items, ok := v.([]any)
+		if !ok || len(items) == 0 {
+			continue
+		}
+		out, err := marshalCompact(items)
+		if err != nil {
+			continue
+		}
+		if len(out) > bestSize || (len(out) == bestSize && key < best) {
+			best, bestSize = key, len(out)
+		}
+	}
+	return best
+}
+
+// truncationNote describes a truncated array and how to fetch the rest. For
+// offset-chunked results it also moves nextOffset to the first omitted item.
+func truncationNote(obj map[string]any, field string, returned, total, limit int) string {
+	note := fmt.Sprintf("Result truncated to fit the %d-byte limit: showing %d of %d %s.", limit, returned, total, field)
+	if offset, ok := jsonInt(obj["offset"]); ok {
+		next := offset + returned
+		obj["nextOffset"] = next
+		return note + fmt.Sprintf(" Continue with offset=%d and limit=%d.", next, max(returned, 1))
+	}
+	if paginationKey(obj) != "" {
+		return note + fmt.Sprintf(" Re-run with page_size=%d and follow page_token to fetch the rest.", max(returned, 1))
+	}
+	return note + " Narrow the request with filters to see the rest."
+}
+
+// paginationKey returns the key of the result's pagination metadata, in
+// either field naming style, or "" if the result is not paginated.
+func paginationKey(obj map[string]any) string {
+	for _, key := range []string{"paginationMeta", "pagination_meta"} {
+		if _, ok := obj[key]; ok {
+			return key
+		}
+	}
+	return ""
+}
+
+// jsonInt converts a decoded JSON number to an int.
+func jsonInt(v any) (int, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	i, err := strconv.Atoi(n.String())
+	return i, err == nil
+}
+
+// marshalCompact encodes v as compact JSON without HTML escaping.
+func marshalCompact(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	pidgrv1 "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func bigList(field string, n int, extra string) []byte {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"id":"item-%03d","note":"<%s>"}`, i, strings.Repeat("x", 40))
+	}
+	return []byte(`{` + extra + `"` + field + `":[` + strings.Join(items, ",") + `]}`)
+}
+
+// campaignPage renders a page of n campaigns the way list tools do.
+func campaignPage(t *testing.T, n int) []byte {
+	t.Helper()
+	resp := &pidgrv1.ListCampaignsResponse{PaginationMeta: &pidgrv1.PaginationMeta{NextPageToken: "abc"}}
+	for i := range n {
+		resp.Campaigns = append(resp.Campaigns, &pidgrv1.Campaign{
+			Id:   fmt.Sprintf("item-%03d", i),
+			Name: "<" + strings.Repeat("x", 40) + ">",
+		})
+	}
+	data, err := protojson.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestTruncateJSONPaginated(t *testing.T) {
+	data := campaignPage(t, 100)
+
+	out, note, valid := truncateJSON(data, 1000)
+	if !valid || len(out) > 1000 {
+		t.Fatalf("expected valid JSON within 1000 bytes, got %d bytes (valid=%v)", len(out), valid)
+	}
+	var got struct {
+		Campaigns []map[string]string `json:"campaigns"`
+	}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("truncated output is not JSON: %v", err)
+	}
+	n := len(got.Campaigns)
+	if n == 0 || got.Campaigns[n-1]["id"] != fmt.Sprintf("item-%03d", n-1) {
+		t.Errorf("expected a leading prefix of campaigns, got %d items", n)
+	}
+	if !strings.Contains(note, fmt.Sprintf("showing %d of 100 campaigns", n)) || !strings.Contains(note, fmt.Sprintf("page_size=%d", n)) {
+		t.Errorf("unexpected note: %s", note)
+	}
+	if !strings.Contains(string(out), "<x") {
+		t.Error("expected HTML characters to be left unescaped")
+	}
+
+	again, _, _ := truncateJSON(data, 1000)
+	if string(again) != string(out) {
+		t.Error("expected truncation to be deterministic")
+	}
+}
+
+func TestTruncateJSONOffsetChunk(t *testing.T) {
+	data := bigList("snapshots", 50, `"offset":200,"nextOffset":250,`)
+
+	out, note, _ := truncateJSON(data, 800)
+	var got struct {
+		Snapshots  []any `json:"snapshots"`
+		NextOffset int   `json:"nextOffset"`
+	}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("truncated output is not JSON: %v", err)
+	}
+	want := 200 + len(got.Snapshots)
+	if got.NextOffset != want || !strings.Contains(note, fmt.Sprintf("offset=%d", want)) {
+		t.Errorf("nextOffset = %d, note %q, want continuation at %d", got.NextOffset, note, want)
+	}
+}
+
+func TestTruncateJSONWithoutArray(t *testing.T) {
+	data := []byte(`{"body":"` + strings.Repeat("é", 600) + `"}`)
+
+	out, note, valid := truncateJSON(data, 501)
+	if valid || len(out) > 501 || !strings.Contains(note, "incomplete") {
+		t.Errorf("expected a raw cut with a note, got %d bytes valid=%v note=%q", len(out), valid, note)
+	}
+	if !utf8.Valid(out) {
+		t.Error("expected the cut to land on a rune boundary")
+	}
+}
+
+func TestJSONResultTruncates(t *testing.T) {
+	SetMaxResultBytes(500)
+	defer SetMaxResultBytes(DefaultMaxResultBytes)
+
+	var v map[string]any
+	if err := json.Unmarshal(bigList("users", 40, ""), &v); err != nil {
+		t.Fatal(err)
+	}
+	result, err := JSONResult(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Content) != 2 {
+		t.Fatalf("expected data and note content, got %d items", len(result.Content))
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; len(text) > 500 {
+		t.Errorf("expected at most 500 bytes, got %d", len(text))
+	}
+	if result.StructuredContent == nil {
+		t.Error("expected structured content for a still-valid object")
+	}
+}
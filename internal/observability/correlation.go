@@ -0,0 +1,110 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package observability
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// CorrelationHeader carries a tool call's correlation ID to the backend. It
+// is the header pidgr-api already logs and echoes as its request ID, so a
+// failed tool call can be found in backend logs by the same ID.
+const CorrelationHeader = "X-Request-Id"
+
+// MetaCorrelationID is the tools/call _meta key a client may set to supply
+// its own correlation ID. The ID used is returned under the same key in the
+// result's _meta.
+const MetaCorrelationID = "pidgr.com/requestId"
+
+// maxCorrelationID bounds accepted IDs so clients cannot inject large values
+// into headers and logs.
+const maxCorrelationID = 128
+
+type correlationKey struct{}
+
+// WithCorrelationID returns a context carrying id.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID returns the correlation ID carried by ctx, or "".
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// NewCorrelationID returns a random 128-bit hex ID.
+func NewCorrelationID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// validCorrelationID reports whether a client-supplied ID is safe to
+// forward: short, and limited to characters common in request and trace IDs.
+func validCorrelationID(id string) bool {
+	if id == "" || len(id) > maxCorrelationID {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_.:", r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// callCorrelationID returns the correlation ID for a tools/call request:
+// the client's from _meta, else from the HTTP request header, else a new one.
+func callCorrelationID(call *mcp.CallToolRequest) string {
+	if id, _ := call.Params.GetMeta()[MetaCorrelationID].(string); validCorrelationID(id) {
+		return id
+	}
+	if call.Extra != nil && call.Extra.Header != nil {
+		if id := call.Extra.Header.Get(CorrelationHeader); validCorrelationID(id) {
+			return id
+		}
+	}
+	return NewCorrelationID()
+}
+
+// CorrelationMiddleware assigns a correlation ID to each tools/call request,
+// attaches it to the handler context for the backend clients to forward,
+// logs the call's outcome with it, and returns it in the result's _meta.
+func CorrelationMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		call, ok := req.(*mcp.CallToolRequest)
+		if !ok || call.Params == nil {
+			return next(ctx, method, req)
+		}
+		id := callCorrelationID(call)
+		start := time.Now()
+		res, err := next(WithCorrelationID(ctx, id), method, req)
+
+		attrs := []any{"tool", call.Params.Name, "request_id", id, "duration", time.Since(start)}
+		result, _ := res.(*mcp.CallToolResult)
+		switch {
+		case err != nil:
+			slog.Warn("tool call failed", append(attrs, "error", err)...)
+		case result != nil && result.IsError:
+			slog.Warn("tool call returned error", attrs...)
+		default:
+			slog.Info("tool call", attrs...)
+		}
+		if result != nil {
+			if result.Meta == nil {
+				result.Meta = mcp.Meta{}
+			}
+			result.Meta[MetaCorrelationID] = id
+		}
+		return res, err
+	}
+}
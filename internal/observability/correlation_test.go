@@ -0,0 +1,77 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package observability
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestCorrelationMiddleware(t *testing.T) {
+	tests := []struct {
+		name   string
+		meta   mcp.Meta
+		header http.Header
+		want   string
+	}{
+		{"from meta", mcp.Meta{MetaCorrelationID: "trace-1"}, http.Header{CorrelationHeader: {"hdr-1"}}, "trace-1"},
+		{"from header", nil, http.Header{CorrelationHeader: {"hdr-1"}}, "hdr-1"},
+		{"invalid meta replaced", mcp.Meta{MetaCorrelationID: "bad id\n"}, nil, ""},
+		{"generated", nil, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := CorrelationMiddleware(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+				seen = CorrelationID(ctx)
+				return &mcp.CallToolResult{}, nil
+			})
+			req := &mcp.CallToolRequest{
+				Params: &mcp.CallToolParamsRaw{Meta: tt.meta, Name: "get_campaign"},
+				Extra:  &mcp.RequestExtra{Header: tt.header},
+			}
+			res, err := handler(context.Background(), "tools/call", req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.want != "" && seen != tt.want {
+				t.Errorf("got ID %q, want %q", seen, tt.want)
+			}
+			if tt.want == "" && (len(seen) != 32 || strings.ContainsAny(seen, " \n")) {
+				t.Errorf("expected a generated ID, got %q", seen)
+			}
+			if got := res.(*mcp.CallToolResult).Meta[MetaCorrelationID]; got != seen {
+				t.Errorf("got result _meta ID %v, want %q", got, seen)
+			}
+		})
+	}
+}
+
+func TestCorrelationMiddlewareIgnoresOtherMethods(t *testing.T) {
+	var seen string
+	handler := CorrelationMiddleware(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		seen = CorrelationID(ctx)
+		return nil, nil
+	})
+	if _, err := handler(context.Background(), "tools/list", &mcp.ListToolsRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if seen != "" {
+		t.Errorf("expected no ID outside tools/call, got %q", seen)
+	}
+}
+
+func TestNewCorrelationID(t *testing.T) {
+	a, b := NewCorrelationID(), NewCorrelationID()
+	if a == b {
+		t.Error("expected distinct IDs")
+	}
+	if !validCorrelationID(a) {
+		t.Errorf("generated ID %q is not valid", a)
+	}
+}
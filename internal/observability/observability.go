@@ -6,11 +6,13 @@ package observability
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
 	sdklog "go.opentelemetry.io/otel/sdk/log"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -31,6 +33,10 @@ func InitTracer(ctx context.Context, endpoint, serviceName string) (*sdktrace.Tr
 		return nil, fmt.Errorf("create resource: %w", err)
 	}
 
+	// Propagate W3C trace context, so incoming MCP requests and outgoing
+	// backend calls join their callers' traces.
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
 	if endpoint == "" {
 		tp := sdktrace.NewTracerProvider(sdktrace.WithResource(res))
 		otel.SetTracerProvider(tp)
@@ -82,6 +88,16 @@ func InitLogger(ctx context.Context, endpoint, serviceName string) (*sdklog.Logg
 	return lp, nil
 }
 
+// NewStreamHandler creates a handler writing records at or above level to
+// w, one per line, as JSON or, when format is "text", as key=value pairs.
+func NewStreamHandler(w io.Writer, format string, level slog.Leveler) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
+	if format == "text" {
+		return slog.NewTextHandler(w, opts)
+	}
+	return slog.NewJSONHandler(w, opts)
+}
+
 // FanoutHandler distributes slog records to multiple handlers, enabling
 // simultaneous output to stdout (for container logs) and OTEL (for remote backend).
 type FanoutHandler struct {
@@ -128,3 +144,32 @@ func (h *FanoutHandler) WithGroup(name string) slog.Handler {
 	}
 	return NewFanoutHandler(handlers...)
 }
+
+// LevelHandler drops records below a minimum level before they reach the
+// wrapped handler. The level is read on every record, so a *slog.LevelVar
+// changes it at runtime.
+type LevelHandler struct {
+	level   slog.Leveler
+	handler slog.Handler
+}
+
+// NewLevelHandler creates a handler that passes records at or above level to h.
+func NewLevelHandler(level slog.Leveler, h slog.Handler) *LevelHandler {
+	return &LevelHandler{level: level, handler: h}
+}
+
+func (h *LevelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.handler.Enabled(ctx, level)
+}
+
+func (h *LevelHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *LevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return NewLevelHandler(h.level, h.handler.WithAttrs(attrs))
+}
+
+func (h *LevelHandler) WithGroup(name string) slog.Handler {
+	return NewLevelHandler(h.level, h.handler.WithGroup(name))
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"log/slog"
+	"strings"
 	"testing"
 )
 
@@ -143,3 +144,39 @@ func TestFanoutHandler_WithGroup(t *testing.T) {
 		t.Error("expected 'mygroup' in output")
 	}
 }
+
+func TestLevelHandler(t *testing.T) {
+	var buf bytes.Buffer
+	var level slog.LevelVar
+	level.Set(slog.LevelWarn)
+	logger := slog.New(NewLevelHandler(&level, slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))).With("service", "test")
+
+	logger.Info("dropped")
+	logger.Warn("kept")
+	if bytes.Contains(buf.Bytes(), []byte("dropped")) || !bytes.Contains(buf.Bytes(), []byte("kept")) {
+		t.Errorf("expected only records at or above Warn, got %s", buf.String())
+	}
+
+	// Changing the level applies to loggers already derived from the handler.
+	level.Set(slog.LevelDebug)
+	logger.Debug("now kept")
+	if !bytes.Contains(buf.Bytes(), []byte("now kept")) {
+		t.Errorf("expected Debug records after lowering the level, got %s", buf.String())
+	}
+}
+
+func TestNewStreamHandler(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(NewStreamHandler(&buf, "json", slog.LevelDebug)).Debug("hello", "key", "val")
+	if !bytes.HasPrefix(buf.Bytes(), []byte("{")) || !bytes.Contains(buf.Bytes(), []byte(`"key":"val"`)) {
+		t.Errorf("expected a JSON record, got %s", buf.String())
+	}
+
+	buf.Reset()
+	logger := slog.New(NewStreamHandler(&buf, "text", slog.LevelInfo))
+	logger.Debug("dropped")
+	logger.Info("hello", "key", "val")
+	if got := buf.String(); strings.Contains(got, "dropped") || !strings.Contains(got, "level=INFO msg=hello key=val") {
+		t.Errorf("expected one text record at Info, got %s", got)
+	}
+}
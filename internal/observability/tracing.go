@@ -0,0 +1,50 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package observability
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation scope of spans created by this server.
+const TracerName = "github.com/pidgr/pidgr-mcp"
+
+// TracingMiddleware starts a span for each tools/call request. Backend RPC
+// spans created by the transport package become its children, so a trace
+// shows which backend calls a tool made. The span records the correlation
+// ID when CorrelationMiddleware runs inside it.
+func TracingMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		call, ok := req.(*mcp.CallToolRequest)
+		if !ok || call.Params == nil {
+			return next(ctx, method, req)
+		}
+		ctx, span := otel.Tracer(TracerName).Start(ctx, "tools/call "+call.Params.Name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("mcp.tool.name", call.Params.Name)),
+		)
+		defer span.End()
+
+		res, err := next(ctx, method, req)
+		if result, ok := res.(*mcp.CallToolResult); ok {
+			if id, ok := result.Meta[MetaCorrelationID].(string); ok {
+				span.SetAttributes(attribute.String("pidgr.request_id", id))
+			}
+			if result.IsError {
+				span.SetStatus(codes.Error, "tool returned an error result")
+			}
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return res, err
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package observability
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracingMiddleware(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	handler := TracingMiddleware(CorrelationMiddleware(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return &mcp.CallToolResult{IsError: true}, nil
+	}))
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{
+		Meta: mcp.Meta{MetaCorrelationID: "req-1"},
+		Name: "get_campaign",
+	}}
+	if _, err := handler(context.Background(), "tools/call", req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "tools/call get_campaign" {
+		t.Errorf("got span name %q", span.Name())
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("got status %v, want error for an error result", span.Status().Code)
+	}
+	var id string
+	for _, kv := range span.Attributes() {
+		if kv.Key == "pidgr.request_id" {
+			id = kv.Value.AsString()
+		}
+	}
+	if id != "req-1" {
+		t.Errorf("got pidgr.request_id %q, want req-1", id)
+	}
+}
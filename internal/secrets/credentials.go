@@ -0,0 +1,175 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// ecsCredentialsHost serves task role credentials on ECS.
+	ecsCredentialsHost = "http://169.254.170.2"
+	// imdsEndpoint is the EC2 instance metadata service.
+	imdsEndpoint = "http://169.254.169.254"
+	// imdsTimeout bounds instance metadata calls, which hang rather than
+	// fail outside EC2.
+	imdsTimeout = 2 * time.Second
+	// credentialsMargin renews temporary credentials this long before they
+	// expire.
+	credentialsMargin = 5 * time.Minute
+)
+
+// errNoCredentials is returned when no credential source is configured.
+var errNoCredentials = errors.New("no AWS credentials: set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY, or run with an ECS task role, EKS Pod Identity, or an EC2 instance profile")
+
+// credentials returns AWS credentials from, in order, the environment, the
+// ECS or EKS Pod Identity container endpoint, and the EC2 instance metadata
+// service. Temporary credentials are cached until shortly before they
+// expire.
+func (r *Resolver) credentials(ctx context.Context) (credentials, error) {
+	if id, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"); id != "" && secret != "" {
+		return credentials{AccessKeyID: id, SecretAccessKey: secret, SessionToken: os.Getenv("AWS_SESSION_TOKEN")}, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cached.AccessKeyID != "" && r.now().Add(credentialsMargin).Before(r.cached.Expires) {
+		return r.cached, nil
+	}
+	var c credentials
+	var err error
+	if endpoint, ok := containerEndpoint(); ok {
+		c, err = r.containerCredentials(ctx, endpoint)
+	} else if strings.EqualFold(os.Getenv("AWS_EC2_METADATA_DISABLED"), "true") {
+		err = errNoCredentials
+	} else {
+		c, err = r.instanceCredentials(ctx)
+	}
+	if err != nil {
+		return credentials{}, err
+	}
+	r.cached = c
+	return c, nil
+}
+
+// containerEndpoint returns the container credentials endpoint configured
+// by ECS or EKS Pod Identity.
+func containerEndpoint() (string, bool) {
+	if uri := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); uri != "" {
+		return ecsCredentialsHost + uri, true
+	}
+	if uri := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI"); uri != "" {
+		return uri, true
+	}
+	return "", false
+}
+
+// containerCredentials fetches task or pod credentials from endpoint.
+func (r *Resolver) containerCredentials(ctx context.Context, endpoint string) (credentials, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return credentials{}, fmt.Errorf("container credentials: %w", err)
+	}
+	token := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN")
+	if path := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return credentials{}, fmt.Errorf("container credentials: %w", err)
+		}
+		token = strings.TrimSpace(string(data))
+	}
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+	c, err := r.fetchCredentials(req)
+	if err != nil {
+		return credentials{}, fmt.Errorf("container credentials: %w", err)
+	}
+	return c, nil
+}
+
+// instanceCredentials fetches the instance profile's credentials from the
+// EC2 instance metadata service, using an IMDSv2 session token.
+func (r *Resolver) instanceCredentials(ctx context.Context) (credentials, error) {
+	ctx, cancel := context.WithTimeout(ctx, imdsTimeout)
+	defer cancel()
+	endpoint := imdsEndpoint
+	if e := os.Getenv("AWS_EC2_METADATA_SERVICE_ENDPOINT"); e != "" {
+		endpoint = strings.TrimSuffix(e, "/")
+	}
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPut, endpoint+"/latest/api/token", nil)
+	req.Header.Set("X-Aws-Ec2-Metadata-Token-Ttl-Seconds", "21600")
+	token, err := r.get(req)
+	if err != nil {
+		return credentials{}, fmt.Errorf("%w (instance metadata: %v)", errNoCredentials, err)
+	}
+
+	const rolesPath = "/latest/meta-data/iam/security-credentials/"
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, endpoint+rolesPath, nil)
+	req.Header.Set("X-Aws-Ec2-Metadata-Token", string(token))
+	roles, err := r.get(req)
+	if err != nil {
+		return credentials{}, fmt.Errorf("instance metadata: %w", err)
+	}
+	role, _, _ := strings.Cut(strings.TrimSpace(string(roles)), "\n")
+	if role == "" {
+		return credentials{}, fmt.Errorf("instance metadata: no instance profile role")
+	}
+
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, endpoint+rolesPath+role, nil)
+	req.Header.Set("X-Aws-Ec2-Metadata-Token", string(token))
+	c, err := r.fetchCredentials(req)
+	if err != nil {
+		return credentials{}, fmt.Errorf("instance metadata: %w", err)
+	}
+	return c, nil
+}
+
+// fetchCredentials decodes the credentials document served by the container
+// and instance metadata endpoints.
+func (r *Resolver) fetchCredentials(req *http.Request) (credentials, error) {
+	body, err := r.get(req)
+	if err != nil {
+		return credentials{}, err
+	}
+	var doc struct {
+		AccessKeyID     string `json:"AccessKeyId"`
+		SecretAccessKey string
+		Token           string
+		Expiration      time.Time
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return credentials{}, fmt.Errorf("invalid credentials document: %w", err)
+	}
+	if doc.AccessKeyID == "" || doc.SecretAccessKey == "" {
+		return credentials{}, fmt.Errorf("credentials document has no access key")
+	}
+	return credentials{AccessKeyID: doc.AccessKeyID, SecretAccessKey: doc.SecretAccessKey, SessionToken: doc.Token, Expires: doc.Expiration}, nil
+}
+
+// get performs req and returns the body of a 200 response.
+func (r *Resolver) get(req *http.Request) ([]byte, error) {
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", req.URL.Path, resp.Status)
+	}
+	return body, nil
+}
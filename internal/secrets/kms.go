@@ -0,0 +1,32 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Decrypt decrypts a KMS ciphertext blob in AWS_REGION and returns the
+// plaintext. ciphertext is base64-encoded, as printed by
+// aws kms encrypt --output text --query CiphertextBlob.
+func (r *Resolver) Decrypt(ctx context.Context, ciphertext string) (string, error) {
+	blob, err := base64.StdEncoding.DecodeString(strings.TrimSpace(ciphertext))
+	if err != nil || len(blob) == 0 {
+		return "", fmt.Errorf("KMS ciphertext is not base64")
+	}
+	rf := reference{service: "kms", region: envRegion(), id: "kms"}
+	if rf.region == "" {
+		return "", fmt.Errorf("set AWS_REGION to decrypt with KMS")
+	}
+	var out struct {
+		Plaintext []byte
+	}
+	if err := r.call(ctx, rf, "TrentService.Decrypt", map[string]any{"CiphertextBlob": blob}, &out); err != nil {
+		return "", err
+	}
+	return string(out.Plaintext), nil
+}
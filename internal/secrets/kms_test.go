@@ -0,0 +1,50 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package secrets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDecrypt(t *testing.T) {
+	r := testResolver(t, func(w http.ResponseWriter, req *http.Request) {
+		if got := req.Header.Get("X-Amz-Target"); got != "TrentService.Decrypt" {
+			t.Errorf("X-Amz-Target = %q", got)
+		}
+		var in struct{ CiphertextBlob []byte }
+		_ = json.NewDecoder(req.Body).Decode(&in)
+		if string(in.CiphertextBlob) != "sealed" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"InvalidCiphertextException"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"KeyId": "arn:aws:kms:us-east-1:123456789012:key/k", "Plaintext": []byte("pidgr_k_from_kms")})
+	})
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	got, err := r.Decrypt(context.Background(), base64.StdEncoding.EncodeToString([]byte("sealed"))+"\n")
+	if err != nil || got != "pidgr_k_from_kms" {
+		t.Errorf("Decrypt = %q, %v; want the plaintext", got, err)
+	}
+
+	for in, want := range map[string]string{
+		base64.StdEncoding.EncodeToString([]byte("other")): "InvalidCiphertextException",
+		"not base64!": "not base64",
+	} {
+		if _, err := r.Decrypt(context.Background(), in); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Decrypt(%q) error = %v, want it to contain %q", in, err, want)
+		}
+	}
+
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	if _, err := r.Decrypt(context.Background(), "c2VhbGVk"); err == nil || !strings.Contains(err.Error(), "AWS_REGION") {
+		t.Errorf("got %v, want an error asking for AWS_REGION", err)
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+// Package secrets resolves settings that name a secret stored in AWS Secrets
+// Manager, SSM Parameter Store, or HashiCorp Vault instead of holding its
+// value, and decrypts settings encrypted with AWS KMS, so keys and
+// certificates never appear in task definitions or environment dumps.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// requestTimeout bounds each AWS or Vault API call.
+const requestTimeout = 30 * time.Second
+
+// Resolver fetches secrets from AWS or Vault. It calls their HTTP APIs
+// itself rather than depending on their SDKs, and is safe for concurrent
+// use.
+type Resolver struct {
+	client *http.Client
+	now    func() time.Time
+	// endpoint returns the API endpoint of service in region.
+	endpoint func(service, region string) string
+
+	mu          sync.Mutex
+	cached      credentials
+	vaultClient *vaultClient
+}
+
+// NewResolver creates a resolver. AWS credentials come from the
+// environment, the container credentials endpoint, or the EC2 instance
+// profile; the Vault connection from VAULT_ADDR and related variables (see
+// Resolve). Requests follow HTTP_PROXY, HTTPS_PROXY, and NO_PROXY.
+func NewResolver() *Resolver {
+	return &Resolver{
+		client:   &http.Client{Timeout: requestTimeout},
+		now:      time.Now,
+		endpoint: awsEndpoint,
+	}
+}
+
+// endpointEnv names the variable that overrides each service's endpoint,
+// as in the AWS SDKs.
+var endpointEnv = map[string]string{
+	"kms":            "AWS_ENDPOINT_URL_KMS",
+	"secretsmanager": "AWS_ENDPOINT_URL_SECRETS_MANAGER",
+	"ssm":            "AWS_ENDPOINT_URL_SSM",
+}
+
+// awsEndpoint returns the API endpoint of service in region, honoring the
+// AWS_ENDPOINT_URL overrides used for VPC endpoints and local emulators.
+func awsEndpoint(service, region string) string {
+	if url := os.Getenv(endpointEnv[service]); url != "" {
+		return strings.TrimSuffix(url, "/")
+	}
+	if url := os.Getenv("AWS_ENDPOINT_URL"); url != "" {
+		return strings.TrimSuffix(url, "/")
+	}
+	domain := "amazonaws.com"
+	if strings.HasPrefix(region, "cn-") {
+		domain = "amazonaws.com.cn"
+	}
+	return "https://" + service + "." + region + "." + domain
+}
+
+// reference identifies one secret.
+type reference struct {
+	service string // secretsmanager, ssm, or vault
+	region  string
+	id      string // secret ARN, parameter ARN or name, or Vault path
+	field   string // Vault secret field, or key of a JSON Secrets Manager secret
+}
+
+// parseReference recognizes the secret references a setting may hold:
+//
+//	arn:aws:secretsmanager:REGION:ACCOUNT:secret:NAME[#KEY]
+//	arn:aws:ssm:REGION:ACCOUNT:parameter/NAME
+//	ssm:/NAME (a parameter in AWS_REGION)
+//	vault:PATH#FIELD
+func parseReference(v string) (reference, bool) {
+	if rest, ok := strings.CutPrefix(v, "vault:"); ok {
+		path, field, found := strings.Cut(rest, "#")
+		if !found || strings.Trim(path, "/") == "" || field == "" {
+			return reference{}, false
+		}
+		return reference{service: "vault", id: path, field: field}, true
+	}
+	if name, ok := strings.CutPrefix(v, "ssm:"); ok && strings.HasPrefix(name, "/") {
+		return reference{service: "ssm", region: envRegion(), id: name}, true
+	}
+	parts := strings.SplitN(v, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" || !strings.HasPrefix(parts[1], "aws") || parts[3] == "" {
+		return reference{}, false
+	}
+	switch {
+	case parts[2] == "secretsmanager" && strings.HasPrefix(parts[5], "secret:"):
+		// Secret names cannot contain #, so it always starts a key.
+		id, key, found := strings.Cut(v, "#")
+		if found && key == "" {
+			return reference{}, false
+		}
+		return reference{service: "secretsmanager", region: parts[3], id: id, field: key}, true
+	case parts[2] == "ssm" && strings.HasPrefix(parts[5], "parameter/"):
+		return reference{service: "ssm", region: parts[3], id: v}, true
+	}
+	return reference{}, false
+}
+
+func envRegion() string {
+	if region := os.Getenv("AWS_REGION"); region != "" {
+		return region
+	}
+	return os.Getenv("AWS_DEFAULT_REGION")
+}
+
+// IsReference reports whether v names a Secrets Manager secret, SSM
+// parameter, or Vault secret (see Resolve) rather than holding a value.
+func IsReference(v string) bool {
+	_, ok := parseReference(v)
+	return ok
+}
+
+// Resolve returns the current value of the secret ref names: a Secrets
+// Manager secret ARN, optionally followed by #KEY to select one key of a
+// secret stored as a JSON object, an SSM parameter ARN, an SSM parameter name
+// written as ssm:/NAME, which is looked up in AWS_REGION, or a field of a
+// Vault KV secret written as vault:PATH#FIELD. SecureString parameters are
+// decrypted.
+//
+// Vault is reached at VAULT_ADDR (in VAULT_NAMESPACE, trusting VAULT_CACERT)
+// with VAULT_TOKEN or, when PIDGR_VAULT_ROLE is set instead, a token from
+// logging in as that role with the Kubernetes service account token (or
+// PIDGR_VAULT_JWT_FILE) at the auth method mounted at PIDGR_VAULT_AUTH_MOUNT
+// (default kubernetes). The login token is renewed as its lease runs out.
+func (r *Resolver) Resolve(ctx context.Context, ref string) (string, error) {
+	rf, ok := parseReference(ref)
+	if !ok {
+		return "", fmt.Errorf("%q is not a Secrets Manager, SSM parameter, or Vault reference", ref)
+	}
+	if rf.service == "vault" {
+		v, err := r.vault()
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", ref, err)
+		}
+		return v.read(ctx, rf.id, rf.field)
+	}
+	if rf.region == "" {
+		return "", fmt.Errorf("%s: set AWS_REGION to look up SSM parameters by name", ref)
+	}
+	switch rf.service {
+	case "secretsmanager":
+		var out struct {
+			SecretString string
+			SecretBinary []byte
+		}
+		if err := r.call(ctx, rf, "secretsmanager.GetSecretValue", map[string]any{"SecretId": rf.id}, &out); err != nil {
+			return "", err
+		}
+		if rf.field != "" {
+			return jsonKey(rf, out.SecretString)
+		}
+		if out.SecretString != "" {
+			return out.SecretString, nil
+		}
+		return string(out.SecretBinary), nil
+	default:
+		var out struct {
+			Parameter struct {
+				Value string
+			}
+		}
+		if err := r.call(ctx, rf, "AmazonSSM.GetParameter", map[string]any{"Name": rf.id, "WithDecryption": true}, &out); err != nil {
+			return "", err
+		}
+		return out.Parameter.Value, nil
+	}
+}
+
+// jsonKey returns the value of rf.field in a secret stored as a JSON object.
+// Values that are not strings are returned as JSON.
+func jsonKey(rf reference, secret string) (string, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(secret), &obj); err != nil {
+		return "", fmt.Errorf("%s: secret is not a JSON object, so key %q cannot be selected", rf.id, rf.field)
+	}
+	raw, ok := obj[rf.field]
+	if !ok {
+		return "", fmt.Errorf("%s: secret has no key %q", rf.id, rf.field)
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s, nil
+	}
+	return string(raw), nil
+}
+
+// call invokes an AWS JSON API operation, named by target, and decodes the
+// response into out.
+func (r *Resolver) call(ctx context.Context, rf reference, target string, in, out any) error {
+	creds, err := r.credentials(ctx)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint(rf.service, rf.region)+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", target)
+	sign(req, body, rf.service, rf.region, creds, r.now())
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", rf.id, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("%s: %w", rf.id, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", rf.id, apiError(resp.Status, data))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", rf.id, err)
+	}
+	return nil
+}
+
+// apiError extracts the error type and message from an AWS JSON error
+// response, e.g. "ResourceNotFoundException: Secrets Manager can't find the
+// specified secret."
+func apiError(status string, data []byte) string {
+	var e struct {
+		Type         string `json:"__type"`
+		Message      string `json:"message"`
+		MessageUpper string `json:"Message"`
+	}
+	if json.Unmarshal(data, &e) != nil || e.Type == "" {
+		return status
+	}
+	_, kind, found := strings.Cut(e.Type, "#")
+	if !found {
+		kind = e.Type
+	}
+	msg := e.Message
+	if msg == "" {
+		msg = e.MessageUpper
+	}
+	if msg == "" {
+		return kind
+	}
+	return kind + ": " + msg
+}
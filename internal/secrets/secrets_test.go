@@ -0,0 +1,210 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseReference(t *testing.T) {
+	t.Setenv("AWS_REGION", "eu-west-1")
+	tests := []struct {
+		in   string
+		want reference
+		ok   bool
+	}{
+		{"arn:aws:secretsmanager:us-east-1:123456789012:secret:pidgr/api-key-AbCdEf",
+			reference{"secretsmanager", "us-east-1", "arn:aws:secretsmanager:us-east-1:123456789012:secret:pidgr/api-key-AbCdEf", ""}, true},
+		{"arn:aws:secretsmanager:us-east-1:123456789012:secret:pidgr/mcp-AbCdEf#api_key",
+			reference{"secretsmanager", "us-east-1", "arn:aws:secretsmanager:us-east-1:123456789012:secret:pidgr/mcp-AbCdEf", "api_key"}, true},
+		{"arn:aws:secretsmanager:us-east-1:123456789012:secret:pidgr/mcp-AbCdEf#", reference{}, false},
+		{"arn:aws:ssm:us-west-2:123456789012:parameter/pidgr/api-key",
+			reference{"ssm", "us-west-2", "arn:aws:ssm:us-west-2:123456789012:parameter/pidgr/api-key", ""}, true},
+		{"ssm:/pidgr/api-key", reference{"ssm", "eu-west-1", "/pidgr/api-key", ""}, true},
+		{"pidgr_k_abc123", reference{}, false},
+		{"ssm:pidgr", reference{}, false},
+		{"arn:aws:s3:::bucket/key", reference{}, false},
+		{"arn:aws:secretsmanager::123456789012:secret:x", reference{}, false},
+		{"vault:secret/data/pidgr/mcp#api_key", reference{"vault", "", "secret/data/pidgr/mcp", "api_key"}, true},
+		{"vault:secret/data/pidgr/mcp", reference{}, false},
+		{"vault:#api_key", reference{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseReference(tt.in)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseReference(%q) = %+v, %v; want %+v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAWSEndpoint(t *testing.T) {
+	if got := awsEndpoint("ssm", "us-east-1"); got != "https://ssm.us-east-1.amazonaws.com" {
+		t.Errorf("awsEndpoint = %s", got)
+	}
+	if got := awsEndpoint("secretsmanager", "cn-north-1"); got != "https://secretsmanager.cn-north-1.amazonaws.com.cn" {
+		t.Errorf("awsEndpoint = %s", got)
+	}
+	t.Setenv("AWS_ENDPOINT_URL", "http://localhost:4566/")
+	t.Setenv("AWS_ENDPOINT_URL_SSM", "https://vpce-123.ssm.us-east-1.vpce.amazonaws.com")
+	if got := awsEndpoint("secretsmanager", "us-east-1"); got != "http://localhost:4566" {
+		t.Errorf("awsEndpoint with AWS_ENDPOINT_URL = %s", got)
+	}
+	if got := awsEndpoint("ssm", "us-east-1"); got != "https://vpce-123.ssm.us-east-1.vpce.amazonaws.com" {
+		t.Errorf("awsEndpoint with AWS_ENDPOINT_URL_SSM = %s", got)
+	}
+}
+
+// testResolver returns a resolver whose API calls go to handler, with
+// static credentials from the environment.
+func testResolver(t *testing.T, handler http.HandlerFunc) *Resolver {
+	t.Helper()
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	r := NewResolver()
+	r.endpoint = func(service, region string) string { return ts.URL }
+	return r
+}
+
+func TestResolve(t *testing.T) {
+	r := testResolver(t, func(w http.ResponseWriter, req *http.Request) {
+		if !strings.HasPrefix(req.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			t.Errorf("request is not signed: %q", req.Header.Get("Authorization"))
+		}
+		var in map[string]any
+		_ = json.NewDecoder(req.Body).Decode(&in)
+		switch req.Header.Get("X-Amz-Target") {
+		case "secretsmanager.GetSecretValue":
+			if strings.HasSuffix(in["SecretId"].(string), "pidgr/mcp-AbCdEf") {
+				_, _ = fmt.Fprintf(w, `{"ARN":%q,"SecretString":"{\"api_key\":\"pidgr_k_from_json\",\"port\":443}"}`, in["SecretId"])
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"ARN":%q,"SecretString":"pidgr_k_from_sm"}`, in["SecretId"])
+		case "AmazonSSM.GetParameter":
+			if in["WithDecryption"] != true {
+				t.Error("expected WithDecryption")
+			}
+			_, _ = fmt.Fprintf(w, `{"Parameter":{"Name":%q,"Value":"pidgr_k_from_ssm"}}`, in["Name"])
+		default:
+			t.Errorf("unexpected target %q", req.Header.Get("X-Amz-Target"))
+		}
+	})
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	tests := map[string]string{
+		"arn:aws:secretsmanager:us-east-1:123456789012:secret:pidgr/api-key-AbCdEf":     "pidgr_k_from_sm",
+		"arn:aws:secretsmanager:us-east-1:123456789012:secret:pidgr/mcp-AbCdEf#api_key": "pidgr_k_from_json",
+		"arn:aws:secretsmanager:us-east-1:123456789012:secret:pidgr/mcp-AbCdEf#port":    "443",
+		"arn:aws:ssm:us-east-1:123456789012:parameter/pidgr/api-key":                    "pidgr_k_from_ssm",
+		"ssm:/pidgr/api-key": "pidgr_k_from_ssm",
+	}
+	for ref, want := range tests {
+		got, err := r.Resolve(context.Background(), ref)
+		if err != nil || got != want {
+			t.Errorf("Resolve(%q) = %q, %v; want %q", ref, got, err, want)
+		}
+	}
+}
+
+func TestResolveError(t *testing.T) {
+	r := testResolver(t, func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`))
+	})
+	_, err := r.Resolve(context.Background(), "arn:aws:secretsmanager:us-east-1:123456789012:secret:missing")
+	if err == nil || !strings.Contains(err.Error(), "ResourceNotFoundException: Secrets Manager can't find the specified secret.") {
+		t.Errorf("got %v, want the AWS error", err)
+	}
+
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	if _, err := r.Resolve(context.Background(), "ssm:/pidgr/api-key"); err == nil || !strings.Contains(err.Error(), "AWS_REGION") {
+		t.Errorf("got %v, want an error asking for AWS_REGION", err)
+	}
+}
+
+func TestContainerCredentials(t *testing.T) {
+	var fetches atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fetches.Add(1)
+		if req.Header.Get("Authorization") != "pod-token" {
+			t.Errorf("Authorization = %q, want the pod identity token", req.Header.Get("Authorization"))
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"AccessKeyId": "ASIA", "SecretAccessKey": "secret", "Token": "session",
+			"Expiration": time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		})
+	}))
+	defer ts.Close()
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", ts.URL+"/v1/credentials")
+	t.Setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN", "pod-token")
+
+	r := NewResolver()
+	for i := 0; i < 2; i++ {
+		c, err := r.credentials(context.Background())
+		if err != nil {
+			t.Fatalf("credentials: %v", err)
+		}
+		if c.AccessKeyID != "ASIA" || c.SessionToken != "session" {
+			t.Errorf("credentials = %+v", c)
+		}
+	}
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("fetched credentials %d times, want them cached", n)
+	}
+
+	// Credentials about to expire are renewed.
+	r.now = func() time.Time { return time.Now().Add(58 * time.Minute) }
+	if _, err := r.credentials(context.Background()); err != nil {
+		t.Fatalf("credentials: %v", err)
+	}
+	if n := fetches.Load(); n != 2 {
+		t.Errorf("fetched credentials %d times, want expiring ones renewed", n)
+	}
+}
+
+func TestInstanceCredentials(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == http.MethodPut && req.URL.Path == "/latest/api/token":
+			_, _ = w.Write([]byte("imds-token"))
+		case req.Header.Get("X-Aws-Ec2-Metadata-Token") != "imds-token":
+			w.WriteHeader(http.StatusUnauthorized)
+		case req.URL.Path == "/latest/meta-data/iam/security-credentials/":
+			_, _ = w.Write([]byte("pidgr-mcp-role\n"))
+		case req.URL.Path == "/latest/meta-data/iam/security-credentials/pidgr-mcp-role":
+			_, _ = w.Write([]byte(`{"Code":"Success","AccessKeyId":"ASIAEC2","SecretAccessKey":"secret","Token":"session","Expiration":"2099-01-01T00:00:00Z"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", "")
+	t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", ts.URL)
+
+	c, err := NewResolver().credentials(context.Background())
+	if err != nil {
+		t.Fatalf("credentials: %v", err)
+	}
+	if c.AccessKeyID != "ASIAEC2" || c.SessionToken != "session" {
+		t.Errorf("credentials = %+v", c)
+	}
+
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	if _, err := NewResolver().credentials(context.Background()); err != errNoCredentials {
+		t.Errorf("got %v, want errNoCredentials", err)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package secrets
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The example request and keys from the AWS Signature Version 4 documentation.
+const (
+	exampleAccessKey = "AKIDEXAMPLE"
+	exampleSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+)
+
+func TestSigningKey(t *testing.T) {
+	got := hex.EncodeToString(signingKey(exampleSecretKey, "20150830", "us-east-1", "iam"))
+	want := "c4afb1cc5771d871763a393e44b703571b55cc28424d1a5e86da6ed3c154a4b9"
+	if got != want {
+		t.Errorf("signingKey = %s, want %s", got, want)
+	}
+}
+
+func TestSign(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	sign(req, nil, "iam", "us-east-1", credentials{AccessKeyID: exampleAccessKey, SecretAccessKey: exampleSecretKey}, now)
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, " +
+		"SignedHeaders=content-type;host;x-amz-date, " +
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization =\n%s\nwant\n%s", got, want)
+	}
+	if got := req.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+		t.Errorf("X-Amz-Date = %s", got)
+	}
+}
+
+func TestSignSessionToken(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "https://ssm.eu-west-1.amazonaws.com/", nil)
+	sign(req, []byte("{}"), "ssm", "eu-west-1", credentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "token"}, time.Now())
+	if req.Header.Get("X-Amz-Security-Token") != "token" {
+		t.Error("expected the session token header")
+	}
+	if got := req.Header.Get("Authorization"); !strings.Contains(got, "SignedHeaders=host;x-amz-date;x-amz-security-token,") {
+		t.Errorf("expected the session token to be signed, got %s", got)
+	}
+}
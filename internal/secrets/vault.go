@@ -0,0 +1,231 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultServiceAccountToken is the Kubernetes service account token,
+// presented to Vault's Kubernetes auth method.
+const defaultServiceAccountToken = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// vaultConfig is the Vault connection, read from the environment on each
+// lookup so a configuration reload can change it.
+type vaultConfig struct {
+	addr      string // VAULT_ADDR
+	namespace string // VAULT_NAMESPACE
+	caCert    string // VAULT_CACERT
+	token     string // VAULT_TOKEN; when set, no login is made
+	role      string // PIDGR_VAULT_ROLE
+	mount     string // PIDGR_VAULT_AUTH_MOUNT
+	jwtFile   string // PIDGR_VAULT_JWT_FILE
+}
+
+func vaultConfigFromEnv() vaultConfig {
+	return vaultConfig{
+		addr:      strings.TrimSuffix(os.Getenv("VAULT_ADDR"), "/"),
+		namespace: os.Getenv("VAULT_NAMESPACE"),
+		caCert:    os.Getenv("VAULT_CACERT"),
+		token:     os.Getenv("VAULT_TOKEN"),
+		role:      os.Getenv("PIDGR_VAULT_ROLE"),
+		mount:     getenv("PIDGR_VAULT_AUTH_MOUNT", "kubernetes"),
+		jwtFile:   getenv("PIDGR_VAULT_JWT_FILE", defaultServiceAccountToken),
+	}
+}
+
+func getenv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// vaultClient reads secrets from Vault, logging in with the configured role
+// and renewing its token before the lease runs out.
+type vaultClient struct {
+	cfg    vaultConfig
+	client *http.Client
+	now    func() time.Time
+
+	mu      sync.Mutex
+	token   string
+	renewAt time.Time // zero for tokens that do not expire
+	expires time.Time
+}
+
+func newVaultClient(cfg vaultConfig, now func() time.Time) (*vaultClient, error) {
+	if cfg.addr == "" {
+		return nil, fmt.Errorf("VAULT_ADDR is not set")
+	}
+	if cfg.token == "" && cfg.role == "" {
+		return nil, fmt.Errorf("set VAULT_TOKEN, or PIDGR_VAULT_ROLE to log in to Vault")
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.caCert != "" {
+		pem, err := os.ReadFile(cfg.caCert)
+		if err != nil {
+			return nil, fmt.Errorf("VAULT_CACERT: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("VAULT_CACERT: no PEM certificates in %s", cfg.caCert)
+		}
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12, RootCAs: pool}
+	}
+	return &vaultClient{
+		cfg:    cfg,
+		client: &http.Client{Transport: transport, Timeout: requestTimeout},
+		now:    now,
+		token:  cfg.token,
+	}, nil
+}
+
+// vaultAuth is the auth block of a login or renewal response.
+type vaultAuth struct {
+	ClientToken   string `json:"client_token"`
+	LeaseDuration int    `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+}
+
+// authToken returns a valid Vault token. A login token is renewed once two
+// thirds of its lease has passed; if it cannot be renewed (it has reached
+// its max TTL, or was revoked) the client logs in again.
+func (v *vaultClient) authToken(ctx context.Context) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	now := v.now()
+	if v.token != "" && (v.renewAt.IsZero() || now.Before(v.renewAt)) {
+		return v.token, nil
+	}
+	if v.token != "" && now.Before(v.expires) {
+		var out struct{ Auth vaultAuth }
+		if err := v.do(ctx, http.MethodPost, "auth/token/renew-self", v.token, struct{}{}, &out); err == nil && out.Auth.ClientToken != "" {
+			v.setToken(out.Auth, now)
+			return v.token, nil
+		}
+	}
+	jwt, err := os.ReadFile(v.cfg.jwtFile)
+	if err != nil {
+		return "", fmt.Errorf("vault login: %w", err)
+	}
+	var out struct{ Auth vaultAuth }
+	login := map[string]string{"role": v.cfg.role, "jwt": strings.TrimSpace(string(jwt))}
+	if err := v.do(ctx, http.MethodPost, "auth/"+v.cfg.mount+"/login", "", login, &out); err != nil {
+		return "", fmt.Errorf("vault login as %s: %w", v.cfg.role, err)
+	}
+	if out.Auth.ClientToken == "" {
+		return "", fmt.Errorf("vault login as %s: no token in response", v.cfg.role)
+	}
+	v.setToken(out.Auth, now)
+	return v.token, nil
+}
+
+func (v *vaultClient) setToken(auth vaultAuth, now time.Time) {
+	v.token = auth.ClientToken
+	v.renewAt, v.expires = time.Time{}, time.Time{}
+	if auth.LeaseDuration > 0 {
+		lease := time.Duration(auth.LeaseDuration) * time.Second
+		v.renewAt = now.Add(lease * 2 / 3)
+		v.expires = now.Add(lease)
+	}
+}
+
+// read returns one field of the secret at path. Both KV version 1 and
+// version 2 (paths with /data/) secrets are supported.
+func (v *vaultClient) read(ctx context.Context, path, field string) (string, error) {
+	token, err := v.authToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	var out struct {
+		Data map[string]json.RawMessage
+	}
+	if err := v.do(ctx, http.MethodGet, path, token, nil, &out); err != nil {
+		return "", fmt.Errorf("vault %s: %w", path, err)
+	}
+	data := out.Data
+	if raw, ok := data["data"]; ok && data["metadata"] != nil {
+		// KV version 2 wraps the secret with its metadata.
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return "", fmt.Errorf("vault %s: %w", path, err)
+		}
+	}
+	raw, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("vault %s: no field %q", path, field)
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return "", fmt.Errorf("vault %s: field %q is not a string", path, field)
+	}
+	return value, nil
+}
+
+// do calls the Vault HTTP API at /v1/path.
+func (v *vaultClient) do(ctx context.Context, method, path, token string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, v.cfg.addr+"/v1/"+strings.TrimPrefix(path, "/"), body)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if v.cfg.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.cfg.namespace)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct{ Errors []string }
+		if json.Unmarshal(data, &e) == nil && len(e.Errors) > 0 {
+			return fmt.Errorf("%s: %s", resp.Status, strings.Join(e.Errors, "; "))
+		}
+		return fmt.Errorf("%s", resp.Status)
+	}
+	return json.Unmarshal(data, out)
+}
+
+// vault returns the Vault client for the current environment, creating a new
+// one (and logging in again) when the Vault settings have changed.
+func (r *Resolver) vault() (*vaultClient, error) {
+	cfg := vaultConfigFromEnv()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.vaultClient != nil && r.vaultClient.cfg == cfg {
+		return r.vaultClient, nil
+	}
+	v, err := newVaultClient(cfg, r.now)
+	if err != nil {
+		return nil, err
+	}
+	r.vaultClient = v
+	return v, nil
+}
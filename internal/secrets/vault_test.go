@@ -0,0 +1,171 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeVault serves Kubernetes logins for role pidgr-mcp, token renewal, and
+// a KV v1 and a KV v2 secret.
+type fakeVault struct {
+	logins, renewals atomic.Int32
+	renewable        bool
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	reply := func(v any) { _ = json.NewEncoder(w).Encode(v) }
+	switch r.URL.Path {
+	case "/v1/auth/kubernetes/login":
+		var in map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		if in["role"] != "pidgr-mcp" || in["jwt"] != "sa-token" {
+			w.WriteHeader(http.StatusBadRequest)
+			reply(map[string]any{"errors": []string{"invalid role or jwt"}})
+			return
+		}
+		n := f.logins.Add(1)
+		reply(map[string]any{"auth": vaultAuth{ClientToken: "login-" + string(rune('0'+n)), LeaseDuration: 3600, Renewable: true}})
+	case "/v1/auth/token/renew-self":
+		f.renewals.Add(1)
+		if !f.renewable {
+			w.WriteHeader(http.StatusBadRequest)
+			reply(map[string]any{"errors": []string{"lease is not renewable"}})
+			return
+		}
+		reply(map[string]any{"auth": vaultAuth{ClientToken: r.Header.Get("X-Vault-Token"), LeaseDuration: 3600, Renewable: true}})
+	case "/v1/secret/data/pidgr/mcp":
+		if !strings.HasPrefix(r.Header.Get("X-Vault-Token"), "login-") && r.Header.Get("X-Vault-Token") != "static" {
+			w.WriteHeader(http.StatusForbidden)
+			reply(map[string]any{"errors": []string{"permission denied"}})
+			return
+		}
+		reply(map[string]any{"data": map[string]any{
+			"data":     map[string]any{"api_key": "pidgr_k_from_vault", "retries": 3},
+			"metadata": map[string]any{"version": 2},
+		}})
+	case "/v1/kv/pidgr":
+		reply(map[string]any{"data": map[string]any{"api_key": "pidgr_k_from_kv1"}})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		reply(map[string]any{"errors": []string{}})
+	}
+}
+
+// useVault points the environment at a fake Vault and returns it.
+func useVault(t *testing.T) *fakeVault {
+	t.Helper()
+	f := &fakeVault{renewable: true}
+	ts := httptest.NewServer(f)
+	t.Cleanup(ts.Close)
+	jwt := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(jwt, []byte("sa-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VAULT_ADDR", ts.URL)
+	t.Setenv("VAULT_TOKEN", "")
+	t.Setenv("PIDGR_VAULT_ROLE", "pidgr-mcp")
+	t.Setenv("PIDGR_VAULT_JWT_FILE", jwt)
+	return f
+}
+
+func TestResolveVault(t *testing.T) {
+	f := useVault(t)
+	r := NewResolver()
+	tests := map[string]string{
+		"vault:secret/data/pidgr/mcp#api_key": "pidgr_k_from_vault",
+		"vault:/kv/pidgr#api_key":             "pidgr_k_from_kv1",
+	}
+	for ref, want := range tests {
+		got, err := r.Resolve(context.Background(), ref)
+		if err != nil || got != want {
+			t.Errorf("Resolve(%q) = %q, %v; want %q", ref, got, err, want)
+		}
+	}
+	if n := f.logins.Load(); n != 1 {
+		t.Errorf("logged in %d times, want the token reused", n)
+	}
+
+	for ref, want := range map[string]string{
+		"vault:secret/data/pidgr/mcp#missing": `no field "missing"`,
+		"vault:secret/data/pidgr/mcp#retries": "is not a string",
+		"vault:secret/data/other#api_key":     "404",
+	} {
+		if _, err := r.Resolve(context.Background(), ref); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Resolve(%q) error = %v, want it to contain %q", ref, err, want)
+		}
+	}
+}
+
+func TestVaultStaticToken(t *testing.T) {
+	f := useVault(t)
+	t.Setenv("VAULT_TOKEN", "static")
+	if _, err := NewResolver().Resolve(context.Background(), "vault:secret/data/pidgr/mcp#api_key"); err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if n := f.logins.Load(); n != 0 {
+		t.Errorf("logged in %d times with VAULT_TOKEN set", n)
+	}
+}
+
+func TestVaultLoginErrors(t *testing.T) {
+	useVault(t)
+	t.Setenv("PIDGR_VAULT_ROLE", "other")
+	_, err := NewResolver().Resolve(context.Background(), "vault:secret/data/pidgr/mcp#api_key")
+	if err == nil || !strings.Contains(err.Error(), "invalid role or jwt") {
+		t.Errorf("got %v, want the Vault login error", err)
+	}
+
+	t.Setenv("PIDGR_VAULT_ROLE", "")
+	_, err = NewResolver().Resolve(context.Background(), "vault:secret/data/pidgr/mcp#api_key")
+	if err == nil || !strings.Contains(err.Error(), "PIDGR_VAULT_ROLE") {
+		t.Errorf("got %v, want an error naming the missing settings", err)
+	}
+}
+
+func TestVaultTokenRenewal(t *testing.T) {
+	f := useVault(t)
+	now := time.Now()
+	r := NewResolver()
+	r.now = func() time.Time { return now }
+	resolve := func() {
+		t.Helper()
+		if _, err := r.Resolve(context.Background(), "vault:secret/data/pidgr/mcp#api_key"); err != nil {
+			t.Fatalf("Resolve: %v", err)
+		}
+	}
+
+	resolve()
+	// Two thirds into the lease, the token is renewed rather than replaced.
+	now = now.Add(41 * time.Minute)
+	resolve()
+	if f.renewals.Load() != 1 || f.logins.Load() != 1 {
+		t.Errorf("got %d renewals and %d logins, want the token renewed", f.renewals.Load(), f.logins.Load())
+	}
+
+	// A token that can no longer be renewed is replaced by a new login.
+	f.renewable = false
+	now = now.Add(41 * time.Minute)
+	resolve()
+	if f.logins.Load() != 2 {
+		t.Errorf("got %d logins, want a new login once renewal fails", f.logins.Load())
+	}
+
+	// A token past its lease is not renewed.
+	now = now.Add(2 * time.Hour)
+	renewals := f.renewals.Load()
+	resolve()
+	if f.renewals.Load() != renewals || f.logins.Load() != 3 {
+		t.Errorf("expected an expired token to be replaced by a login without renewing")
+	}
+}
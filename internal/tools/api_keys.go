@@ -5,6 +5,7 @@ package tools
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"connectrpc.com/connect"
@@ -23,12 +24,22 @@ type CreateApiKeyInput struct {
 	ExpiresAt   string   `json:"expires_at,omitempty" jsonschema:"Optional expiration time in RFC 3339 format"`
 }
 
-type ListApiKeysInput struct{}
+type ListApiKeysInput struct {
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+	SummaryOnly  bool     `json:"summary_only,omitempty" jsonschema:"Return only the item count, breakdowns by status and other enum fields, and the first few items"`
+}
 
 type RevokeApiKeyInput struct {
 	ApiKeyID string `json:"api_key_id" jsonschema:"API key UUID to revoke"`
 }
 
+type RotateApiKeyInput struct {
+	ApiKeyID  string `json:"api_key_id" jsonschema:"API key UUID to rotate"`
+	Name      string `json:"name,omitempty" jsonschema:"Label for the replacement key (defaults to the old key's name)"`
+	ExpiresAt string `json:"expires_at,omitempty" jsonschema:"Expiration for the replacement key in RFC 3339 format (defaults to the old key's expiration)"`
+}
+
 // ── Registration ────────────────────────────────────────────────────────────
 
 func registerApiKeyTools(s *mcp.Server, c *transport.Clients) {
@@ -45,16 +56,21 @@ func registerApiKeyTools(s *mcp.Server, c *transport.Clients) {
 			}
 			expiresAt = timestamppb.New(t)
 		}
+		perms, err := toProtoPermissions(input.Permissions)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
 		resp, err := c.ApiKeys.CreateApiKey(ctx, connect.NewRequest(&pidgrv1.CreateApiKeyRequest{
 			Name:        input.Name,
-			Permissions: toProtoPermissions(input.Permissions),
+			Permissions: perms,
 			ExpiresAt:   expiresAt,
 		}))
 		if err != nil {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -67,7 +83,7 @@ func registerApiKeyTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -84,4 +100,66 @@ func registerApiKeyTools(s *mcp.Server, c *transport.Clients) {
 		}
 		return convert.SuccessResult("API key revoked successfully"), nil, nil
 	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "rotate_api_key",
+		Description: "Replace an API key with a new one that has the same permissions and expiration, then revoke the old key. The old key is only revoked after the new key is created, and the new secret is only returned once. Use list_api_keys to find the API key UUID.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input RotateApiKeyInput) (*mcp.CallToolResult, any, error) {
+		keys, err := c.ApiKeys.ListApiKeys(ctx, connect.NewRequest(&pidgrv1.ListApiKeysRequest{}))
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		var old *pidgrv1.ApiKey
+		for _, k := range keys.Msg.GetApiKeys() {
+			if k.GetId() == input.ApiKeyID {
+				old = k
+				break
+			}
+		}
+		if old == nil {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeNotFound, fmt.Errorf("no active API key with ID %s", input.ApiKeyID)))
+			return r, nil, nil
+		}
+
+		name := input.Name
+		if name == "" {
+			name = old.GetName()
+		}
+		expiresAt := old.GetExpiresAt()
+		if input.ExpiresAt != "" {
+			t, err := time.Parse(time.RFC3339, input.ExpiresAt)
+			if err != nil {
+				r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invalid expires_at: %w", err)))
+				return r, nil, nil
+			}
+			expiresAt = timestamppb.New(t)
+		}
+
+		resp, err := c.ApiKeys.CreateApiKey(ctx, connect.NewRequest(&pidgrv1.CreateApiKeyRequest{
+			Name:        name,
+			Permissions: old.GetPermissions(),
+			ExpiresAt:   expiresAt,
+		}))
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		r, err := convert.ProtoResult(ctx, resp.Msg)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		_, err = c.ApiKeys.RevokeApiKey(ctx, connect.NewRequest(&pidgrv1.RevokeApiKeyRequest{
+			ApiKeyId: old.GetId(),
+		}))
+		if err != nil {
+			// The new secret cannot be retrieved again, so still return it.
+			r.Content = append(r.Content, &mcp.TextContent{Text: fmt.Sprintf(
+				"Warning: the replacement key was created but the old key %s could not be revoked (%s). Revoke it with revoke_api_key.",
+				old.GetId(), convert.ErrorMessage(err),
+			)})
+		}
+		return r, nil, nil
+	})
 }
@@ -0,0 +1,88 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pidgr/pidgr-mcp/internal/convert"
+	"github.com/pidgr/pidgr-mcp/internal/transport"
+	pidgrv1 "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1"
+)
+
+// ── Input types ─────────────────────────────────────────────────────────────
+
+type GetAudienceSizesInput struct {
+	GroupIDs []string `json:"group_ids,omitempty" jsonschema:"Group UUIDs to size (max 100)"`
+	TeamIDs  []string `json:"team_ids,omitempty" jsonschema:"Team UUIDs to size (max 100)"`
+}
+
+// ── Output types ────────────────────────────────────────────────────────────
+
+type AudienceSize struct {
+	ID          string `json:"id"`
+	Kind        string `json:"kind"`
+	Name        string `json:"name,omitempty"`
+	MemberCount int32  `json:"memberCount"`
+	Error       string `json:"error,omitempty"`
+}
+
+type AudienceSizes struct {
+	Audiences []AudienceSize `json:"audiences"`
+	// TotalMembers sums member counts; users in several audiences are counted
+	// once per audience.
+	TotalMembers int64 `json:"totalMembers"`
+}
+
+// ── Registration ────────────────────────────────────────────────────────────
+
+func registerAudienceTools(s *mcp.Server, c *transport.Clients) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "get_audience_sizes",
+		Description: "Get member counts for a list of groups and/or teams to estimate campaign reach without listing members. The total counts users once per audience. Use list_groups and list_teams to find the UUIDs.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input GetAudienceSizesInput) (*mcp.CallToolResult, any, error) {
+		if err := validateBatchSize(input.GroupIDs, 100); err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		if err := validateBatchSize(input.TeamIDs, 100); err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+
+		result := AudienceSizes{Audiences: []AudienceSize{}}
+		for _, id := range input.GroupIDs {
+			size := AudienceSize{ID: id, Kind: "group"}
+			resp, err := c.Groups.GetGroup(ctx, connect.NewRequest(&pidgrv1.GetGroupRequest{
+				GroupId: id,
+			}))
+			if err != nil {
+				size.Error = convert.ErrorMessage(err)
+			} else {
+				size.Name = resp.Msg.GetGroup().GetName()
+				size.MemberCount = resp.Msg.GetGroup().GetMemberCount()
+			}
+			result.Audiences = append(result.Audiences, size)
+			result.TotalMembers += int64(size.MemberCount)
+		}
+		for _, id := range input.TeamIDs {
+			size := AudienceSize{ID: id, Kind: "team"}
+			resp, err := c.Teams.GetTeam(ctx, connect.NewRequest(&pidgrv1.GetTeamRequest{
+				TeamId: id,
+			}))
+			if err != nil {
+				size.Error = convert.ErrorMessage(err)
+			} else {
+				size.Name = resp.Msg.GetTeam().GetName()
+				size.MemberCount = resp.Msg.GetTeam().GetMemberCount()
+			}
+			result.Audiences = append(result.Audiences, size)
+			result.TotalMembers += int64(size.MemberCount)
+		}
+		r, err := convert.JSONResult(result)
+		return r, nil, err
+	})
+}
@@ -0,0 +1,239 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pidgr/pidgr-mcp/internal/convert"
+	"github.com/pidgr/pidgr-mcp/internal/transport"
+)
+
+// RowFailure describes why one row of a bulk operation was not applied.
+type RowFailure struct {
+	Row   int    `json:"row,omitempty"`
+	Value string `json:"value,omitempty"`
+	Error string `json:"error"`
+}
+
+// ChunkedMembershipResult aggregates a membership mutation that was split
+// into several backend calls.
+type ChunkedMembershipResult struct {
+	GroupID   string       `json:"groupId,omitempty"`
+	TeamID    string       `json:"teamId,omitempty"`
+	Requested int          `json:"requested"`
+	Added     int          `json:"added"`
+	Chunks    int          `json:"chunks"`
+	Failures  []RowFailure `json:"failures,omitempty"`
+}
+
+// resolvedRows maps email rows to user UUIDs. IDs are de-duplicated and keep
+// the order in which they first appear; rows maps each ID back to its source
+// row for per-row error reporting.
+type resolvedRows struct {
+	IDs      []string
+	Rows     map[string]inputRow
+	Failures []RowFailure
+}
+
+// resolveEmails looks up each row's email address in the organization's user
+// directory. Unknown or blank emails are reported as failures.
+func resolveEmails(ctx context.Context, c *transport.Clients, rows []inputRow) (*resolvedRows, error) {
+	users, err := listAllUsers(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	byEmail := make(map[string]string, len(users))
+	for _, u := range users {
+		byEmail[strings.ToLower(u.GetEmail())] = u.GetId()
+	}
+
+	res := &resolvedRows{Rows: make(map[string]inputRow)}
+	for _, row := range rows {
+		email := strings.ToLower(strings.TrimSpace(row.Value))
+		if email == "" {
+			res.Failures = append(res.Failures, RowFailure{Row: row.Row, Error: "missing email"})
+			continue
+		}
+		id, ok := byEmail[email]
+		if !ok {
+			res.Failures = append(res.Failures, RowFailure{Row: row.Row, Value: row.Value, Error: "no user with this email"})
+			continue
+		}
+		if _, dup := res.Rows[id]; dup {
+			continue
+		}
+		res.IDs = append(res.IDs, id)
+		res.Rows[id] = row
+	}
+	return res, nil
+}
+
+// progressFunc is called after each unit of work with the number of items
+// processed so far and the total.
+type progressFunc func(done, total int)
+
+// toolProgress returns a progressFunc that sends MCP progress notifications
+// for the tool call. It is a no-op when the client did not request progress.
+func toolProgress(ctx context.Context, req *mcp.CallToolRequest) progressFunc {
+	if req == nil || req.Session == nil || req.Params == nil {
+		return nil
+	}
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return nil
+	}
+	return func(done, total int) {
+		_ = req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      float64(done),
+			Total:         float64(total),
+			Message:       fmt.Sprintf("%d of %d processed", done, total),
+		})
+	}
+}
+
+// DefaultBulkConcurrency is the number of backend calls a bulk tool makes at
+// once unless SetBulkConcurrency overrides it.
+const DefaultBulkConcurrency = 4
+
+// bulkConcurrency bounds the backend calls in flight for one bulk tool call.
+var bulkConcurrency = DefaultBulkConcurrency
+
+// SetBulkConcurrency sets how many backend calls a bulk tool makes at once;
+// values below one are treated as one. It must be called before the server
+// starts handling requests.
+func SetBulkConcurrency(n int) {
+	bulkConcurrency = max(n, 1)
+}
+
+// forEach calls fn for items 0..n-1 with at most bulkConcurrency calls in
+// flight and returns each item's error by index. A failed item does not stop
+// the others; once ctx is done, items not yet started fail with ctx's error
+// without calling fn. done, when non-nil, is called after each item, one call
+// at a time, so callers can aggregate results without locking. progress, when
+// non-nil, is called after each item outside that lock with the units of work
+// finished so far; item i counts for size(i) units, or one when size is nil.
+func forEach(ctx context.Context, n int, fn func(ctx context.Context, i int) error, done func(i int, err error), size func(i int) int, progress progressFunc) []error {
+	total := n
+	if size != nil {
+		total = 0
+		for i := range n {
+			total += size(i)
+		}
+	}
+
+	errs := make([]error, n)
+	next := make(chan int)
+	var mu, progressMu sync.Mutex
+	var wg sync.WaitGroup
+	processed, reported := 0, 0
+	for range min(bulkConcurrency, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				err := ctx.Err()
+				if err == nil {
+					err = fn(ctx, i)
+				}
+				mu.Lock()
+				errs[i] = err
+				if done != nil {
+					done(i, err)
+				}
+				if size != nil {
+					processed += size(i)
+				} else {
+					processed++
+				}
+				current := processed
+				mu.Unlock()
+
+				if progress != nil {
+					// Notifications from different workers may race; only
+					// send counts that move forward.
+					progressMu.Lock()
+					if current > reported {
+						reported = current
+						progress(current, total)
+					}
+					progressMu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range n {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return errs
+}
+
+// applyInChunks calls fn for each membershipChunkSize slice of ids, running
+// chunks concurrently and reporting progress as each completes. IDs in
+// successful chunks are returned in input order; IDs in failed chunks are
+// reported as failures carrying the sanitized backend error.
+func applyInChunks(ctx context.Context, ids []string, fn func(ctx context.Context, chunk []string) error, progress progressFunc) ([]string, []RowFailure) {
+	chunks := chunkIDs(ids, membershipChunkSize)
+	errs := forEach(ctx, len(chunks), func(ctx context.Context, i int) error {
+		return fn(ctx, chunks[i])
+	}, nil, func(i int) int {
+		return len(chunks[i])
+	}, progress)
+
+	var applied []string
+	var failures []RowFailure
+	for i, chunk := range chunks {
+		if errs[i] != nil {
+			msg := convert.ErrorMessage(errs[i])
+			for _, id := range chunk {
+				failures = append(failures, RowFailure{Value: id, Error: msg})
+			}
+			continue
+		}
+		applied = append(applied, chunk...)
+	}
+	return applied, failures
+}
+
+// diffIDs partitions two ID lists into IDs only in a, only in b, and in both.
+// Each result keeps the order of its source list.
+func diffIDs(a, b []string) (onlyA, onlyB, both []string) {
+	inA := make(map[string]bool, len(a))
+	for _, id := range a {
+		inA[id] = true
+	}
+	inB := make(map[string]bool, len(b))
+	for _, id := range b {
+		inB[id] = true
+	}
+	for _, id := range a {
+		if inB[id] {
+			both = append(both, id)
+		} else {
+			onlyA = append(onlyA, id)
+		}
+	}
+	for _, id := range b {
+		if !inA[id] {
+			onlyB = append(onlyB, id)
+		}
+	}
+	return onlyA, onlyB, both
+}
+
+// nonNil returns ids, or an empty slice when ids is nil, so results serialize
+// as [] rather than null.
+func nonNil(ids []string) []string {
+	if ids == nil {
+		return []string{}
+	}
+	return ids
+}
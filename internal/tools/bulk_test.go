@@ -0,0 +1,164 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+func TestDiffIDs(t *testing.T) {
+	onlyA, onlyB, both := diffIDs([]string{"a", "b", "c"}, []string{"c", "d", "a"})
+	if got := fmt.Sprint(onlyA, onlyB, both); got != "[b] [d] [a c]" {
+		t.Errorf("diffIDs = %s, want [b] [d] [a c]", got)
+	}
+}
+
+func TestApplyInChunks(t *testing.T) {
+	ids := make([]string, 250)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("u%d", i)
+	}
+
+	var calls atomic.Int32
+	var progress []int
+	applied, failures := applyInChunks(context.Background(), ids, func(_ context.Context, chunk []string) error {
+		calls.Add(1)
+		if chunk[0] == "u100" {
+			return connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("bad chunk"))
+		}
+		return nil
+	}, func(done, total int) {
+		if total != 250 {
+			t.Errorf("progress total = %d, want 250", total)
+		}
+		progress = append(progress, done)
+	})
+
+	if n := calls.Load(); n != 3 {
+		t.Errorf("fn called %d times, want 3", n)
+	}
+	if len(applied) != 150 {
+		t.Errorf("applied %d IDs, want 150", len(applied))
+	}
+	if len(failures) != 100 {
+		t.Fatalf("got %d failures, want 100", len(failures))
+	}
+	if len(progress) != 3 || progress[2] != 250 {
+		t.Errorf("progress = %v, want three reports ending at 250", progress)
+	}
+	if applied[0] != "u0" || applied[100] != "u200" {
+		t.Errorf("applied IDs out of order: %s, %s", applied[0], applied[100])
+	}
+	if failures[0].Value != "u100" || failures[0].Error != "Invalid input: bad chunk" {
+		t.Errorf("unexpected first failure: %+v", failures[0])
+	}
+}
+
+func TestForEachBoundsConcurrency(t *testing.T) {
+	defer SetBulkConcurrency(DefaultBulkConcurrency)
+	SetBulkConcurrency(3)
+
+	var inFlight, peak atomic.Int32
+	var processed []int
+	errs := forEach(context.Background(), 20, func(_ context.Context, i int) error {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		if i%5 == 0 {
+			return fmt.Errorf("item %d", i)
+		}
+		return nil
+	}, func(i int, _ error) {
+		processed = append(processed, i)
+	}, nil, nil)
+
+	if p := peak.Load(); p > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3", p)
+	}
+	if len(processed) != 20 {
+		t.Errorf("done called %d times, want 20", len(processed))
+	}
+	for i, err := range errs {
+		if (i%5 == 0) != (err != nil) {
+			t.Errorf("errs[%d] = %v", i, err)
+		}
+	}
+}
+
+func TestForEachCancellation(t *testing.T) {
+	defer SetBulkConcurrency(DefaultBulkConcurrency)
+	SetBulkConcurrency(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls atomic.Int32
+	errs := forEach(ctx, 5, func(_ context.Context, i int) error {
+		calls.Add(1)
+		if i == 1 {
+			cancel()
+		}
+		return nil
+	}, nil, nil, nil)
+
+	if n := calls.Load(); n != 2 {
+		t.Errorf("fn called %d times, want 2", n)
+	}
+	if errs[1] != nil || !errors.Is(errs[2], context.Canceled) || !errors.Is(errs[4], context.Canceled) {
+		t.Errorf("errs = %v, want items after the cancellation to fail with context.Canceled", errs)
+	}
+}
+
+func TestForEachProgress(t *testing.T) {
+	defer SetBulkConcurrency(DefaultBulkConcurrency)
+	SetBulkConcurrency(4)
+
+	sizes := []int{100, 100, 100, 7}
+	var reports [][2]int
+	forEach(context.Background(), len(sizes), func(context.Context, int) error {
+		time.Sleep(time.Millisecond)
+		return nil
+	}, nil, func(i int) int {
+		return sizes[i]
+	}, func(done, total int) {
+		reports = append(reports, [2]int{done, total})
+	})
+
+	if len(reports) == 0 {
+		t.Fatal("progress was never reported")
+	}
+	for i, r := range reports {
+		if r[1] != 307 {
+			t.Errorf("report %d total = %d, want 307", i, r[1])
+		}
+		if i > 0 && r[0] <= reports[i-1][0] {
+			t.Errorf("report %d done = %d, want more than %d", i, r[0], reports[i-1][0])
+		}
+	}
+	if last := reports[len(reports)-1]; last[0] != 307 {
+		t.Errorf("last report done = %d, want 307", last[0])
+	}
+}
+
+func TestNonNil(t *testing.T) {
+	if got := nonNil(nil); got == nil || len(got) != 0 {
+		t.Errorf("nonNil(nil) = %#v, want empty slice", got)
+	}
+	in := []string{"a"}
+	if got := nonNil(in); len(got) != 1 {
+		t.Errorf("nonNil should return the input unchanged, got %v", got)
+	}
+}
@@ -46,12 +46,18 @@ type StartCampaignInput struct {
 }
 
 type GetCampaignInput struct {
-	CampaignID string `json:"campaign_id" jsonschema:"Campaign UUID to retrieve"`
+	CampaignID   string   `json:"campaign_id" jsonschema:"Campaign UUID to retrieve"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
 }
 
 type ListCampaignsInput struct {
-	PageSize  int32  `json:"page_size,omitempty" jsonschema:"Max items per page"`
-	PageToken string `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
+	PageSize     int32    `json:"page_size,omitempty" jsonschema:"Max items per page"`
+	PageToken    string   `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
+	FetchAll     bool     `json:"fetch_all,omitempty" jsonschema:"Fetch every page server-side and return all items together, up to 20 pages; nextPageToken is set if more remain"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+	SummaryOnly  bool     `json:"summary_only,omitempty" jsonschema:"Return only the item count, breakdowns by status and other enum fields, and the first few items"`
 }
 
 type CancelCampaignInput struct {
@@ -59,10 +65,62 @@ type CancelCampaignInput struct {
 }
 
 type ListDeliveriesInput struct {
-	CampaignID   string `json:"campaign_id" jsonschema:"Campaign UUID"`
-	StatusFilter string `json:"status_filter,omitempty" jsonschema:"Filter by delivery status (PENDING/SENT/DELIVERED/ACKNOWLEDGED/MISSED/NO_DEVICE/FAILED)"`
-	PageSize     int32  `json:"page_size,omitempty" jsonschema:"Max items per page"`
-	PageToken    string `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
+	CampaignID   string   `json:"campaign_id" jsonschema:"Campaign UUID"`
+	StatusFilter string   `json:"status_filter,omitempty" jsonschema:"Filter by delivery status (PENDING/SENT/DELIVERED/ACKNOWLEDGED/MISSED/NO_DEVICE/FAILED)"`
+	PageSize     int32    `json:"page_size,omitempty" jsonschema:"Max items per page"`
+	PageToken    string   `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
+	FetchAll     bool     `json:"fetch_all,omitempty" jsonschema:"Fetch every page server-side and return all items together, up to 20 pages; nextPageToken is set if more remain"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+	SummaryOnly  bool     `json:"summary_only,omitempty" jsonschema:"Return only the item count, breakdowns by status and other enum fields, and the first few items"`
+}
+
+// ── Output types ────────────────────────────────────────────────────────────
+
+type CampaignResult struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	SenderName      string `json:"senderName,omitempty"`
+	TemplateID      string `json:"templateId,omitempty"`
+	TemplateVersion int32  `json:"templateVersion,omitempty"`
+	Status          string `json:"status"`
+	CreatedAt       string `json:"createdAt,omitempty"`
+}
+
+type GetCampaignResult struct {
+	Campaign CampaignResult `json:"campaign"`
+}
+
+type ListCampaignsResult struct {
+	Campaigns  []CampaignResult `json:"campaigns"`
+	Pagination *PageResult      `json:"paginationMeta,omitempty"`
+}
+
+// ── Helpers ─────────────────────────────────────────────────────────────────
+
+func campaignResult(c *pidgrv1.Campaign) CampaignResult {
+	return CampaignResult{
+		ID:              c.GetId(),
+		Name:            c.GetName(),
+		SenderName:      c.GetSenderName(),
+		TemplateID:      c.GetTemplateId(),
+		TemplateVersion: c.GetTemplateVersion(),
+		Status:          convert.EnumValue(c.GetStatus().String(), "CAMPAIGN_STATUS_"),
+		CreatedAt:       convert.TimeValue(c.GetCreatedAt()),
+	}
+}
+
+// listAllCampaigns fetches every campaign in the organization.
+func listAllCampaigns(ctx context.Context, c *transport.Clients) ([]*pidgrv1.Campaign, error) {
+	return fetchAll(ctx, func(ctx context.Context, p *pidgrv1.Pagination) ([]*pidgrv1.Campaign, string, error) {
+		resp, err := c.Campaigns.ListCampaigns(ctx, connect.NewRequest(&pidgrv1.ListCampaignsRequest{
+			Pagination: p,
+		}))
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Msg.GetCampaigns(), resp.Msg.GetPaginationMeta().GetNextPageToken(), nil
+	})
 }
 
 // ── Registration ────────────────────────────────────────────────────────────
@@ -97,7 +155,7 @@ func registerCampaignTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -118,7 +176,7 @@ func registerCampaignTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -133,13 +191,14 @@ func registerCampaignTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
 	mcp.AddTool(s, &mcp.Tool{
-		Name:        "get_campaign",
-		Description: "Retrieve a single campaign by UUID. Use list_campaigns to find available campaign UUIDs.",
+		Name:         "get_campaign",
+		Description:  "Retrieve a single campaign by UUID. Use list_campaigns to find available campaign UUIDs.",
+		OutputSchema: outputSchema[GetCampaignResult](),
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input GetCampaignInput) (*mcp.CallToolResult, any, error) {
 		resp, err := c.Campaigns.GetCampaign(ctx, connect.NewRequest(&pidgrv1.GetCampaignRequest{
 			CampaignId: input.CampaignID,
@@ -148,25 +207,36 @@ func registerCampaignTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.TypedResult(ctx, GetCampaignResult{Campaign: campaignResult(resp.Msg.GetCampaign())})
 		return r, nil, err
 	})
 
 	mcp.AddTool(s, &mcp.Tool{
-		Name:        "list_campaigns",
-		Description: "List campaigns for the organization with pagination. Call this first to discover campaign UUIDs before using other campaign tools.",
+		Name:         "list_campaigns",
+		Description:  "List campaigns for the organization with pagination. Call this first to discover campaign UUIDs before using other campaign tools.",
+		OutputSchema: outputSchema[ListCampaignsResult](),
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListCampaignsInput) (*mcp.CallToolResult, any, error) {
-		resp, err := c.Campaigns.ListCampaigns(ctx, connect.NewRequest(&pidgrv1.ListCampaignsRequest{
-			Pagination: &pidgrv1.Pagination{
-				PageSize:  clampPageSize(input.PageSize),
-				PageToken: input.PageToken,
-			},
-		}))
+		msg, err := collectPages(ctx, input.PageSize, input.PageToken, input.FetchAll, func(ctx context.Context, p *pidgrv1.Pagination) (*pidgrv1.ListCampaignsResponse, string, error) {
+			resp, err := c.Campaigns.ListCampaigns(ctx, connect.NewRequest(&pidgrv1.ListCampaignsRequest{
+				Pagination: p,
+			}))
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Msg, resp.Msg.GetPaginationMeta().GetNextPageToken(), nil
+		})
 		if err != nil {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		out := ListCampaignsResult{
+			Campaigns:  make([]CampaignResult, 0, len(msg.GetCampaigns())),
+			Pagination: pageResult(msg.GetPaginationMeta().GetNextPageToken()),
+		}
+		for _, campaign := range msg.GetCampaigns() {
+			out.Campaigns = append(out.Campaigns, campaignResult(campaign))
+		}
+		r, err := convert.TypedResult(ctx, out)
 		return r, nil, err
 	})
 
@@ -181,7 +251,7 @@ func registerCampaignTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -197,19 +267,22 @@ func registerCampaignTools(s *mcp.Server, c *transport.Clients) {
 				statusFilter = pidgrv1.DeliveryStatus(v)
 			}
 		}
-		resp, err := c.Campaigns.ListDeliveries(ctx, connect.NewRequest(&pidgrv1.ListDeliveriesRequest{
-			CampaignId:   input.CampaignID,
-			StatusFilter: statusFilter,
-			Pagination: &pidgrv1.Pagination{
-				PageSize:  clampPageSize(input.PageSize),
-				PageToken: input.PageToken,
-			},
-		}))
+		msg, err := collectPages(ctx, input.PageSize, input.PageToken, input.FetchAll, func(ctx context.Context, p *pidgrv1.Pagination) (*pidgrv1.ListDeliveriesResponse, string, error) {
+			resp, err := c.Campaigns.ListDeliveries(ctx, connect.NewRequest(&pidgrv1.ListDeliveriesRequest{
+				CampaignId:   input.CampaignID,
+				StatusFilter: statusFilter,
+				Pagination:   p,
+			}))
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Msg, resp.Msg.GetPaginationMeta().GetNextPageToken(), nil
+		})
 		if err != nil {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, msg)
 		return r, nil, err
 	})
 }
@@ -0,0 +1,148 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pidgr/pidgr-mcp/internal/convert"
+	"github.com/pidgr/pidgr-mcp/internal/transport"
+	pidgrv1 "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1"
+	"github.com/pidgr/pidgr-proto/gen/go/pidgr/v1/pidgrv1connect"
+)
+
+// fakeCampaignService serves a single campaign.
+type fakeCampaignService struct {
+	pidgrv1connect.UnimplementedCampaignServiceHandler
+	campaign *pidgrv1.Campaign
+}
+
+func (f *fakeCampaignService) GetCampaign(context.Context, *connect.Request[pidgrv1.GetCampaignRequest]) (*connect.Response[pidgrv1.GetCampaignResponse], error) {
+	return connect.NewResponse(&pidgrv1.GetCampaignResponse{Campaign: f.campaign}), nil
+}
+
+func (f *fakeCampaignService) ListCampaigns(context.Context, *connect.Request[pidgrv1.ListCampaignsRequest]) (*connect.Response[pidgrv1.ListCampaignsResponse], error) {
+	return connect.NewResponse(&pidgrv1.ListCampaignsResponse{
+		Campaigns:      []*pidgrv1.Campaign{f.campaign},
+		PaginationMeta: &pidgrv1.PaginationMeta{NextPageToken: "t2"},
+	}), nil
+}
+
+// campaignSession connects an MCP client to a server with only the campaign
+// tools, backed by svc.
+func campaignSession(t *testing.T, svc *fakeCampaignService) *mcp.ClientSession {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.Handle(pidgrv1connect.NewCampaignServiceHandler(svc))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "pidgr-test", Version: "test"}, nil)
+	server.AddReceivingMiddleware(convert.OptionsMiddleware)
+	registerCampaignTools(server, &transport.Clients{
+		Campaigns: pidgrv1connect.NewCampaignServiceClient(srv.Client(), srv.URL),
+	})
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	go func() { _ = server.Run(context.Background(), serverTransport) }()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, nil)
+	session, err := client.Connect(context.Background(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+// callTool calls a tool and decodes its structured content into out.
+func callTool(t *testing.T, session *mcp.ClientSession, name string, args map[string]any, out any) *mcp.CallToolResult {
+	t.Helper()
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("CallTool error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("%s failed: %+v", name, result.Content)
+	}
+	raw, _ := json.Marshal(result.StructuredContent)
+	if err := json.Unmarshal(raw, out); err != nil {
+		t.Fatalf("decode structured content: %v", err)
+	}
+	return result
+}
+
+func TestGetCampaignTypedResult(t *testing.T) {
+	session := campaignSession(t, &fakeCampaignService{campaign: &pidgrv1.Campaign{
+		Id:         "c1",
+		Name:       "Launch",
+		SenderName: "HR",
+		Status:     pidgrv1.CampaignStatus_CAMPAIGN_STATUS_RUNNING,
+	}})
+
+	var structured GetCampaignResult
+	result := callTool(t, session, "get_campaign", map[string]any{"campaign_id": "c1"}, &structured)
+	want := CampaignResult{ID: "c1", Name: "Launch", SenderName: "HR", Status: "RUNNING"}
+	if structured.Campaign != want {
+		t.Errorf("structured content = %+v, want %+v", structured.Campaign, want)
+	}
+
+	// The text is the typed result too, not the backend's proto JSON.
+	var text GetCampaignResult
+	if err := json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &text); err != nil || text != structured {
+		t.Errorf("text content = %+v (%v), want %+v", text, err, structured)
+	}
+}
+
+func TestGetCampaignTypedResultHonorsFields(t *testing.T) {
+	session := campaignSession(t, &fakeCampaignService{campaign: &pidgrv1.Campaign{
+		Id:     "c1",
+		Name:   "Launch",
+		Status: pidgrv1.CampaignStatus_CAMPAIGN_STATUS_RUNNING,
+	}})
+
+	var structured map[string]map[string]any
+	callTool(t, session, "get_campaign", map[string]any{"campaign_id": "c1", "fields": []string{"id", "status"}}, &structured)
+	want := map[string]any{"id": "c1", "status": "RUNNING"}
+	if got := structured["campaign"]; len(got) != len(want) || got["id"] != want["id"] || got["status"] != want["status"] {
+		t.Errorf("structured campaign = %v, want only %v", got, want)
+	}
+}
+
+func TestListCampaignsStructuredPagination(t *testing.T) {
+	session := campaignSession(t, &fakeCampaignService{campaign: &pidgrv1.Campaign{Id: "c1", Name: "Launch"}})
+
+	var structured ListCampaignsResult
+	result := callTool(t, session, "list_campaigns", map[string]any{}, &structured)
+	if len(structured.Campaigns) != 1 || structured.Pagination == nil || structured.Pagination.NextPageToken != "t2" {
+		t.Errorf("structured content = %+v, want one campaign and next page t2", structured)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, `"paginationMeta":{"nextPageToken":"t2"}`) {
+		t.Errorf("content %s does not carry the pagination block", text)
+	}
+}
+
+func TestListCampaignsSummaryOnly(t *testing.T) {
+	session := campaignSession(t, &fakeCampaignService{campaign: &pidgrv1.Campaign{
+		Id:     "c1",
+		Status: pidgrv1.CampaignStatus_CAMPAIGN_STATUS_RUNNING,
+	}})
+
+	var structured struct {
+		Summary struct {
+			Count     int                       `json:"count"`
+			Breakdown map[string]map[string]int `json:"breakdown"`
+		} `json:"summary"`
+	}
+	callTool(t, session, "list_campaigns", map[string]any{"summary_only": true}, &structured)
+	if structured.Summary.Count != 1 || structured.Summary.Breakdown["status"]["RUNNING"] != 1 {
+		t.Errorf("structured summary = %+v, want one RUNNING campaign", structured.Summary)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// emailHeaders are the normalized column names recognized as an email column.
+var emailHeaders = map[string]bool{
+	"email":         true,
+	"email_address": true,
+	"e_mail":        true,
+	"mail":          true,
+}
+
+// inputRow is one value read from bulk input, with its 1-based row number in
+// the source (CSV line or list position) for error reporting.
+type inputRow struct {
+	Row   int
+	Value string
+}
+
+// parseEmailCSV reads email addresses from CSV content. If the first record
+// has a recognized email column header, that column is used and the header is
+// skipped; otherwise the first column of every record is used.
+func parseEmailCSV(content string) ([]inputRow, error) {
+	r := csv.NewReader(strings.NewReader(content))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	var rows []inputRow
+	col := 0
+	first := true
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse csv: %w", err)
+		}
+		line, _ := r.FieldPos(0)
+		if first {
+			first = false
+			if i := headerIndex(record, emailHeaders); i >= 0 {
+				col = i
+				continue
+			}
+		}
+		value := ""
+		if col < len(record) {
+			value = strings.TrimSpace(record[col])
+		}
+		rows = append(rows, inputRow{Row: line, Value: value})
+	}
+	return rows, nil
+}
+
+// csvRecord is one data row of a CSV file with a header row. Fields is keyed
+// by normalized column name.
+type csvRecord struct {
+	Row    int
+	Fields map[string]string
+}
+
+// parseRecordCSV reads CSV content whose first record is a header row. It
+// returns the normalized column names and one csvRecord per data row. Short
+// rows leave their missing columns empty.
+func parseRecordCSV(content string) ([]string, []csvRecord, error) {
+	r := csv.NewReader(strings.NewReader(content))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	var header []string
+	var records []csvRecord
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("parse csv: %w", err)
+		}
+		if header == nil {
+			header = make([]string, len(record))
+			for i, h := range record {
+				header[i] = normalizeHeader(h)
+			}
+			continue
+		}
+		line, _ := r.FieldPos(0)
+		fields := make(map[string]string, len(header))
+		for i, name := range header {
+			if i < len(record) {
+				fields[name] = strings.TrimSpace(record[i])
+			} else {
+				fields[name] = ""
+			}
+		}
+		records = append(records, csvRecord{Row: line, Fields: fields})
+	}
+	if header == nil {
+		return nil, nil, fmt.Errorf("csv has no header row")
+	}
+	return header, records, nil
+}
+
+// headerIndex returns the index of the first column whose normalized name is
+// in names, or -1 if none match.
+func headerIndex(record []string, names map[string]bool) int {
+	for i, h := range record {
+		if names[normalizeHeader(h)] {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalizeHeader lowercases a CSV column name and replaces spaces and
+// hyphens with underscores. The key of a "custom_attributes.<key>" column
+// is kept as written, since custom attribute keys are case-sensitive.
+func normalizeHeader(h string) string {
+	h = strings.TrimSpace(h)
+	if i := strings.Index(h, "."); i >= 0 {
+		if prefix := normalizeName(h[:i+1]); prefix == customAttributePrefix {
+			return prefix + h[i+1:]
+		}
+	}
+	return normalizeName(h)
+}
+
+// normalizeName lowercases name and replaces spaces and hyphens with
+// underscores.
+func normalizeName(name string) string {
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(name))
+}
+
+// writeCSV renders a header and rows as CSV text.
+func writeCSV(header []string, rows [][]string) (string, error) {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	if err := w.Write(header); err != nil {
+		return "", err
+	}
+	if err := w.WriteAll(rows); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
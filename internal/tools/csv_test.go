@@ -0,0 +1,84 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseEmailCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"email header", "name,Email\nAna,ana@example.com\nBo,bo@example.com\n", "[{2 ana@example.com} {3 bo@example.com}]"},
+		{"email address header", "Email Address\nana@example.com\n", "[{2 ana@example.com}]"},
+		{"no header", "ana@example.com,Ana\nbo@example.com\n", "[{1 ana@example.com} {2 bo@example.com}]"},
+		{"short row", "name,email\nAna\n", "[{2 }]"},
+		{"empty", "", "[]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseEmailCSV(tt.content)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := fmt.Sprint(rows); got != tt.want {
+				t.Errorf("parseEmailCSV = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEmailCSVMalformed(t *testing.T) {
+	if _, err := parseEmailCSV("email\n\"unterminated\n"); err == nil {
+		t.Fatal("expected error for malformed csv")
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	got, err := writeCSV([]string{"id", "name"}, [][]string{{"u1", "Ana"}, {"u2", "Doe, Jo"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "id,name\nu1,Ana\nu2,\"Doe, Jo\"\n"
+	if got != want {
+		t.Errorf("writeCSV = %q, want %q", got, want)
+	}
+}
+
+func TestParseRecordCSV(t *testing.T) {
+	header, records, err := parseRecordCSV("Email,Job Title\nana@example.com, Analyst\nbo@example.com\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := fmt.Sprint(header); got != "[email job_title]" {
+		t.Errorf("header = %s", got)
+	}
+	if got := fmt.Sprint(records); got != "[{2 map[email:ana@example.com job_title:Analyst]} {3 map[email:bo@example.com job_title:]}]" {
+		t.Errorf("records = %s", got)
+	}
+}
+
+func TestNormalizeHeaderKeepsCustomAttributeKeys(t *testing.T) {
+	tests := map[string]string{
+		" Job Title ":                  "job_title",
+		"Custom Attributes.CostCenter": "custom_attributes.CostCenter",
+		"custom-attributes.Badge ID":   "custom_attributes.Badge ID",
+		"Region.Name":                  "region.name",
+	}
+	for in, want := range tests {
+		if got := normalizeHeader(in); got != want {
+			t.Errorf("normalizeHeader(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseRecordCSVEmpty(t *testing.T) {
+	if _, _, err := parseRecordCSV(""); err == nil {
+		t.Error("expected error for missing header")
+	}
+}
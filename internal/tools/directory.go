@@ -0,0 +1,315 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	pidgrv1 "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1"
+	"github.com/pidgr/pidgr-mcp/internal/convert"
+	"github.com/pidgr/pidgr-mcp/internal/transport"
+)
+
+// ── Input types ─────────────────────────────────────────────────────────────
+
+type DirectoryEntry struct {
+	Email   string            `json:"email" jsonschema:"Email address identifying the user"`
+	Name    string            `json:"name,omitempty" jsonschema:"Display name, used when inviting a new user"`
+	Status  string            `json:"status,omitempty" jsonschema:"Employment status: active (default), or inactive, terminated, or departed to deactivate the user"`
+	Profile *UserProfileInput `json:"profile,omitempty" jsonschema:"Profile attributes; empty fields are left unchanged"`
+}
+
+type SyncUsersInput struct {
+	Users             []DirectoryEntry `json:"users,omitempty" jsonschema:"Directory entries (max 1000 combined with csv rows)"`
+	CSV               string           `json:"csv,omitempty" jsonschema:"Directory export as CSV with a header row: an email column, plus optional name, status, and profile columns (department, title, manager, custom_attributes.<key>, ...)"`
+	RoleID            string           `json:"role_id,omitempty" jsonschema:"Role UUID for newly invited users (defaults to employee role)"`
+	DeactivateMissing bool             `json:"deactivate_missing,omitempty" jsonschema:"Also deactivate users (including invited ones) who are not in the directory export at all; the export must cover the whole organization"`
+	DryRun            bool             `json:"dry_run,omitempty" jsonschema:"Report the changes that would be made without applying them"`
+	Confirm           bool             `json:"confirm,omitempty" jsonschema:"Must be true to apply deactivate_missing outside a dry run"`
+}
+
+// ── Output types ────────────────────────────────────────────────────────────
+
+// SyncUserChange is one user affected by a directory sync.
+type SyncUserChange struct {
+	Email   string   `json:"email"`
+	UserID  string   `json:"userId,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+type SyncUsersResult struct {
+	DryRun      bool             `json:"dryRun"`
+	Invited     []SyncUserChange `json:"invited"`
+	Updated     []SyncUserChange `json:"updated"`
+	Deactivated []SyncUserChange `json:"deactivated"`
+	Unchanged   int              `json:"unchanged"`
+	Failures    []RowFailure     `json:"failures,omitempty"`
+}
+
+// ── Helpers ─────────────────────────────────────────────────────────────────
+
+// departedStatuses are the directory status values that mean a user has left.
+var departedStatuses = map[string]bool{
+	"inactive":    true,
+	"deactivated": true,
+	"terminated":  true,
+	"departed":    true,
+	"disabled":    true,
+	"suspended":   true,
+}
+
+var (
+	nameHeaders   = map[string]bool{"name": true, "full_name": true, "display_name": true}
+	statusHeaders = map[string]bool{"status": true, "employment_status": true}
+)
+
+// directoryRow is a directory entry with its source row number.
+type directoryRow struct {
+	Row int
+	DirectoryEntry
+}
+
+// directoryRowsFromCSV converts a directory CSV export into entries.
+func directoryRowsFromCSV(content string) ([]directoryRow, error) {
+	header, records, err := parseRecordCSV(content)
+	if err != nil {
+		return nil, err
+	}
+	emailCol := headerIndex(header, emailHeaders)
+	if emailCol < 0 {
+		return nil, fmt.Errorf("csv needs an email column")
+	}
+	column := func(names map[string]bool) string {
+		if i := headerIndex(header, names); i >= 0 {
+			return header[i]
+		}
+		return ""
+	}
+	nameCol, statusCol := column(nameHeaders), column(statusHeaders)
+
+	rows := make([]directoryRow, len(records))
+	for i, rec := range records {
+		profile := profileFromRecord(rec.Fields)
+		rows[i] = directoryRow{Row: rec.Row, DirectoryEntry: DirectoryEntry{
+			Email:   rec.Fields[header[emailCol]],
+			Name:    rec.Fields[nameCol],
+			Status:  rec.Fields[statusCol],
+			Profile: &profile,
+		}}
+	}
+	return rows, nil
+}
+
+// displayName picks the invite name for a directory entry, falling back to
+// the profile's first and last name, then the email address.
+func displayName(e DirectoryEntry) string {
+	if e.Name != "" {
+		return e.Name
+	}
+	if e.Profile != nil {
+		if n := strings.TrimSpace(e.Profile.FirstName + " " + e.Profile.LastName); n != "" {
+			return n
+		}
+	}
+	return e.Email
+}
+
+// ── Registration ────────────────────────────────────────────────────────────
+
+func registerDirectoryTools(s *mcp.Server, c *transport.Clients) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "sync_users",
+		Description: "Reconcile the organization against a directory export (e.g. from an HRIS), matched by email: invite users who are missing, update changed profile fields, and deactivate users marked inactive or terminated. Set deactivate_missing (with confirm=true) to also deactivate users absent from the export; it is refused when the export has fewer rows than the organization has active users, since a partial export would deactivate everyone outside it. Run with dry_run=true first to review the change summary.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input SyncUsersInput) (*mcp.CallToolResult, any, error) {
+		var rows []directoryRow
+		for i, e := range input.Users {
+			rows = append(rows, directoryRow{Row: i + 1, DirectoryEntry: e})
+		}
+		if input.CSV != "" {
+			csvRows, err := directoryRowsFromCSV(input.CSV)
+			if err != nil {
+				r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, err))
+				return r, nil, nil
+			}
+			rows = append(rows, csvRows...)
+		}
+		if len(rows) == 0 {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("users or csv is required")))
+			return r, nil, nil
+		}
+		if len(rows) > maxBatchSize {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("batch size %d exceeds maximum of %d", len(rows), maxBatchSize)))
+			return r, nil, nil
+		}
+
+		users, err := listAllUsers(ctx, c)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		byEmail := make(map[string]*pidgrv1.User, len(users))
+		for _, u := range users {
+			byEmail[strings.ToLower(u.GetEmail())] = u
+		}
+
+		// Plan every change before applying any, so dry runs and real runs
+		// report the same summary.
+		type profileUpdate struct {
+			Change  *SyncUserChange
+			Profile UserProfileInput
+		}
+		var (
+			invites       []directoryRow
+			updates       []profileUpdate
+			deactivations []*SyncUserChange
+		)
+		result := SyncUsersResult{
+			DryRun:      input.DryRun,
+			Invited:     []SyncUserChange{},
+			Updated:     []SyncUserChange{},
+			Deactivated: []SyncUserChange{},
+		}
+		seen := make(map[string]bool, len(rows))
+		for _, row := range rows {
+			email := strings.ToLower(strings.TrimSpace(row.Email))
+			if email == "" {
+				result.Failures = append(result.Failures, RowFailure{Row: row.Row, Error: "missing email"})
+				continue
+			}
+			if seen[email] {
+				result.Failures = append(result.Failures, RowFailure{Row: row.Row, Value: row.Email, Error: "duplicate email in directory"})
+				continue
+			}
+			seen[email] = true
+			departed := departedStatuses[strings.ToLower(strings.TrimSpace(row.Status))]
+
+			u := byEmail[email]
+			switch {
+			case u == nil && departed:
+				// Never joined and already gone: nothing to do.
+				result.Unchanged++
+			case u == nil:
+				invites = append(invites, row)
+			case departed:
+				if u.GetStatus() == pidgrv1.UserStatus_USER_STATUS_DEACTIVATED {
+					result.Unchanged++
+					continue
+				}
+				result.Deactivated = append(result.Deactivated, SyncUserChange{Email: u.GetEmail(), UserID: u.GetId()})
+			default:
+				current := fromProtoProfile(u.GetProfile())
+				var update UserProfileInput
+				if row.Profile != nil {
+					update = *row.Profile
+				}
+				merged, _ := mergeProfile(current, update, nil)
+				changed := profileChanges(current, merged)
+				if len(changed) == 0 {
+					result.Unchanged++
+					continue
+				}
+				result.Updated = append(result.Updated, SyncUserChange{Email: u.GetEmail(), UserID: u.GetId(), Changed: changed})
+				updates = append(updates, profileUpdate{Profile: merged})
+			}
+		}
+		if input.DeactivateMissing {
+			if !input.DryRun && !input.Confirm {
+				r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf(
+					"deactivate_missing deactivates every user absent from the export; review with dry_run=true, then call again with confirm=true to proceed",
+				)))
+				return r, nil, nil
+			}
+			active := 0
+			for _, u := range users {
+				if u.GetStatus() != pidgrv1.UserStatus_USER_STATUS_DEACTIVATED {
+					active++
+				}
+			}
+			if len(seen) < active {
+				r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf(
+					"deactivate_missing needs a complete directory export: it has %d users but the organization has %d active users; sync without deactivate_missing or send the full export",
+					len(seen), active,
+				)))
+				return r, nil, nil
+			}
+			for _, u := range users {
+				if seen[strings.ToLower(u.GetEmail())] || u.GetStatus() == pidgrv1.UserStatus_USER_STATUS_DEACTIVATED {
+					continue
+				}
+				result.Deactivated = append(result.Deactivated, SyncUserChange{Email: u.GetEmail(), UserID: u.GetId()})
+			}
+		}
+		for i := range updates {
+			updates[i].Change = &result.Updated[i]
+		}
+		for i := range result.Deactivated {
+			deactivations = append(deactivations, &result.Deactivated[i])
+		}
+
+		if input.DryRun {
+			for _, row := range invites {
+				result.Invited = append(result.Invited, SyncUserChange{Email: row.Email})
+			}
+			r, err := convert.JSONResult(result)
+			return r, nil, err
+		}
+
+		// Invites, updates, and deactivations share one pool: index i is an
+		// invite, then an update, then a deactivation.
+		total := len(invites) + len(updates) + len(deactivations)
+		result.Invited = make([]SyncUserChange, len(invites))
+		errs := forEach(ctx, total, func(ctx context.Context, i int) error {
+			switch {
+			case i < len(invites):
+				row := invites[i]
+				resp, err := c.Members.InviteUser(ctx, connect.NewRequest(&pidgrv1.InviteUserRequest{
+					Email:   row.Email,
+					Name:    displayName(row.DirectoryEntry),
+					RoleId:  input.RoleID,
+					Profile: toProtoProfile(row.Profile),
+				}))
+				if err != nil {
+					return err
+				}
+				result.Invited[i].UserID = resp.Msg.GetUser().GetId()
+				return nil
+			case i < len(invites)+len(updates):
+				upd := updates[i-len(invites)]
+				_, err := c.Members.UpdateUserProfile(ctx, connect.NewRequest(&pidgrv1.UpdateUserProfileRequest{
+					UserId:  upd.Change.UserID,
+					Profile: toProtoProfile(&upd.Profile),
+				}))
+				return err
+			default:
+				_, err := c.Members.DeactivateUser(ctx, connect.NewRequest(&pidgrv1.DeactivateUserRequest{
+					UserId: deactivations[i-len(invites)-len(updates)].UserID,
+				}))
+				return err
+			}
+		}, nil, nil, toolProgress(ctx, req))
+		for i, err := range errs {
+			var change *SyncUserChange
+			switch {
+			case i < len(invites):
+				change = &result.Invited[i]
+				change.Email = invites[i].Email
+			case i < len(invites)+len(updates):
+				change = updates[i-len(invites)].Change
+			default:
+				change = deactivations[i-len(invites)-len(updates)]
+			}
+			if err != nil {
+				change.Error = convert.ErrorMessage(err)
+			}
+		}
+
+		r, err := convert.JSONResult(result)
+		return r, nil, err
+	})
+}
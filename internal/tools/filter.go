@@ -0,0 +1,93 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync/atomic"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ToolFilter restricts the tools a server exposes, so operators can give
+// less-trusted agents a reduced tool surface. Patterns use path.Match glob
+// syntax, e.g. "create_*" or "delete_*". A tool is exposed when it matches an
+// Allow pattern (or Allow is empty) and matches no Deny pattern.
+type ToolFilter struct {
+	Allow []string
+	Deny  []string
+}
+
+// Validate reports the first malformed pattern.
+func (f ToolFilter) Validate() error {
+	for _, pattern := range append(append([]string(nil), f.Allow...), f.Deny...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid tool pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Allowed reports whether the tool with the given name is exposed.
+func (f ToolFilter) Allowed(name string) bool {
+	if len(f.Allow) > 0 && !matchAny(f.Allow, name) {
+		return false
+	}
+	return !matchAny(f.Deny, name)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// toolFilter is the filter applied by toolFilterMiddleware; until one is
+// set, every tool is exposed.
+var toolFilter atomic.Pointer[ToolFilter]
+
+// SetToolFilter sets the filter applied to tool listings and calls. It may
+// be called while the server is running, e.g. on a configuration reload;
+// clients see the new tool list the next time they list tools. Patterns
+// must have been checked with Validate; malformed ones never match.
+func SetToolFilter(f ToolFilter) {
+	toolFilter.Store(&f)
+}
+
+// toolAllowed reports whether the current filter exposes the named tool.
+func toolAllowed(name string) bool {
+	f := toolFilter.Load()
+	return f == nil || f.Allowed(name)
+}
+
+// toolFilterMiddleware hides filtered tools from tools/list and rejects calls
+// to them with the same error the server returns for an unknown tool, so a
+// filtered tool is indistinguishable from one that does not exist.
+func toolFilterMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if call, ok := req.(*mcp.CallToolRequest); ok && call.Params != nil && !toolAllowed(call.Params.Name) {
+			return nil, &jsonrpc.Error{
+				Code:    jsonrpc.CodeInvalidParams,
+				Message: fmt.Sprintf("unknown tool %q", call.Params.Name),
+			}
+		}
+		res, err := next(ctx, method, req)
+		if list, ok := res.(*mcp.ListToolsResult); ok && err == nil {
+			tools := list.Tools[:0:0]
+			for _, tool := range list.Tools {
+				if toolAllowed(tool.Name) {
+					tools = append(tools, tool)
+				}
+			}
+			list.Tools = tools
+		}
+		return res, err
+	}
+}
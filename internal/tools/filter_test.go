@@ -0,0 +1,97 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pidgr/pidgr-mcp/internal/transport"
+)
+
+func TestToolFilterAllowed(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter ToolFilter
+		tool   string
+		want   bool
+	}{
+		{"no filter", ToolFilter{}, "delete_group", true},
+		{"denied", ToolFilter{Deny: []string{"create_*", "delete_*"}}, "delete_group", false},
+		{"not denied", ToolFilter{Deny: []string{"create_*", "delete_*"}}, "get_group", true},
+		{"allowed", ToolFilter{Allow: []string{"get_*", "list_*"}}, "list_groups", true},
+		{"not allowed", ToolFilter{Allow: []string{"get_*", "list_*"}}, "delete_group", false},
+		{"deny wins", ToolFilter{Allow: []string{"*_group"}, Deny: []string{"delete_*"}}, "delete_group", false},
+		{"exact name", ToolFilter{Allow: []string{"get_group"}}, "get_group", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Allowed(tt.tool); got != tt.want {
+				t.Errorf("Allowed(%q) = %v, want %v", tt.tool, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolFilterValidate(t *testing.T) {
+	if err := (ToolFilter{Allow: []string{"get_*"}, Deny: []string{"delete_[a-z]*"}}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := ToolFilter{Deny: []string{"delete_["}}.Validate()
+	if err == nil || !strings.Contains(err.Error(), "delete_[") {
+		t.Errorf("got %v, want an error naming the bad pattern", err)
+	}
+}
+
+func TestToolFilterMiddleware(t *testing.T) {
+	SetToolFilter(ToolFilter{Deny: []string{"create_*", "delete_*"}})
+	defer SetToolFilter(ToolFilter{})
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "pidgr-test", Version: "test"}, nil)
+	RegisterAll(server, transport.NewStaticTokenClients("http://localhost:50051", "test-key", transport.DefaultOptions()))
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, nil)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	go func() { _ = server.Run(context.Background(), serverTransport) }()
+	session, err := client.Connect(context.Background(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer func() { _ = session.Close() }()
+
+	result, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools error: %v", err)
+	}
+	listed := make(map[string]bool)
+	for _, tool := range result.Tools {
+		listed[tool.Name] = true
+	}
+	if listed["create_group"] || listed["delete_group"] {
+		t.Error("denied tools are listed")
+	}
+	if !listed["get_group"] {
+		t.Error("get_group is not listed")
+	}
+
+	_, err = session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "delete_group",
+		Arguments: map[string]any{"groupId": "g1"},
+	})
+	if err == nil || !strings.Contains(err.Error(), `unknown tool "delete_group"`) {
+		t.Errorf("got %v, want an unknown tool error", err)
+	}
+
+	// A new filter applies to sessions already connected.
+	SetToolFilter(ToolFilter{Allow: []string{"get_group"}})
+	result, err = session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools error: %v", err)
+	}
+	if len(result.Tools) != 1 || result.Tools[0].Name != "get_group" {
+		t.Errorf("after SetToolFilter, listed %d tools, want only get_group", len(result.Tools))
+	}
+}
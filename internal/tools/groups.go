@@ -5,6 +5,8 @@ package tools
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"connectrpc.com/connect"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -21,12 +23,18 @@ type CreateGroupInput struct {
 }
 
 type GetGroupInput struct {
-	GroupID string `json:"group_id" jsonschema:"Group UUID"`
+	GroupID      string   `json:"group_id" jsonschema:"Group UUID"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
 }
 
 type ListGroupsInput struct {
-	PageSize  int32  `json:"page_size,omitempty" jsonschema:"Max items per page"`
-	PageToken string `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
+	PageSize     int32    `json:"page_size,omitempty" jsonschema:"Max items per page"`
+	PageToken    string   `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
+	FetchAll     bool     `json:"fetch_all,omitempty" jsonschema:"Fetch every page server-side and return all items together, up to 20 pages; nextPageToken is set if more remain"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+	SummaryOnly  bool     `json:"summary_only,omitempty" jsonschema:"Return only the item count, breakdowns by status and other enum fields, and the first few items"`
 }
 
 type UpdateGroupInput struct {
@@ -41,7 +49,7 @@ type DeleteGroupInput struct {
 
 type AddGroupMembersInput struct {
 	GroupID string   `json:"group_id" jsonschema:"Group UUID"`
-	UserIDs []string `json:"user_ids" jsonschema:"User UUIDs to add (max 100)"`
+	UserIDs []string `json:"user_ids" jsonschema:"User UUIDs to add (max 1000, sent in chunks of 100)"`
 }
 
 type RemoveGroupMembersInput struct {
@@ -50,13 +58,176 @@ type RemoveGroupMembersInput struct {
 }
 
 type ListGroupMembersInput struct {
-	GroupID   string `json:"group_id" jsonschema:"Group UUID"`
-	PageSize  int32  `json:"page_size,omitempty" jsonschema:"Max items per page"`
-	PageToken string `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
+	GroupID      string   `json:"group_id" jsonschema:"Group UUID"`
+	PageSize     int32    `json:"page_size,omitempty" jsonschema:"Max items per page"`
+	PageToken    string   `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
+	FetchAll     bool     `json:"fetch_all,omitempty" jsonschema:"Fetch every page server-side and return all items together, up to 20 pages; nextPageToken is set if more remain"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+	SummaryOnly  bool     `json:"summary_only,omitempty" jsonschema:"Return only the item count, breakdowns by status and other enum fields, and the first few items"`
 }
 
 type GetUserGroupMembershipsInput struct {
-	UserIDs []string `json:"user_ids" jsonschema:"User UUIDs to look up (max 200)"`
+	UserIDs      []string `json:"user_ids" jsonschema:"User UUIDs to look up (max 200)"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+}
+
+type BulkAddGroupMembersInput struct {
+	GroupID string   `json:"group_id" jsonschema:"Group UUID"`
+	Emails  []string `json:"emails,omitempty" jsonschema:"Email addresses of users to add (max 1000 combined with csv rows)"`
+	CSV     string   `json:"csv,omitempty" jsonschema:"CSV content with an email column (e.g. an HR export); without an email header the first column is used"`
+}
+
+type SearchGroupsInput struct {
+	Query        string   `json:"query,omitempty" jsonschema:"Case-insensitive substring to match against group names"`
+	MinMembers   int32    `json:"min_members,omitempty" jsonschema:"Only groups with at least this many members"`
+	MaxMembers   int32    `json:"max_members,omitempty" jsonschema:"Only groups with at most this many members (0 = no limit)"`
+	Limit        int32    `json:"limit,omitempty" jsonschema:"Max groups to return (default 20, max 100)"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+	SummaryOnly  bool     `json:"summary_only,omitempty" jsonschema:"Return only the item count, breakdowns by status and other enum fields, and the first few items"`
+}
+
+type SyncGroupFromTeamInput struct {
+	GroupID string `json:"group_id" jsonschema:"Group UUID whose membership is reconciled"`
+	TeamID  string `json:"team_id" jsonschema:"Team UUID to copy membership from"`
+	DryRun  bool   `json:"dry_run,omitempty" jsonschema:"Report what would change without modifying the group"`
+	Confirm bool   `json:"confirm,omitempty" jsonschema:"Must be true to remove every group member when the team has no members"`
+}
+
+type MergeGroupsInput struct {
+	SourceGroupID string `json:"source_group_id" jsonschema:"Group UUID whose members are merged"`
+	TargetGroupID string `json:"target_group_id" jsonschema:"Group UUID that receives the members"`
+	DeleteSource  bool   `json:"delete_source,omitempty" jsonschema:"Delete the source group after all members were added to the target"`
+	DryRun        bool   `json:"dry_run,omitempty" jsonschema:"Report what would change without modifying any group"`
+}
+
+type ExportGroupMembersInput struct {
+	GroupID string `json:"group_id" jsonschema:"Group UUID to export"`
+}
+
+type DiffGroupMembersInput struct {
+	GroupA string `json:"group_a" jsonschema:"First group UUID"`
+	GroupB string `json:"group_b" jsonschema:"Second group UUID"`
+}
+
+type CloneGroupInput struct {
+	GroupID string `json:"group_id" jsonschema:"Group UUID to clone"`
+	Name    string `json:"name,omitempty" jsonschema:"Name for the new group (defaults to the source name plus suffix)"`
+	Suffix  string `json:"suffix,omitempty" jsonschema:"Suffix appended to the source name when name is omitted (default ' (copy)')"`
+}
+
+type RemoveAllGroupMembersInput struct {
+	GroupID string `json:"group_id" jsonschema:"Group UUID to clear"`
+	Confirm bool   `json:"confirm,omitempty" jsonschema:"Must be true to actually remove members; otherwise only the current member count is reported"`
+}
+
+// ── Output types ────────────────────────────────────────────────────────────
+
+type BulkAddGroupMembersResult struct {
+	GroupID   string       `json:"groupId"`
+	Requested int          `json:"requested"`
+	Resolved  int          `json:"resolved"`
+	Added     int          `json:"added"`
+	Failures  []RowFailure `json:"failures,omitempty"`
+}
+
+type SyncGroupFromTeamResult struct {
+	GroupID   string       `json:"groupId"`
+	TeamID    string       `json:"teamId"`
+	DryRun    bool         `json:"dryRun,omitempty"`
+	Added     []string     `json:"added"`
+	Removed   []string     `json:"removed"`
+	Unchanged int          `json:"unchanged"`
+	Failures  []RowFailure `json:"failures,omitempty"`
+}
+
+type MergeGroupsResult struct {
+	SourceGroupID   string       `json:"sourceGroupId"`
+	TargetGroupID   string       `json:"targetGroupId"`
+	DryRun          bool         `json:"dryRun,omitempty"`
+	Added           []string     `json:"added"`
+	AlreadyInTarget int          `json:"alreadyInTarget"`
+	SourceDeleted   bool         `json:"sourceDeleted"`
+	Failures        []RowFailure `json:"failures,omitempty"`
+}
+
+type GroupMembershipDiff struct {
+	GroupA string   `json:"groupA"`
+	GroupB string   `json:"groupB"`
+	OnlyA  []string `json:"onlyA"`
+	OnlyB  []string `json:"onlyB"`
+	Both   []string `json:"both"`
+}
+
+type CloneGroupResult struct {
+	SourceGroupID string       `json:"sourceGroupId"`
+	GroupID       string       `json:"groupId"`
+	Name          string       `json:"name"`
+	MembersCopied int          `json:"membersCopied"`
+	Failures      []RowFailure `json:"failures,omitempty"`
+}
+
+type RemoveAllGroupMembersResult struct {
+	GroupID  string       `json:"groupId"`
+	Removed  int          `json:"removed"`
+	Failures []RowFailure `json:"failures,omitempty"`
+}
+
+// ── Helpers ─────────────────────────────────────────────────────────────────
+
+// listAllGroups fetches every group in the organization.
+func listAllGroups(ctx context.Context, c *transport.Clients) ([]*pidgrv1.Group, error) {
+	return fetchAll(ctx, func(ctx context.Context, p *pidgrv1.Pagination) ([]*pidgrv1.Group, string, error) {
+		resp, err := c.Groups.ListGroups(ctx, connect.NewRequest(&pidgrv1.ListGroupsRequest{
+			Pagination: p,
+		}))
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Msg.GetGroups(), resp.Msg.GetPaginationMeta().GetNextPageToken(), nil
+	})
+}
+
+// listAllGroupMemberIDs fetches the user UUIDs of every member of a group.
+func listAllGroupMemberIDs(ctx context.Context, c *transport.Clients, groupID string) ([]string, error) {
+	return fetchAll(ctx, func(ctx context.Context, p *pidgrv1.Pagination) ([]string, string, error) {
+		resp, err := c.Groups.ListGroupMembers(ctx, connect.NewRequest(&pidgrv1.ListGroupMembersRequest{
+			GroupId:    groupID,
+			Pagination: p,
+		}))
+		if err != nil {
+			return nil, "", err
+		}
+		ids := make([]string, len(resp.Msg.GetUsers()))
+		for i, u := range resp.Msg.GetUsers() {
+			ids[i] = u.GetId()
+		}
+		return ids, resp.Msg.GetPaginationMeta().GetNextPageToken(), nil
+	})
+}
+
+// addGroupMembersChunked adds ids to a group in backend-sized chunks.
+func addGroupMembersChunked(ctx context.Context, c *transport.Clients, groupID string, ids []string, progress progressFunc) ([]string, []RowFailure) {
+	return applyInChunks(ctx, ids, func(ctx context.Context, chunk []string) error {
+		_, err := c.Groups.AddGroupMembers(ctx, connect.NewRequest(&pidgrv1.AddGroupMembersRequest{
+			GroupId: groupID,
+			UserIds: chunk,
+		}))
+		return err
+	}, progress)
+}
+
+// removeGroupMembersChunked removes ids from a group in backend-sized chunks.
+func removeGroupMembersChunked(ctx context.Context, c *transport.Clients, groupID string, ids []string, progress progressFunc) ([]string, []RowFailure) {
+	return applyInChunks(ctx, ids, func(ctx context.Context, chunk []string) error {
+		_, err := c.Groups.RemoveGroupMembers(ctx, connect.NewRequest(&pidgrv1.RemoveGroupMembersRequest{
+			GroupId: groupID,
+			UserIds: chunk,
+		}))
+		return err
+	}, progress)
 }
 
 // ── Registration ────────────────────────────────────────────────────────────
@@ -74,7 +245,7 @@ func registerGroupTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -89,7 +260,7 @@ func registerGroupTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -97,17 +268,20 @@ func registerGroupTools(s *mcp.Server, c *transport.Clients) {
 		Name:        "list_groups",
 		Description: "List groups in the organization with pagination. Call this first to discover group UUIDs before using other group tools.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListGroupsInput) (*mcp.CallToolResult, any, error) {
-		resp, err := c.Groups.ListGroups(ctx, connect.NewRequest(&pidgrv1.ListGroupsRequest{
-			Pagination: &pidgrv1.Pagination{
-				PageSize:  clampPageSize(input.PageSize),
-				PageToken: input.PageToken,
-			},
-		}))
+		msg, err := collectPages(ctx, input.PageSize, input.PageToken, input.FetchAll, func(ctx context.Context, p *pidgrv1.Pagination) (*pidgrv1.ListGroupsResponse, string, error) {
+			resp, err := c.Groups.ListGroups(ctx, connect.NewRequest(&pidgrv1.ListGroupsRequest{
+				Pagination: p,
+			}))
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Msg, resp.Msg.GetPaginationMeta().GetNextPageToken(), nil
+		})
 		if err != nil {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, msg)
 		return r, nil, err
 	})
 
@@ -124,7 +298,7 @@ func registerGroupTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -144,12 +318,23 @@ func registerGroupTools(s *mcp.Server, c *transport.Clients) {
 
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "add_group_members",
-		Description: "Add users to a group (idempotent). Use list_groups to find the group UUID and list_users to find user UUIDs.",
+		Description: "Add users to a group (idempotent). Lists over 100 users are split into chunks of 100 that run concurrently, and an aggregate result with any per-chunk failures is returned. Use list_groups to find the group UUID and list_users to find user UUIDs.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input AddGroupMembersInput) (*mcp.CallToolResult, any, error) {
-		if err := validateBatchSize(input.UserIDs, 100); err != nil {
+		if err := validateBatchSize(input.UserIDs, maxBatchSize); err != nil {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
+		if len(input.UserIDs) > membershipChunkSize {
+			added, failures := addGroupMembersChunked(ctx, c, input.GroupID, input.UserIDs, toolProgress(ctx, req))
+			r, err := convert.JSONResult(ChunkedMembershipResult{
+				GroupID:   input.GroupID,
+				Requested: len(input.UserIDs),
+				Added:     len(added),
+				Chunks:    len(chunkIDs(input.UserIDs, membershipChunkSize)),
+				Failures:  failures,
+			})
+			return r, nil, err
+		}
 		resp, err := c.Groups.AddGroupMembers(ctx, connect.NewRequest(&pidgrv1.AddGroupMembersRequest{
 			GroupId: input.GroupID,
 			UserIds: input.UserIDs,
@@ -158,7 +343,7 @@ func registerGroupTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -178,7 +363,7 @@ func registerGroupTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -186,18 +371,21 @@ func registerGroupTools(s *mcp.Server, c *transport.Clients) {
 		Name:        "list_group_members",
 		Description: "List members of a group with pagination. Use list_groups to find the group UUID.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListGroupMembersInput) (*mcp.CallToolResult, any, error) {
-		resp, err := c.Groups.ListGroupMembers(ctx, connect.NewRequest(&pidgrv1.ListGroupMembersRequest{
-			GroupId: input.GroupID,
-			Pagination: &pidgrv1.Pagination{
-				PageSize:  clampPageSize(input.PageSize),
-				PageToken: input.PageToken,
-			},
-		}))
+		msg, err := collectPages(ctx, input.PageSize, input.PageToken, input.FetchAll, func(ctx context.Context, p *pidgrv1.Pagination) (*pidgrv1.ListGroupMembersResponse, string, error) {
+			resp, err := c.Groups.ListGroupMembers(ctx, connect.NewRequest(&pidgrv1.ListGroupMembersRequest{
+				GroupId:    input.GroupID,
+				Pagination: p,
+			}))
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Msg, resp.Msg.GetPaginationMeta().GetNextPageToken(), nil
+		})
 		if err != nil {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, msg)
 		return r, nil, err
 	})
 
@@ -216,7 +404,311 @@ func registerGroupTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "bulk_add_group_members",
+		Description: "Add users to a group by email address, from a list or CSV content (e.g. an HR export). Emails are resolved to users, additions are chunked, and per-row failures (unknown emails, failed chunks) are reported. Use list_groups to find the group UUID.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input BulkAddGroupMembersInput) (*mcp.CallToolResult, any, error) {
+		var rows []inputRow
+		for i, e := range input.Emails {
+			rows = append(rows, inputRow{Row: i + 1, Value: e})
+		}
+		if input.CSV != "" {
+			csvRows, err := parseEmailCSV(input.CSV)
+			if err != nil {
+				r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, err))
+				return r, nil, nil
+			}
+			rows = append(rows, csvRows...)
+		}
+		if len(rows) == 0 {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("emails or csv is required")))
+			return r, nil, nil
+		}
+		if len(rows) > maxBatchSize {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("batch size %d exceeds maximum of %d", len(rows), maxBatchSize)))
+			return r, nil, nil
+		}
+
+		resolved, err := resolveEmails(ctx, c, rows)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+
+		result := BulkAddGroupMembersResult{
+			GroupID:   input.GroupID,
+			Requested: len(rows),
+			Resolved:  len(resolved.IDs),
+			Failures:  resolved.Failures,
+		}
+		added, failures := addGroupMembersChunked(ctx, c, input.GroupID, resolved.IDs, toolProgress(ctx, req))
+		result.Added = len(added)
+		for _, f := range failures {
+			row := resolved.Rows[f.Value]
+			result.Failures = append(result.Failures, RowFailure{Row: row.Row, Value: row.Value, Error: f.Error})
+		}
+		r, err := convert.JSONResult(result)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "search_groups",
+		Description: "Find groups by name substring and member count without paginating through list_groups.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input SearchGroupsInput) (*mcp.CallToolResult, any, error) {
+		groups, err := listAllGroups(ctx, c)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		query := strings.ToLower(input.Query)
+		limit := int(clampPageSize(input.Limit))
+		var matches []*pidgrv1.Group
+		for _, g := range groups {
+			if !strings.Contains(strings.ToLower(g.GetName()), query) {
+				continue
+			}
+			if g.GetMemberCount() < input.MinMembers {
+				continue
+			}
+			if input.MaxMembers > 0 && g.GetMemberCount() > input.MaxMembers {
+				continue
+			}
+			matches = append(matches, g)
+			if len(matches) == limit {
+				break
+			}
+		}
+		r, err := convert.ProtoResult(ctx, &pidgrv1.ListGroupsResponse{Groups: matches})
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "sync_group_from_team",
+		Description: "Reconcile a group's membership to exactly match a team: team members missing from the group are added and group members not in the team are removed. Returns a summary of changes. Set dry_run to see what would change first. Syncing from a team with no members would empty the group and is refused unless confirm=true. Use list_groups and list_teams to find the UUIDs.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input SyncGroupFromTeamInput) (*mcp.CallToolResult, any, error) {
+		teamIDs, err := listAllTeamMemberIDs(ctx, c, input.TeamID)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		groupIDs, err := listAllGroupMemberIDs(ctx, c, input.GroupID)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+
+		toAdd, toRemove, unchanged := diffIDs(teamIDs, groupIDs)
+		if len(teamIDs) == 0 && len(toRemove) > 0 && !input.Confirm {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf(
+				"team %s has no members; syncing would remove all %d members of group %s, call again with confirm=true to proceed",
+				input.TeamID, len(toRemove), input.GroupID,
+			)))
+			return r, nil, nil
+		}
+		result := SyncGroupFromTeamResult{
+			GroupID:   input.GroupID,
+			TeamID:    input.TeamID,
+			DryRun:    input.DryRun,
+			Unchanged: len(unchanged),
+		}
+		if input.DryRun {
+			result.Added = nonNil(toAdd)
+			result.Removed = nonNil(toRemove)
+			r, err := convert.JSONResult(result)
+			return r, nil, err
+		}
+
+		added, addFailures := addGroupMembersChunked(ctx, c, input.GroupID, toAdd, toolProgress(ctx, req))
+		removed, removeFailures := removeGroupMembersChunked(ctx, c, input.GroupID, toRemove, toolProgress(ctx, req))
+		result.Added = nonNil(added)
+		result.Removed = nonNil(removed)
+		result.Failures = append(addFailures, removeFailures...)
+		r, err := convert.JSONResult(result)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "merge_groups",
+		Description: "Merge one group into another: every member of the source group is added to the target group, and the source group is optionally deleted once all members were added. Set dry_run to see what would change first. Use list_groups to find the UUIDs.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input MergeGroupsInput) (*mcp.CallToolResult, any, error) {
+		if input.SourceGroupID == input.TargetGroupID {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("source and target group must differ")))
+			return r, nil, nil
+		}
+		sourceIDs, err := listAllGroupMemberIDs(ctx, c, input.SourceGroupID)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		targetIDs, err := listAllGroupMemberIDs(ctx, c, input.TargetGroupID)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+
+		toAdd, _, both := diffIDs(sourceIDs, targetIDs)
+		result := MergeGroupsResult{
+			SourceGroupID:   input.SourceGroupID,
+			TargetGroupID:   input.TargetGroupID,
+			DryRun:          input.DryRun,
+			AlreadyInTarget: len(both),
+		}
+		if input.DryRun {
+			result.Added = nonNil(toAdd)
+			r, err := convert.JSONResult(result)
+			return r, nil, err
+		}
+
+		added, failures := addGroupMembersChunked(ctx, c, input.TargetGroupID, toAdd, toolProgress(ctx, req))
+		result.Added = nonNil(added)
+		result.Failures = failures
+		if input.DeleteSource && len(failures) == 0 {
+			_, err := c.Groups.DeleteGroup(ctx, connect.NewRequest(&pidgrv1.DeleteGroupRequest{
+				GroupId: input.SourceGroupID,
+			}))
+			if err != nil {
+				result.Failures = append(result.Failures, RowFailure{Value: input.SourceGroupID, Error: convert.ErrorMessage(err)})
+			} else {
+				result.SourceDeleted = true
+			}
+		}
+		r, err := convert.JSONResult(result)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "export_group_members",
+		Description: "Export every member of a group as a CSV resource (user_id, name, email, department), fetching all pages server-side. Use list_groups to find the group UUID.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ExportGroupMembersInput) (*mcp.CallToolResult, any, error) {
+		memberIDs, err := listAllGroupMemberIDs(ctx, c, input.GroupID)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		users, err := listAllUsers(ctx, c)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		byID := make(map[string]*pidgrv1.User, len(users))
+		for _, u := range users {
+			byID[u.GetId()] = u
+		}
+
+		rows := make([][]string, len(memberIDs))
+		for i, id := range memberIDs {
+			u := byID[id]
+			rows[i] = []string{id, u.GetName(), u.GetEmail(), u.GetProfile().GetDepartment()}
+		}
+		data, err := writeCSV([]string{"user_id", "name", "email", "department"}, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		return convert.ResourceResult(
+			fmt.Sprintf("Exported %d members of group %s", len(rows), input.GroupID),
+			"pidgr://groups/"+input.GroupID+"/members.csv",
+			"text/csv",
+			data,
+		), nil, nil
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "diff_group_members",
+		Description: "Compare the membership of two groups, returning user UUIDs only in group A, only in group B, and in both. Useful before merge_groups or to check audience coverage. Use list_groups to find the UUIDs.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input DiffGroupMembersInput) (*mcp.CallToolResult, any, error) {
+		aIDs, err := listAllGroupMemberIDs(ctx, c, input.GroupA)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		bIDs, err := listAllGroupMemberIDs(ctx, c, input.GroupB)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		onlyA, onlyB, both := diffIDs(aIDs, bIDs)
+		r, err := convert.JSONResult(GroupMembershipDiff{
+			GroupA: input.GroupA,
+			GroupB: input.GroupB,
+			OnlyA:  nonNil(onlyA),
+			OnlyB:  nonNil(onlyB),
+			Both:   nonNil(both),
+		})
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "clone_group",
+		Description: "Create a new group copying another group's description and membership, named after the source with a suffix unless a name is given. Use list_groups or search_groups to find the source group UUID.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input CloneGroupInput) (*mcp.CallToolResult, any, error) {
+		src, err := c.Groups.GetGroup(ctx, connect.NewRequest(&pidgrv1.GetGroupRequest{
+			GroupId: input.GroupID,
+		}))
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		memberIDs, err := listAllGroupMemberIDs(ctx, c, input.GroupID)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+
+		name := input.Name
+		if name == "" {
+			suffix := input.Suffix
+			if suffix == "" {
+				suffix = " (copy)"
+			}
+			name = src.Msg.GetGroup().GetName() + suffix
+		}
+		created, err := c.Groups.CreateGroup(ctx, connect.NewRequest(&pidgrv1.CreateGroupRequest{
+			Name:        name,
+			Description: src.Msg.GetGroup().GetDescription(),
+		}))
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+
+		newID := created.Msg.GetGroup().GetId()
+		copied, failures := addGroupMembersChunked(ctx, c, newID, memberIDs, toolProgress(ctx, req))
+		r, err := convert.JSONResult(CloneGroupResult{
+			SourceGroupID: input.GroupID,
+			GroupID:       newID,
+			Name:          created.Msg.GetGroup().GetName(),
+			MembersCopied: len(copied),
+			Failures:      failures,
+		})
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "remove_all_group_members",
+		Description: "Remove every member from a group, e.g. to reset an audience between campaign cycles. The group itself is kept. Without confirm=true only the number of members that would be removed is reported. Use list_groups to find the group UUID.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input RemoveAllGroupMembersInput) (*mcp.CallToolResult, any, error) {
+		memberIDs, err := listAllGroupMemberIDs(ctx, c, input.GroupID)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		if !input.Confirm {
+			return convert.SuccessResult(fmt.Sprintf(
+				"Group %s has %d members. No members were removed; call again with confirm=true to remove them all.",
+				input.GroupID, len(memberIDs),
+			)), nil, nil
+		}
+
+		removed, failures := removeGroupMembersChunked(ctx, c, input.GroupID, memberIDs, toolProgress(ctx, req))
+		r, err := convert.JSONResult(RemoveAllGroupMembersResult{
+			GroupID:  input.GroupID,
+			Removed:  len(removed),
+			Failures: failures,
+		})
 		return r, nil, err
 	})
 }
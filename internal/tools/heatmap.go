@@ -5,6 +5,14 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"connectrpc.com/connect"
@@ -12,6 +20,7 @@ import (
 	pidgrv1 "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1"
 	"github.com/pidgr/pidgr-mcp/internal/convert"
 	"github.com/pidgr/pidgr-mcp/internal/transport"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -23,73 +32,891 @@ type QueryHeatmapDataInput struct {
 	DateTo         string   `json:"date_to,omitempty" jsonschema:"End of time range (RFC 3339)"`
 	CampaignID     string   `json:"campaign_id,omitempty" jsonschema:"Filter by campaign UUID"`
 	UserID         string   `json:"user_id,omitempty" jsonschema:"Filter by user UUID (required for USER_SPECIFIC mode)"`
+	GroupID        string   `json:"group_id,omitempty" jsonschema:"Only count touches by members of this group (TOTAL mode, at most 200 members)"`
+	TeamID         string   `json:"team_id,omitempty" jsonschema:"Only count touches by members of this team (TOTAL mode, at most 200 members)"`
 	GridResolution float32  `json:"grid_resolution,omitempty" jsonschema:"Grid resolution (0.005 to 0.1, default 0.02)"`
 	Mode           string   `json:"mode,omitempty" jsonschema:"Aggregation mode: TOTAL (default), MEDIAN, or USER_SPECIFIC"`
 	EventTypes     []string `json:"event_types,omitempty" jsonschema:"Filter by event types: TAP, LONG_PRESS, SCROLL, ACTION_CLICK"`
 }
 
-type ListScreenshotsInput struct{}
+type ListScreenshotsInput struct {
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+	SummaryOnly  bool     `json:"summary_only,omitempty" jsonschema:"Return only the item count, breakdowns by status and other enum fields, and the first few items"`
+}
+
+type GetScreenshotInput struct {
+	ScreenName string `json:"screen_name" jsonschema:"Screen route name"`
+}
+
+type HeatmapSliceInput struct {
+	Label      string `json:"label,omitempty" jsonschema:"Name for this side in the result (e.g. the campaign name or \"last week\")"`
+	CampaignID string `json:"campaign_id,omitempty" jsonschema:"Filter by campaign UUID"`
+	DateFrom   string `json:"date_from,omitempty" jsonschema:"Start of time range (RFC 3339)"`
+	DateTo     string `json:"date_to,omitempty" jsonschema:"End of time range (RFC 3339)"`
+}
+
+type CompareHeatmapsInput struct {
+	ScreenName     string            `json:"screen_name" jsonschema:"Screen route name"`
+	A              HeatmapSliceInput `json:"a" jsonschema:"Baseline heatmap filters"`
+	B              HeatmapSliceInput `json:"b" jsonschema:"Heatmap filters to compare against the baseline"`
+	GridResolution float32           `json:"grid_resolution,omitempty" jsonschema:"Grid resolution (0.005 to 0.1, default 0.02)"`
+	EventTypes     []string          `json:"event_types,omitempty" jsonschema:"Filter by event types: TAP, LONG_PRESS, SCROLL, ACTION_CLICK"`
+	Limit          int32             `json:"limit,omitempty" jsonschema:"Max cells to return, largest changes first (default 20, max 100)"`
+}
+
+type CohortInput struct {
+	Label   string `json:"label,omitempty" jsonschema:"Name for this cohort in the result (e.g. \"managers\")"`
+	GroupID string `json:"group_id,omitempty" jsonschema:"Group UUID defining the cohort"`
+	TeamID  string `json:"team_id,omitempty" jsonschema:"Team UUID defining the cohort"`
+}
+
+type CompareCohortsInput struct {
+	ScreenName     string      `json:"screen_name" jsonschema:"Screen route name"`
+	A              CohortInput `json:"a" jsonschema:"Baseline cohort"`
+	B              CohortInput `json:"b" jsonschema:"Cohort to compare against the baseline"`
+	CampaignID     string      `json:"campaign_id,omitempty" jsonschema:"Filter by campaign UUID"`
+	DateFrom       string      `json:"date_from,omitempty" jsonschema:"Start of time range (RFC 3339)"`
+	DateTo         string      `json:"date_to,omitempty" jsonschema:"End of time range (RFC 3339)"`
+	GridResolution float32     `json:"grid_resolution,omitempty" jsonschema:"Grid resolution (0.005 to 0.1, default 0.02)"`
+	EventTypes     []string    `json:"event_types,omitempty" jsonschema:"Filter by event types: TAP, LONG_PRESS, SCROLL, ACTION_CLICK"`
+	Limit          int32       `json:"limit,omitempty" jsonschema:"Max cells to return, largest changes first (default 20, max 100)"`
+}
+
+type DetectHeatmapAnomaliesInput struct {
+	ScreenNames    []string `json:"screen_names,omitempty" jsonschema:"Screens to check (default: every screen with a screenshot, max 50)"`
+	RecentDays     int      `json:"recent_days,omitempty" jsonschema:"Length of the recent window in days, ending now (default 7)"`
+	BaselineDays   int      `json:"baseline_days,omitempty" jsonschema:"Length of the baseline window in days, immediately before the recent window (default 28)"`
+	Threshold      float64  `json:"threshold,omitempty" jsonschema:"Distribution shift (0 to 1) at or above which a screen is flagged (default 0.2)"`
+	VolumeDrop     float64  `json:"volume_drop,omitempty" jsonschema:"Drop in daily event rate (0 to 1) from the baseline to the recent window at or above which a screen is flagged (default 0.5)"`
+	MinEvents      int32    `json:"min_events,omitempty" jsonschema:"Minimum events in each window for a screen's distribution shift to be scored (default 50)"`
+	EventTypes     []string `json:"event_types,omitempty" jsonschema:"Filter by event types: TAP, LONG_PRESS, SCROLL, ACTION_CLICK"`
+	GridResolution float32  `json:"grid_resolution,omitempty" jsonschema:"Grid resolution (0.005 to 0.1, default 0.05)"`
+}
+
+type RenderHeatmapInput struct {
+	ScreenName     string   `json:"screen_name" jsonschema:"Screen route name"`
+	DateFrom       string   `json:"date_from,omitempty" jsonschema:"Start of time range (RFC 3339)"`
+	DateTo         string   `json:"date_to,omitempty" jsonschema:"End of time range (RFC 3339)"`
+	CampaignID     string   `json:"campaign_id,omitempty" jsonschema:"Filter by campaign UUID"`
+	UserID         string   `json:"user_id,omitempty" jsonschema:"Filter by user UUID (required for USER_SPECIFIC mode)"`
+	GroupID        string   `json:"group_id,omitempty" jsonschema:"Only count touches by members of this group (TOTAL mode, at most 200 members)"`
+	TeamID         string   `json:"team_id,omitempty" jsonschema:"Only count touches by members of this team (TOTAL mode, at most 200 members)"`
+	GridResolution float32  `json:"grid_resolution,omitempty" jsonschema:"Grid resolution (0.005 to 0.1, default 0.02)"`
+	Mode           string   `json:"mode,omitempty" jsonschema:"Aggregation mode: TOTAL (default), MEDIAN, or USER_SPECIFIC"`
+	EventTypes     []string `json:"event_types,omitempty" jsonschema:"Filter by event types: TAP, LONG_PRESS, SCROLL, ACTION_CLICK"`
+}
+
+type ExportHeatmapDataInput struct {
+	ScreenName     string   `json:"screen_name" jsonschema:"Screen route name"`
+	DateFrom       string   `json:"date_from,omitempty" jsonschema:"Start of time range (RFC 3339)"`
+	DateTo         string   `json:"date_to,omitempty" jsonschema:"End of time range (RFC 3339)"`
+	CampaignID     string   `json:"campaign_id,omitempty" jsonschema:"Filter by campaign UUID"`
+	UserID         string   `json:"user_id,omitempty" jsonschema:"Filter by user UUID (required for USER_SPECIFIC mode)"`
+	GroupID        string   `json:"group_id,omitempty" jsonschema:"Only count touches by members of this group (TOTAL mode, at most 200 members)"`
+	TeamID         string   `json:"team_id,omitempty" jsonschema:"Only count touches by members of this team (TOTAL mode, at most 200 members)"`
+	GridResolution float32  `json:"grid_resolution,omitempty" jsonschema:"Grid resolution (0.005 to 0.1, default 0.02)"`
+	Mode           string   `json:"mode,omitempty" jsonschema:"Aggregation mode: TOTAL (default), MEDIAN, or USER_SPECIFIC"`
+	EventTypes     []string `json:"event_types,omitempty" jsonschema:"Filter by event types: TAP, LONG_PRESS, SCROLL, ACTION_CLICK"`
+	Format         string   `json:"format,omitempty" jsonschema:"Export format: csv (default) or json"`
+}
+
+// ── Output types ────────────────────────────────────────────────────────────
+
+type HeatmapSliceSummary struct {
+	Label       string `json:"label,omitempty"`
+	TotalEvents int32  `json:"totalEvents"`
+}
+
+type HeatmapComparison struct {
+	ScreenName     string              `json:"screenName"`
+	GridResolution float64             `json:"gridResolution"`
+	Shift          float64             `json:"shift"`
+	A              HeatmapSliceSummary `json:"a"`
+	B              HeatmapSliceSummary `json:"b"`
+	Regions        []RegionChange      `json:"regions"`
+	Cells          []HeatmapDelta      `json:"cells"`
+	TotalCells     int                 `json:"totalCells"`
+}
+
+// HeatmapExport is the JSON form of an exported heatmap grid.
+type HeatmapExport struct {
+	ScreenName     string              `json:"screenName"`
+	GridResolution float32             `json:"gridResolution"`
+	TotalEvents    int32               `json:"totalEvents"`
+	Cells          []HeatmapExportCell `json:"cells"`
+}
+
+// HeatmapExportCell is one grid cell; X and Y are the cell's center as
+// fractions of the screen width and height.
+type HeatmapExportCell struct {
+	X     float32 `json:"x"`
+	Y     float32 `json:"y"`
+	Value float32 `json:"value"`
+}
+
+// ScreenAnomaly scores how much one screen's touch distribution moved from
+// the baseline window to the recent window.
+type ScreenAnomaly struct {
+	ScreenName     string         `json:"screenName"`
+	Shift          float64        `json:"shift"`
+	Anomalous      bool           `json:"anomalous"`
+	VolumeDrop     float64        `json:"volumeDrop"`
+	RecentEvents   int32          `json:"recentEvents"`
+	BaselineEvents int32          `json:"baselineEvents"`
+	TopRegions     []RegionChange `json:"topRegions,omitempty"`
+	Skipped        string         `json:"skipped,omitempty"`
+}
+
+type HeatmapAnomalyReport struct {
+	RecentFrom   string          `json:"recentFrom"`
+	BaselineFrom string          `json:"baselineFrom"`
+	Threshold    float64         `json:"threshold"`
+	VolumeDrop   float64         `json:"volumeDrop"`
+	Flagged      int             `json:"flagged"`
+	Screens      []ScreenAnomaly `json:"screens"`
+}
+
+// ── Helpers ─────────────────────────────────────────────────────────────────
+
+// Defaults for detect_heatmap_anomalies. A coarser grid than the query
+// default keeps sparse screens from looking anomalous through noise.
+const (
+	defaultAnomalyRecentDays   = 7
+	defaultAnomalyBaselineDays = 28
+	defaultAnomalyThreshold    = 0.2
+	defaultAnomalyVolumeDrop   = 0.5
+	defaultAnomalyMinEvents    = 50
+	defaultAnomalyResolution   = 0.05
+	maxAnomalyScreens          = 50
+	anomalyTopRegions          = 3
+)
+
+// maxCohortMembers bounds the per-member heatmap queries made for one group
+// or team.
+const maxCohortMembers = 200
+
+// defaultGridResolution matches the backend default for query_heatmap_data.
+const defaultGridResolution = 0.02
+
+// buildHeatmapRequest converts query_heatmap_data input into a backend request.
+func buildHeatmapRequest(input QueryHeatmapDataInput) *pidgrv1.QueryHeatmapDataRequest {
+	protoReq := &pidgrv1.QueryHeatmapDataRequest{
+		ScreenName:     input.ScreenName,
+		CampaignId:     input.CampaignID,
+		UserId:         input.UserID,
+		GridResolution: input.GridResolution,
+	}
+
+	if input.DateFrom != "" {
+		if t, err := time.Parse(time.RFC3339, input.DateFrom); err == nil {
+			protoReq.DateFrom = timestamppb.New(t)
+		}
+	}
+	if input.DateTo != "" {
+		if t, err := time.Parse(time.RFC3339, input.DateTo); err == nil {
+			protoReq.DateTo = timestamppb.New(t)
+		}
+	}
+
+	if input.Mode != "" {
+		if v, ok := pidgrv1.HeatmapMode_value[input.Mode]; ok {
+			protoReq.Mode = pidgrv1.HeatmapMode(v)
+		} else if v, ok := pidgrv1.HeatmapMode_value["HEATMAP_MODE_"+input.Mode]; ok {
+			protoReq.Mode = pidgrv1.HeatmapMode(v)
+		}
+	}
+
+	for _, et := range input.EventTypes {
+		if v, ok := pidgrv1.TouchEventType_value[et]; ok {
+			protoReq.EventTypes = append(protoReq.EventTypes, pidgrv1.TouchEventType(v))
+		} else if v, ok := pidgrv1.TouchEventType_value["TOUCH_EVENT_TYPE_"+et]; ok {
+			protoReq.EventTypes = append(protoReq.EventTypes, pidgrv1.TouchEventType(v))
+		}
+	}
+	return protoReq
+}
+
+// heatGrid is a heatmap query result in render form.
+type heatGrid struct {
+	cells         []heatCell
+	totalEvents   int32
+	resolution    float64
+	screenshotURL string
+}
+
+// queryHeatmap runs a heatmap query. A group or team filter is applied by
+// queryCohortHeatmap, since the backend has no such filter.
+func queryHeatmap(ctx context.Context, c *transport.Clients, input QueryHeatmapDataInput, progress progressFunc) (*pidgrv1.QueryHeatmapDataResponse, error) {
+	if input.GroupID != "" || input.TeamID != "" {
+		return queryCohortHeatmap(ctx, c, input, progress)
+	}
+	resp, err := c.Heatmaps.QueryHeatmapData(ctx, connect.NewRequest(buildHeatmapRequest(input)))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Msg, nil
+}
+
+// queryHeatCells runs a heatmap query and returns its cells, total event
+// count, effective grid resolution, and screenshot URL.
+func queryHeatCells(ctx context.Context, c *transport.Clients, input QueryHeatmapDataInput, progress progressFunc) (heatGrid, error) {
+	resp, err := queryHeatmap(ctx, c, input, progress)
+	if err != nil {
+		return heatGrid{}, err
+	}
+	return newHeatGrid(resp, input.GridResolution), nil
+}
+
+// newHeatGrid converts a heatmap response queried at resolution, zero
+// meaning the default, to render form.
+func newHeatGrid(resp *pidgrv1.QueryHeatmapDataResponse, resolution float32) heatGrid {
+	res := float64(resolution)
+	if res == 0 {
+		res = defaultGridResolution
+	}
+	return heatGrid{
+		cells:         heatCells(resp.GetDataPoints(), res),
+		totalEvents:   totalTouches(resp),
+		resolution:    res,
+		screenshotURL: resp.GetScreenshotUrl(),
+	}
+}
+
+// compareHeatmapQueries runs two heatmap queries over the same screen and
+// compares their normalized distributions, keeping the largest limit cells.
+// Shift and regions are computed over all cells before truncation.
+func compareHeatmapQueries(ctx context.Context, c *transport.Clients, labelA string, a QueryHeatmapDataInput, labelB string, b QueryHeatmapDataInput, limit int32) (HeatmapComparison, error) {
+	gridA, err := queryHeatCells(ctx, c, a, nil)
+	if err != nil {
+		return HeatmapComparison{}, err
+	}
+	gridB, err := queryHeatCells(ctx, c, b, nil)
+	if err != nil {
+		return HeatmapComparison{}, err
+	}
+	return compareHeatGrids(a.ScreenName, labelA, gridA, labelB, gridB, limit), nil
+}
+
+// compareHeatGrids compares two heatmaps of a screen as described for
+// compareHeatmapQueries.
+func compareHeatGrids(screenName, labelA string, gridA heatGrid, labelB string, gridB heatGrid, limit int32) HeatmapComparison {
+	deltas := compareHeatCells(gridA.cells, gridB.cells, gridA.resolution)
+	result := HeatmapComparison{
+		ScreenName:     screenName,
+		GridResolution: gridA.resolution,
+		Shift:          distributionShift(deltas),
+		A:              HeatmapSliceSummary{Label: labelA, TotalEvents: gridA.totalEvents},
+		B:              HeatmapSliceSummary{Label: labelB, TotalEvents: gridB.totalEvents},
+		Regions:        summarizeRegions(deltas),
+		Cells:          deltas,
+		TotalCells:     len(deltas),
+	}
+	roundDeltas(result.Cells)
+	if n := int(clampPageSize(limit)); len(result.Cells) > n {
+		result.Cells = result.Cells[:n]
+	}
+	return result
+}
+
+// cohortMemberIDs lists the members of a cohort's group or team, at most
+// maxCohortMembers.
+func cohortMemberIDs(ctx context.Context, c *transport.Clients, cohort CohortInput) ([]string, error) {
+	var ids []string
+	var err error
+	if cohort.GroupID != "" {
+		ids, err = listAllGroupMemberIDs(ctx, c, cohort.GroupID)
+	} else {
+		ids, err = listAllTeamMemberIDs(ctx, c, cohort.TeamID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > maxCohortMembers {
+		name := cohort.Label
+		if name == "" {
+			name = cohort.GroupID + cohort.TeamID
+		}
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("cohort %q has %d members; at most %d can be queried", name, len(ids), maxCohortMembers))
+	}
+	return ids, nil
+}
+
+// queryCohortHeatmap answers a heatmap query filtered to a group's or
+// team's members with the sum of their USER_SPECIFIC heatmaps, which is
+// their TOTAL heatmap.
+func queryCohortHeatmap(ctx context.Context, c *transport.Clients, input QueryHeatmapDataInput, progress progressFunc) (*pidgrv1.QueryHeatmapDataResponse, error) {
+	if input.GroupID != "" && input.TeamID != "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("set group_id or team_id, not both"))
+	}
+	if input.UserID != "" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("user_id cannot be combined with group_id or team_id"))
+	}
+	if mode := strings.TrimPrefix(input.Mode, "HEATMAP_MODE_"); mode != "" && mode != "TOTAL" {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("group_id and team_id only support TOTAL mode"))
+	}
+	ids, err := cohortMemberIDs(ctx, c, CohortInput{GroupID: input.GroupID, TeamID: input.TeamID})
+	if err != nil {
+		return nil, err
+	}
+	resps, err := queryMemberHeatmaps(ctx, c, input, ids, progress)
+	if err != nil {
+		return nil, err
+	}
+	return mergeHeatmaps(resps), nil
+}
+
+// queryMemberHeatmaps runs base as a USER_SPECIFIC query for each user, in
+// the bulk worker pool. QueryHeatmapData has no group or team filter, so a
+// cohort is queried member by member.
+func queryMemberHeatmaps(ctx context.Context, c *transport.Clients, base QueryHeatmapDataInput, userIDs []string, progress progressFunc) ([]*pidgrv1.QueryHeatmapDataResponse, error) {
+	base.GroupID, base.TeamID = "", ""
+	base.Mode = "USER_SPECIFIC"
+	resps := make([]*pidgrv1.QueryHeatmapDataResponse, len(userIDs))
+	errs := forEach(ctx, len(userIDs), func(ctx context.Context, i int) error {
+		input := base
+		input.UserID = userIDs[i]
+		resp, err := c.Heatmaps.QueryHeatmapData(ctx, connect.NewRequest(buildHeatmapRequest(input)))
+		if err != nil {
+			return err
+		}
+		resps[i] = resp.Msg
+		return nil
+	}, nil, nil, progress)
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resps, nil
+}
+
+// mergeHeatmaps sums heatmaps queried over the same grid, adding the values
+// of points in the same cell.
+func mergeHeatmaps(resps []*pidgrv1.QueryHeatmapDataResponse) *pidgrv1.QueryHeatmapDataResponse {
+	merged := &pidgrv1.QueryHeatmapDataResponse{}
+	byCell := make(map[[2]float32]*pidgrv1.HeatmapDataPoint)
+	for _, resp := range resps {
+		if merged.ScreenshotUrl == "" {
+			merged.ScreenshotUrl = resp.GetScreenshotUrl()
+		}
+		for _, p := range resp.GetDataPoints() {
+			key := [2]float32{p.GetXPct(), p.GetYPct()}
+			if cell, ok := byCell[key]; ok {
+				cell.Value += p.GetValue()
+				continue
+			}
+			cell := proto.Clone(p).(*pidgrv1.HeatmapDataPoint)
+			byCell[key] = cell
+			merged.DataPoints = append(merged.DataPoints, cell)
+		}
+	}
+	return merged
+}
+
+// findScreenshot returns the screenshot for a screen, or nil if none exists.
+func findScreenshot(ctx context.Context, c *transport.Clients, screenName string) (*pidgrv1.ScreenScreenshot, error) {
+	resp, err := c.Heatmaps.ListScreenshots(ctx, connect.NewRequest(&pidgrv1.ListScreenshotsRequest{}))
+	if err != nil {
+		return nil, err
+	}
+	for _, shot := range resp.Msg.GetScreenshots() {
+		if shot.GetScreenName() == screenName {
+			return shot, nil
+		}
+	}
+	return nil, nil
+}
+
+// heatCells converts backend data points, which give cell centers, to render
+// cells anchored at their top-left corner and weighted by the point's value.
+func heatCells(points []*pidgrv1.HeatmapDataPoint, resolution float64) []heatCell {
+	out := make([]heatCell, len(points))
+	for i, p := range points {
+		out[i] = heatCell{
+			X:      max(0, float64(p.GetXPct())-resolution/2),
+			Y:      max(0, float64(p.GetYPct())-resolution/2),
+			Weight: float64(p.GetValue()),
+		}
+	}
+	return out
+}
+
+// totalTouches returns the number of touch events behind a heatmap. The
+// backend only reports per-user counts in TOTAL and MEDIAN mode; otherwise
+// the data point values are the counts.
+func totalTouches(resp *pidgrv1.QueryHeatmapDataResponse) int32 {
+	var total int32
+	if counts := resp.GetUserTouchCounts(); len(counts) > 0 {
+		for _, uc := range counts {
+			total += uc.GetCount()
+		}
+		return total
+	}
+	for _, p := range resp.GetDataPoints() {
+		total += int32(p.GetValue())
+	}
+	return total
+}
+
+// heatmapExportTemplate matches the URIs export_heatmap_data links to. The
+// filters travel in the URI, so reading an export re-runs its query and the
+// server keeps no state between the tool call and the read. The template
+// only matches query parameters in the order listed, which is the
+// alphabetical order url.Values.Encode writes them in.
+const heatmapExportTemplate = "pidgr://heatmaps/{screen_name}.{format}{?campaign_id,date_from,date_to,event_types,grid_resolution,group_id,mode,team_id,user_id}"
+
+const heatmapExportPrefix = "pidgr://heatmaps/"
+
+// heatmapExportFormat normalizes an export format, defaulting to CSV.
+func heatmapExportFormat(format string) (string, error) {
+	format = strings.ToLower(format)
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		return "", connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("format must be csv or json"))
+	}
+	return format, nil
+}
+
+// heatmapExportURI returns the resource URI of an export. The screen name
+// is query-escaped with %20 for spaces, since the template does not match a
+// literal "+" in the path.
+func heatmapExportURI(input ExportHeatmapDataInput) string {
+	q := url.Values{}
+	for key, value := range map[string]string{
+		"campaign_id": input.CampaignID,
+		"date_from":   input.DateFrom,
+		"date_to":     input.DateTo,
+		"event_types": strings.Join(input.EventTypes, ","),
+		"group_id":    input.GroupID,
+		"mode":        input.Mode,
+		"team_id":     input.TeamID,
+		"user_id":     input.UserID,
+	} {
+		if value != "" {
+			q.Set(key, value)
+		}
+	}
+	if input.GridResolution != 0 {
+		q.Set("grid_resolution", strconv.FormatFloat(float64(input.GridResolution), 'f', -1, 32))
+	}
+	uri := heatmapExportPrefix + strings.ReplaceAll(url.QueryEscape(input.ScreenName), "+", "%20") + "." + input.Format
+	if len(q) > 0 {
+		uri += "?" + q.Encode()
+	}
+	return uri
+}
+
+// parseHeatmapExportURI reverses heatmapExportURI.
+func parseHeatmapExportURI(uri string) (ExportHeatmapDataInput, bool) {
+	rest, ok := strings.CutPrefix(uri, heatmapExportPrefix)
+	if !ok {
+		return ExportHeatmapDataInput{}, false
+	}
+	path, rawQuery, _ := strings.Cut(rest, "?")
+	dot := strings.LastIndexByte(path, '.')
+	if dot <= 0 {
+		return ExportHeatmapDataInput{}, false
+	}
+	screenName, err := url.PathUnescape(path[:dot])
+	if err != nil {
+		return ExportHeatmapDataInput{}, false
+	}
+	format, err := heatmapExportFormat(path[dot+1:])
+	if err != nil {
+		return ExportHeatmapDataInput{}, false
+	}
+	q, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return ExportHeatmapDataInput{}, false
+	}
+	input := ExportHeatmapDataInput{
+		ScreenName: screenName,
+		DateFrom:   q.Get("date_from"),
+		DateTo:     q.Get("date_to"),
+		CampaignID: q.Get("campaign_id"),
+		UserID:     q.Get("user_id"),
+		GroupID:    q.Get("group_id"),
+		TeamID:     q.Get("team_id"),
+		Mode:       q.Get("mode"),
+		Format:     format,
+	}
+	if v := q.Get("event_types"); v != "" {
+		input.EventTypes = strings.Split(v, ",")
+	}
+	if v := q.Get("grid_resolution"); v != "" {
+		resolution, err := strconv.ParseFloat(v, 32)
+		if err != nil {
+			return ExportHeatmapDataInput{}, false
+		}
+		input.GridResolution = float32(resolution)
+	}
+	return input, true
+}
+
+// exportHeatmap runs an export's query and renders the grid in
+// input.Format, which must already be normalized by heatmapExportFormat.
+func exportHeatmap(ctx context.Context, c *transport.Clients, input ExportHeatmapDataInput, progress progressFunc) (data, mimeType, summary string, err error) {
+	resp, err := queryHeatmap(ctx, c, QueryHeatmapDataInput{
+		ScreenName:     input.ScreenName,
+		DateFrom:       input.DateFrom,
+		DateTo:         input.DateTo,
+		CampaignID:     input.CampaignID,
+		UserID:         input.UserID,
+		GroupID:        input.GroupID,
+		TeamID:         input.TeamID,
+		GridResolution: input.GridResolution,
+		Mode:           input.Mode,
+		EventTypes:     input.EventTypes,
+	}, progress)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	cells := resp.GetDataPoints()
+	totalEvents := totalTouches(resp)
+	summary = fmt.Sprintf("Exported %d heatmap cells (%d events) for %s", len(cells), totalEvents, input.ScreenName)
+	if input.Format == "json" {
+		resolution := input.GridResolution
+		if resolution == 0 {
+			resolution = defaultGridResolution
+		}
+		export := HeatmapExport{
+			ScreenName:     input.ScreenName,
+			GridResolution: resolution,
+			TotalEvents:    totalEvents,
+			Cells:          make([]HeatmapExportCell, len(cells)),
+		}
+		for i, cell := range cells {
+			export.Cells[i] = HeatmapExportCell{X: cell.GetXPct(), Y: cell.GetYPct(), Value: cell.GetValue()}
+		}
+		b, err := json.Marshal(export)
+		if err != nil {
+			return "", "", "", err
+		}
+		return string(b), "application/json", summary, nil
+	}
+	rows := make([][]string, len(cells))
+	for i, cell := range cells {
+		rows[i] = []string{
+			strconv.FormatFloat(float64(cell.GetXPct()), 'f', -1, 32),
+			strconv.FormatFloat(float64(cell.GetYPct()), 'f', -1, 32),
+			strconv.FormatFloat(float64(cell.GetValue()), 'f', -1, 32),
+		}
+	}
+	if data, err = writeCSV([]string{"x", "y", "value"}, rows); err != nil {
+		return "", "", "", err
+	}
+	return data, "text/csv", summary, nil
+}
 
 // ── Registration ────────────────────────────────────────────────────────────
 
 func registerHeatmapTools(s *mcp.Server, c *transport.Clients) {
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "query_heatmap_data",
-		Description: "Query aggregated touch data for heatmap rendering. Use list_screenshots to find available screen names, list_campaigns for campaign UUIDs, and list_users for user UUIDs.",
+		Description: "Query aggregated touch data for heatmap rendering. Set group_id or team_id to count only that cohort's touches; the backend has no such filter, so each member (at most 200) is queried and the results summed. Use list_screenshots to find available screen names, list_campaigns for campaign UUIDs, list_users for user UUIDs, and list_groups or list_teams for cohort UUIDs.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input QueryHeatmapDataInput) (*mcp.CallToolResult, any, error) {
-		protoReq := &pidgrv1.QueryHeatmapDataRequest{
-			ScreenName:     input.ScreenName,
-			CampaignId:     input.CampaignID,
-			UserId:         input.UserID,
-			GridResolution: input.GridResolution,
+		resp, err := queryHeatmap(ctx, c, input, toolProgress(ctx, req))
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
 		}
+		r, err := convert.ProtoResult(ctx, resp)
+		return r, nil, err
+	})
 
-		if input.DateFrom != "" {
-			if t, err := time.Parse(time.RFC3339, input.DateFrom); err == nil {
-				protoReq.DateFrom = timestamppb.New(t)
-			}
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "list_screenshots",
+		Description: "List available screen screenshots for heatmap backgrounds.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListScreenshotsInput) (*mcp.CallToolResult, any, error) {
+		resp, err := c.Heatmaps.ListScreenshots(ctx, connect.NewRequest(&pidgrv1.ListScreenshotsRequest{}))
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
 		}
-		if input.DateTo != "" {
-			if t, err := time.Parse(time.RFC3339, input.DateTo); err == nil {
-				protoReq.DateTo = timestamppb.New(t)
-			}
+		r, err := convert.ProtoResult(ctx, resp.Msg)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "get_screenshot",
+		Description: "Fetch a screen's screenshot as an image. Use list_screenshots to find screen names.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input GetScreenshotInput) (*mcp.CallToolResult, any, error) {
+		shot, err := findScreenshot(ctx, c, input.ScreenName)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		if shot == nil || shot.GetUrl() == "" {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeNotFound, fmt.Errorf("no screenshot for screen %q", input.ScreenName)))
+			return r, nil, nil
+		}
+		data, mimeType, err := fetchImageBytes(ctx, c.FetchClient(), shot.GetUrl())
+		if err != nil {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeUnavailable, err))
+			return r, nil, nil
 		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("Screenshot of %s (app version %s).",
+					shot.GetScreenName(), shot.GetAppVersion())},
+				&mcp.ImageContent{Data: data, MIMEType: mimeType},
+			},
+		}, nil, nil
+	})
 
-		if input.Mode != "" {
-			if v, ok := pidgrv1.HeatmapMode_value[input.Mode]; ok {
-				protoReq.Mode = pidgrv1.HeatmapMode(v)
-			} else if v, ok := pidgrv1.HeatmapMode_value["HEATMAP_MODE_"+input.Mode]; ok {
-				protoReq.Mode = pidgrv1.HeatmapMode(v)
-			}
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "render_heatmap",
+		Description: "Render a touch heatmap as a PNG image, overlaid on the screen's screenshot when one is available. Takes the same filters as query_heatmap_data. Use list_screenshots to find screen names.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input RenderHeatmapInput) (*mcp.CallToolResult, any, error) {
+		grid, err := queryHeatCells(ctx, c, QueryHeatmapDataInput(input), toolProgress(ctx, req))
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
 		}
 
-		for _, et := range input.EventTypes {
-			if v, ok := pidgrv1.TouchEventType_value[et]; ok {
-				protoReq.EventTypes = append(protoReq.EventTypes, pidgrv1.TouchEventType(v))
-			} else if v, ok := pidgrv1.TouchEventType_value["TOUCH_EVENT_TYPE_"+et]; ok {
-				protoReq.EventTypes = append(protoReq.EventTypes, pidgrv1.TouchEventType(v))
+		background := "no screenshot available; rendered on a blank canvas"
+		var bg image.Image
+		if grid.screenshotURL != "" {
+			if bg, err = fetchImage(ctx, c.FetchClient(), grid.screenshotURL); err != nil {
+				bg = nil
+				background = "screenshot could not be loaded; rendered on a blank canvas"
+			} else {
+				background = "overlaid on the screen's screenshot"
 			}
 		}
 
-		resp, err := c.Heatmaps.QueryHeatmapData(ctx, connect.NewRequest(protoReq))
+		data, err := encodePNG(renderHeatmap(bg, grid.cells, grid.resolution))
+		if err != nil {
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: fmt.Sprintf("Heatmap for %s: %d events in %d cells, %s.",
+					input.ScreenName, grid.totalEvents, len(grid.cells), background)},
+				&mcp.ImageContent{Data: data, MIMEType: "image/png"},
+			},
+		}, nil, nil
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "compare_heatmaps",
+		Description: "Compare two heatmaps of the same screen (e.g. two campaigns or two date ranges). Each heatmap is normalized to its own total, and the result gives an overall shift score from 0 (identical) to 1 (disjoint), per-cell share changes, and the screen regions with the biggest engagement shifts. Use list_screenshots to find screen names.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input CompareHeatmapsInput) (*mcp.CallToolResult, any, error) {
+		resolution := input.GridResolution
+		if resolution == 0 {
+			resolution = defaultGridResolution
+		}
+		query := func(side HeatmapSliceInput) QueryHeatmapDataInput {
+			return QueryHeatmapDataInput{
+				ScreenName:     input.ScreenName,
+				CampaignID:     side.CampaignID,
+				DateFrom:       side.DateFrom,
+				DateTo:         side.DateTo,
+				GridResolution: resolution,
+				EventTypes:     input.EventTypes,
+			}
+		}
+		result, err := compareHeatmapQueries(ctx, c, input.A.Label, query(input.A), input.B.Label, query(input.B), input.Limit)
 		if err != nil {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.JSONResult(result)
 		return r, nil, err
 	})
 
 	mcp.AddTool(s, &mcp.Tool{
-		Name:        "list_screenshots",
-		Description: "List available screen screenshots for heatmap backgrounds.",
-	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListScreenshotsInput) (*mcp.CallToolResult, any, error) {
-		resp, err := c.Heatmaps.ListScreenshots(ctx, connect.NewRequest(&pidgrv1.ListScreenshotsRequest{}))
+		Name:        "export_heatmap_data",
+		Description: "Export the full-resolution heatmap grid as a CSV or JSON resource for offline analysis. Returns a summary and a link to a pidgr://heatmaps/ resource; read it with resources/read, which re-runs the query, so the grid never enters the conversation inline. Use this instead of query_heatmap_data when the grid is too large to read inline. Takes the same filters as query_heatmap_data.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ExportHeatmapDataInput) (*mcp.CallToolResult, any, error) {
+		format, err := heatmapExportFormat(input.Format)
 		if err != nil {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		input.Format = format
+		data, mimeType, summary, err := exportHeatmap(ctx, c, input, toolProgress(ctx, req))
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		return convert.ResourceLinkResult(summary, heatmapExportURI(input), input.ScreenName+"."+format, mimeType, len(data)), nil, nil
+	})
+
+	s.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "heatmap_export",
+		URITemplate: heatmapExportTemplate,
+		Description: "A heatmap grid linked from export_heatmap_data, as CSV or JSON. The filters are part of the URI, so reading it re-runs the query.",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		uri := req.Params.URI
+		input, ok := parseHeatmapExportURI(uri)
+		if !ok || !toolAllowed("export_heatmap_data") {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+		data, mimeType, _, err := exportHeatmap(ctx, c, input, nil)
+		if err != nil {
+			return nil, errors.New(convert.ErrorMessage(err))
+		}
+		return convert.ReadResult(uri, mimeType, data), nil
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "compare_cohorts",
+		Description: "Compare how two cohorts (e.g. managers vs individual contributors) engage with the same screen. Each cohort is a group or team of at most 200 members, whose per-user heatmaps are summed; heatmaps are normalized to each cohort's own total, and the result lists per-cell share changes and the regions with the biggest differences. Use list_groups or list_teams to find cohort UUIDs and list_screenshots for screen names.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input CompareCohortsInput) (*mcp.CallToolResult, any, error) {
+		for _, cohort := range []CohortInput{input.A, input.B} {
+			if (cohort.GroupID == "") == (cohort.TeamID == "") {
+				r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("each cohort needs exactly one of group_id or team_id")))
+				return r, nil, nil
+			}
+		}
+		resolution := input.GridResolution
+		if resolution == 0 {
+			resolution = defaultGridResolution
+		}
+		base := QueryHeatmapDataInput{
+			ScreenName:     input.ScreenName,
+			CampaignID:     input.CampaignID,
+			DateFrom:       input.DateFrom,
+			DateTo:         input.DateTo,
+			GridResolution: resolution,
+			EventTypes:     input.EventTypes,
+		}
+		idsA, err := cohortMemberIDs(ctx, c, input.A)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		idsB, err := cohortMemberIDs(ctx, c, input.B)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		// Both cohorts' members share one worker pool and progress count.
+		resps, err := queryMemberHeatmaps(ctx, c, base, append(idsA, idsB...), toolProgress(ctx, req))
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		gridA := newHeatGrid(mergeHeatmaps(resps[:len(idsA)]), resolution)
+		gridB := newHeatGrid(mergeHeatmaps(resps[len(idsA):]), resolution)
+		r, err := convert.JSONResult(compareHeatGrids(input.ScreenName, input.A.Label, gridA, input.B.Label, gridB, input.Limit))
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "detect_heatmap_anomalies",
+		Description: "Flag screens whose recent touch distribution has shifted significantly from their historical baseline, e.g. to spot broken or moved UI after an app release. Each screen gets a shift score from 0 (unchanged) to 1 (completely different) plus the regions that changed most, and a volume drop from 0 (daily events held or grew) to 1 (stopped entirely). Screens whose volume collapsed are flagged even when too few recent events remain to score the shift. Use list_screenshots to find screen names.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input DetectHeatmapAnomaliesInput) (*mcp.CallToolResult, any, error) {
+		recentDays, baselineDays := input.RecentDays, input.BaselineDays
+		if recentDays <= 0 {
+			recentDays = defaultAnomalyRecentDays
+		}
+		if baselineDays <= 0 {
+			baselineDays = defaultAnomalyBaselineDays
+		}
+		threshold := input.Threshold
+		if threshold <= 0 {
+			threshold = defaultAnomalyThreshold
+		}
+		dropThreshold := input.VolumeDrop
+		if dropThreshold <= 0 {
+			dropThreshold = defaultAnomalyVolumeDrop
+		}
+		minEvents := input.MinEvents
+		if minEvents <= 0 {
+			minEvents = defaultAnomalyMinEvents
+		}
+		resolution := input.GridResolution
+		if resolution == 0 {
+			resolution = defaultAnomalyResolution
+		}
+		now := time.Now().UTC()
+		recentFrom := now.AddDate(0, 0, -recentDays)
+		baselineFrom := recentFrom.AddDate(0, 0, -baselineDays)
+
+		screens := input.ScreenNames
+		if len(screens) == 0 {
+			resp, err := c.Heatmaps.ListScreenshots(ctx, connect.NewRequest(&pidgrv1.ListScreenshotsRequest{}))
+			if err != nil {
+				r, _ := convert.ErrorResult(err)
+				return r, nil, nil
+			}
+			seen := make(map[string]bool)
+			for _, shot := range resp.Msg.GetScreenshots() {
+				if name := shot.GetScreenName(); !seen[name] {
+					seen[name] = true
+					screens = append(screens, name)
+				}
+			}
+		}
+		if len(screens) > maxAnomalyScreens {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("at most %d screens can be checked at once; pass screen_names", maxAnomalyScreens)))
+			return r, nil, nil
+		}
+
+		report := HeatmapAnomalyReport{
+			RecentFrom:   recentFrom.Format(time.RFC3339),
+			BaselineFrom: baselineFrom.Format(time.RFC3339),
+			Threshold:    threshold,
+			VolumeDrop:   dropThreshold,
+			Screens:      make([]ScreenAnomaly, 0, len(screens)),
+		}
+		progress := toolProgress(ctx, req)
+		for i, screen := range screens {
+			query := func(from, to time.Time) QueryHeatmapDataInput {
+				return QueryHeatmapDataInput{
+					ScreenName:     screen,
+					DateFrom:       from.Format(time.RFC3339),
+					DateTo:         to.Format(time.RFC3339),
+					GridResolution: resolution,
+					EventTypes:     input.EventTypes,
+				}
+			}
+			comparison, err := compareHeatmapQueries(ctx, c, "baseline", query(baselineFrom, recentFrom), "recent", query(recentFrom, now), 0)
+			if err != nil {
+				r, _ := convert.ErrorResult(err)
+				return r, nil, nil
+			}
+			anomaly := ScreenAnomaly{
+				ScreenName:     screen,
+				RecentEvents:   comparison.B.TotalEvents,
+				BaselineEvents: comparison.A.TotalEvents,
+			}
+			// A screen whose recent volume collapsed may have too few
+			// recent events to score its shift, but the collapse itself is
+			// the anomaly, so only a thin baseline skips a screen.
+			if anomaly.BaselineEvents < minEvents {
+				anomaly.Skipped = fmt.Sprintf("fewer than %d events in the baseline window", minEvents)
+			} else {
+				anomaly.VolumeDrop = volumeDrop(anomaly.BaselineEvents, baselineDays, anomaly.RecentEvents, recentDays)
+				if anomaly.RecentEvents >= minEvents {
+					anomaly.Shift = comparison.Shift
+					anomaly.TopRegions = comparison.Regions[:min(anomalyTopRegions, len(comparison.Regions))]
+				}
+				anomaly.Anomalous = anomaly.Shift >= threshold || anomaly.VolumeDrop >= dropThreshold
+				if !anomaly.Anomalous && anomaly.RecentEvents < minEvents {
+					anomaly.Skipped = fmt.Sprintf("fewer than %d events in the recent window", minEvents)
+				}
+			}
+			if anomaly.Anomalous {
+				report.Flagged++
+			}
+			report.Screens = append(report.Screens, anomaly)
+			progress(i+1, len(screens))
+		}
+		sort.SliceStable(report.Screens, func(i, j int) bool {
+			a, b := report.Screens[i], report.Screens[j]
+			if a.Anomalous != b.Anomalous {
+				return a.Anomalous
+			}
+			return a.Shift > b.Shift
+		})
+		r, err := convert.JSONResult(report)
 		return r, nil, err
 	})
 }
@@ -0,0 +1,160 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"math"
+	"sort"
+)
+
+// HeatmapDelta is the change in one grid cell's share of all events between
+// two heatmaps. Shares are fractions of each heatmap's total weight, so
+// heatmaps with different traffic volumes can be compared.
+type HeatmapDelta struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	ShareA float64 `json:"shareA"`
+	ShareB float64 `json:"shareB"`
+	Delta  float64 `json:"delta"`
+	Region string  `json:"region"`
+}
+
+type cellKey struct{ col, row int }
+
+// cellShares normalizes cells to fractions of their total weight, keyed by
+// grid position.
+func cellShares(cells []heatCell, resolution float64) map[cellKey]float64 {
+	total := 0.0
+	for _, c := range cells {
+		total += c.Weight
+	}
+	shares := make(map[cellKey]float64, len(cells))
+	if total == 0 {
+		return shares
+	}
+	for _, c := range cells {
+		k := cellKey{int(math.Round(c.X / resolution)), int(math.Round(c.Y / resolution))}
+		shares[k] += c.Weight / total
+	}
+	return shares
+}
+
+// compareHeatCells returns the per-cell share change from a to b for every
+// cell present in either, ordered by largest absolute change first. Shares
+// and deltas are exact, so sums over them do not accumulate rounding error;
+// roundDeltas rounds them for output.
+func compareHeatCells(a, b []heatCell, resolution float64) []HeatmapDelta {
+	sharesA, sharesB := cellShares(a, resolution), cellShares(b, resolution)
+	keys := make(map[cellKey]bool, len(sharesA)+len(sharesB))
+	for k := range sharesA {
+		keys[k] = true
+	}
+	for k := range sharesB {
+		keys[k] = true
+	}
+
+	deltas := make([]HeatmapDelta, 0, len(keys))
+	for k := range keys {
+		x, y := float64(k.col)*resolution, float64(k.row)*resolution
+		deltas = append(deltas, HeatmapDelta{
+			X:      roundTo(x, 4),
+			Y:      roundTo(y, 4),
+			ShareA: sharesA[k],
+			ShareB: sharesB[k],
+			Delta:  sharesB[k] - sharesA[k],
+			Region: screenRegion(x+resolution/2, y+resolution/2),
+		})
+	}
+	sort.Slice(deltas, func(i, j int) bool {
+		di, dj := math.Abs(deltas[i].Delta), math.Abs(deltas[j].Delta)
+		if di != dj {
+			return di > dj
+		}
+		if deltas[i].Y != deltas[j].Y {
+			return deltas[i].Y < deltas[j].Y
+		}
+		return deltas[i].X < deltas[j].X
+	})
+	return deltas
+}
+
+// roundDeltas rounds the shares and deltas of compareHeatCells output to
+// four decimal places in place.
+func roundDeltas(deltas []HeatmapDelta) {
+	for i := range deltas {
+		d := &deltas[i]
+		d.ShareA, d.ShareB, d.Delta = roundTo(d.ShareA, 4), roundTo(d.ShareB, 4), roundTo(d.Delta, 4)
+	}
+}
+
+// RegionChange is the combined share change within one area of the screen.
+type RegionChange struct {
+	Region string  `json:"region"`
+	Delta  float64 `json:"delta"`
+}
+
+// summarizeRegions totals deltas per screen region, ordered by largest
+// absolute change first.
+func summarizeRegions(deltas []HeatmapDelta) []RegionChange {
+	totals := make(map[string]float64)
+	for _, d := range deltas {
+		totals[d.Region] += d.Delta
+	}
+	out := make([]RegionChange, 0, len(totals))
+	for region, delta := range totals {
+		out = append(out, RegionChange{Region: region, Delta: roundTo(delta, 4)})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		di, dj := math.Abs(out[i].Delta), math.Abs(out[j].Delta)
+		if di != dj {
+			return di > dj
+		}
+		return out[i].Region < out[j].Region
+	})
+	return out
+}
+
+// screenRegion names the ninth of the screen containing the normalized point
+// (x, y), e.g. "top-left" or "middle-center".
+func screenRegion(x, y float64) string {
+	third := func(v float64, names [3]string) string {
+		switch {
+		case v < 1.0/3:
+			return names[0]
+		case v < 2.0/3:
+			return names[1]
+		default:
+			return names[2]
+		}
+	}
+	return third(y, [3]string{"top", "middle", "bottom"}) + "-" + third(x, [3]string{"left", "center", "right"})
+}
+
+// roundTo rounds v to the given number of decimal places.
+func roundTo(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
+
+// distributionShift is the total variation distance between the two heatmaps
+// behind deltas: 0 for identical distributions, 1 for disjoint ones.
+func distributionShift(deltas []HeatmapDelta) float64 {
+	total := 0.0
+	for _, d := range deltas {
+		total += math.Abs(d.Delta)
+	}
+	return roundTo(total/2, 4)
+}
+
+// volumeDrop is the fraction by which the recent window's daily event rate
+// fell below the baseline window's: 0 when it held or grew, 1 when events
+// stopped entirely.
+func volumeDrop(baselineEvents int32, baselineDays int, recentEvents int32, recentDays int) float64 {
+	if baselineEvents <= 0 {
+		return 0
+	}
+	baselineRate := float64(baselineEvents) / float64(baselineDays)
+	recentRate := float64(recentEvents) / float64(recentDays)
+	return roundTo(max(0, 1-recentRate/baselineRate), 4)
+}
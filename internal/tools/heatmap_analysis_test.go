@@ -0,0 +1,104 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareHeatCells(t *testing.T) {
+	a := []heatCell{{X: 0, Y: 0, Weight: 30}, {X: 0.5, Y: 0.9, Weight: 10}}
+	b := []heatCell{{X: 0, Y: 0, Weight: 5}, {X: 0.5, Y: 0.9, Weight: 5}, {X: 0.9, Y: 0.5, Weight: 10}}
+
+	got := compareHeatCells(a, b, 0.1)
+
+	if len(got) != 3 {
+		t.Fatalf("got %d deltas, want 3: %+v", len(got), got)
+	}
+	first := got[0]
+	if first.X != 0 || first.Y != 0 || first.ShareA != 0.75 || first.ShareB != 0.25 || first.Delta != -0.5 {
+		t.Errorf("largest change = %+v, want top-left cell dropping from 0.75 to 0.25", first)
+	}
+	if first.Region != "top-left" {
+		t.Errorf("region = %q, want top-left", first.Region)
+	}
+	if got[1].Region != "middle-right" || got[1].Delta != 0.5 {
+		t.Errorf("second change = %+v, want new middle-right cell with +0.5", got[1])
+	}
+}
+
+func TestCompareHeatCellsEmpty(t *testing.T) {
+	got := compareHeatCells(nil, []heatCell{{X: 0.2, Y: 0.2, Weight: 4}}, 0.1)
+	if len(got) != 1 || got[0].ShareA != 0 || got[0].ShareB != 1 {
+		t.Errorf("compareHeatCells with empty baseline = %+v", got)
+	}
+}
+
+func TestSummarizeRegions(t *testing.T) {
+	deltas := []HeatmapDelta{
+		{Region: "top-left", Delta: -0.3},
+		{Region: "top-left", Delta: -0.2},
+		{Region: "bottom-center", Delta: 0.1},
+	}
+	want := []RegionChange{{Region: "top-left", Delta: -0.5}, {Region: "bottom-center", Delta: 0.1}}
+	if got := summarizeRegions(deltas); !reflect.DeepEqual(got, want) {
+		t.Errorf("summarizeRegions = %+v, want %+v", got, want)
+	}
+}
+
+func TestScreenRegion(t *testing.T) {
+	tests := []struct {
+		x, y float64
+		want string
+	}{
+		{0.1, 0.1, "top-left"},
+		{0.5, 0.5, "middle-center"},
+		{0.9, 0.95, "bottom-right"},
+	}
+	for _, tt := range tests {
+		if got := screenRegion(tt.x, tt.y); got != tt.want {
+			t.Errorf("screenRegion(%v, %v) = %q, want %q", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestDistributionShift(t *testing.T) {
+	same := []heatCell{{X: 0.1, Y: 0.1, Weight: 5}, {X: 0.5, Y: 0.5, Weight: 5}}
+	if got := distributionShift(compareHeatCells(same, same, 0.1)); got != 0 {
+		t.Errorf("identical shift = %v, want 0", got)
+	}
+	moved := []heatCell{{X: 0.9, Y: 0.9, Weight: 3}}
+	if got := distributionShift(compareHeatCells(same, moved, 0.1)); got != 1 {
+		t.Errorf("disjoint shift = %v, want 1", got)
+	}
+	half := []heatCell{{X: 0.1, Y: 0.1, Weight: 5}, {X: 0.9, Y: 0.9, Weight: 5}}
+	if got := distributionShift(compareHeatCells(same, half, 0.1)); got != 0.5 {
+		t.Errorf("half-moved shift = %v, want 0.5", got)
+	}
+	// Shares of a third would each round to 0.3333 and sum to 0.9999.
+	thirds := []heatCell{{X: 0.1, Y: 0.1, Weight: 1}, {X: 0.2, Y: 0.1, Weight: 1}, {X: 0.3, Y: 0.1, Weight: 1}}
+	others := []heatCell{{X: 0.1, Y: 0.9, Weight: 1}, {X: 0.2, Y: 0.9, Weight: 1}, {X: 0.3, Y: 0.9, Weight: 1}}
+	if got := distributionShift(compareHeatCells(thirds, others, 0.1)); got != 1 {
+		t.Errorf("disjoint thirds shift = %v, want 1", got)
+	}
+}
+
+func TestVolumeDrop(t *testing.T) {
+	tests := []struct {
+		baseline, recent int32
+		want             float64
+	}{
+		{baseline: 280, recent: 70, want: 0},
+		{baseline: 280, recent: 140, want: 0},
+		{baseline: 280, recent: 35, want: 0.5},
+		{baseline: 280, recent: 0, want: 1},
+		{baseline: 0, recent: 10, want: 0},
+	}
+	for _, tt := range tests {
+		if got := volumeDrop(tt.baseline, 28, tt.recent, 7); got != tt.want {
+			t.Errorf("volumeDrop(%d over 28 days, %d over 7) = %v, want %v", tt.baseline, tt.recent, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg" // screenshot backgrounds may be JPEG
+	"image/png"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+)
+
+const (
+	// Canvas size used when no screenshot is available (a typical phone
+	// viewport in points).
+	defaultCanvasWidth  = 390
+	defaultCanvasHeight = 844
+
+	// maxScreenshotBytes caps how much of a screenshot is downloaded.
+	maxScreenshotBytes = 10 << 20
+
+	// maxScreenshotPixels caps the decoded size of a screenshot. A small
+	// compressed image can declare a huge canvas, and rendering allocates 4
+	// bytes per pixel.
+	maxScreenshotPixels = 16 << 20
+
+	// heatmapMaxAlpha is the overlay opacity of the hottest cell.
+	heatmapMaxAlpha = 0.65
+)
+
+// heatCell is one grid cell in normalized screen coordinates: X and Y are the
+// cell's top-left corner in [0, 1) and Weight is its event count.
+type heatCell struct {
+	X, Y   float64
+	Weight float64
+}
+
+// fetchImageBytes downloads an image with client and returns its bytes and
+// MIME type.
+func fetchImageBytes(ctx context.Context, client *http.Client, url string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("fetch image: unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxScreenshotBytes+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > maxScreenshotBytes {
+		return nil, "", fmt.Errorf("fetch image: larger than %d bytes", maxScreenshotBytes)
+	}
+	mimeType := http.DetectContentType(data)
+	if !strings.HasPrefix(mimeType, "image/") {
+		return nil, "", fmt.Errorf("fetch image: unexpected content type %s", mimeType)
+	}
+	return data, mimeType, nil
+}
+
+// fetchImage downloads and decodes a PNG or JPEG image.
+func fetchImage(ctx context.Context, client *http.Client, url string) (image.Image, error) {
+	data, _, err := fetchImageBytes(ctx, client, url)
+	if err != nil {
+		return nil, err
+	}
+	return decodeImage(data)
+}
+
+// decodeImage decodes a PNG or JPEG image, refusing any whose dimensions
+// exceed maxScreenshotPixels before allocating its pixels.
+func decodeImage(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width > maxScreenshotPixels/cfg.Height {
+		return nil, fmt.Errorf("decode image: %dx%d exceeds %d pixels", cfg.Width, cfg.Height, maxScreenshotPixels)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// renderHeatmap draws cells over bg as a translucent color overlay, scaled so
+// the hottest cell is red. If bg is nil a white default-sized canvas is used.
+// resolution is the cell size as a fraction of the screen.
+func renderHeatmap(bg image.Image, cells []heatCell, resolution float64) *image.RGBA {
+	bounds := image.Rect(0, 0, defaultCanvasWidth, defaultCanvasHeight)
+	if bg != nil {
+		bounds = image.Rect(0, 0, bg.Bounds().Dx(), bg.Bounds().Dy())
+	}
+	canvas := image.NewRGBA(bounds)
+	if bg != nil {
+		draw.Draw(canvas, bounds, bg, bg.Bounds().Min, draw.Src)
+	} else {
+		draw.Draw(canvas, bounds, image.White, image.Point{}, draw.Src)
+	}
+
+	maxWeight := 0.0
+	for _, c := range cells {
+		maxWeight = math.Max(maxWeight, c.Weight)
+	}
+	if maxWeight == 0 || resolution <= 0 {
+		return canvas
+	}
+
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+	for _, c := range cells {
+		if c.Weight <= 0 {
+			continue
+		}
+		t := c.Weight / maxWeight
+		rect := image.Rect(
+			int(math.Floor(c.X*w)), int(math.Floor(c.Y*h)),
+			int(math.Ceil((c.X+resolution)*w)), int(math.Ceil((c.Y+resolution)*h)),
+		).Intersect(bounds)
+		overlay := image.NewUniform(heatColor(t))
+		mask := image.NewUniform(color.Alpha{A: uint8(255 * heatmapMaxAlpha * (0.3 + 0.7*t))})
+		draw.DrawMask(canvas, rect, overlay, image.Point{}, mask, image.Point{}, draw.Over)
+	}
+	return canvas
+}
+
+// heatColor maps t in [0, 1] onto a blue → green → yellow → red ramp.
+func heatColor(t float64) color.RGBA {
+	t = math.Max(0, math.Min(1, t))
+	var r, g, b float64
+	switch {
+	case t < 1.0/3:
+		u := t * 3
+		r, g, b = 0, u, 1-u
+	case t < 2.0/3:
+		u := (t - 1.0/3) * 3
+		r, g, b = u, 1, 0
+	default:
+		u := (t - 2.0/3) * 3
+		r, g, b = 1, 1-u, 0
+	}
+	return color.RGBA{R: uint8(r * 255), G: uint8(g * 255), B: uint8(b * 255), A: 255}
+}
+
+// encodePNG renders img as PNG bytes.
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pidgrv1 "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1"
+)
+
+func TestRenderHeatmapDefaultCanvas(t *testing.T) {
+	img := renderHeatmap(nil, []heatCell{{X: 0, Y: 0, Weight: 10}, {X: 0.5, Y: 0.5, Weight: 1}}, 0.1)
+
+	if got := img.Bounds().Size(); got != image.Pt(defaultCanvasWidth, defaultCanvasHeight) {
+		t.Fatalf("canvas size = %v", got)
+	}
+	hot := img.RGBAAt(5, 5)
+	if hot.R <= hot.B || hot == (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("hottest cell should be tinted red, got %v", hot)
+	}
+	cold := img.RGBAAt(200, 450)
+	if cold.B <= cold.R {
+		t.Errorf("coldest cell should be tinted blue, got %v", cold)
+	}
+	if untouched := img.RGBAAt(380, 10); untouched != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("area without events should stay white, got %v", untouched)
+	}
+}
+
+func TestRenderHeatmapUsesBackgroundSize(t *testing.T) {
+	bg := image.NewRGBA(image.Rect(0, 0, 100, 200))
+	img := renderHeatmap(bg, nil, 0.02)
+	if got := img.Bounds().Size(); got != image.Pt(100, 200) {
+		t.Errorf("canvas size = %v, want 100x200", got)
+	}
+}
+
+func TestHeatColorRamp(t *testing.T) {
+	if c := heatColor(0); c.B != 255 || c.R != 0 {
+		t.Errorf("heatColor(0) = %v, want blue", c)
+	}
+	if c := heatColor(1); c.R != 255 || c.G != 0 {
+		t.Errorf("heatColor(1) = %v, want red", c)
+	}
+}
+
+func TestFetchImage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 3, 4))); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.png" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+
+	img, err := fetchImage(context.Background(), srv.Client(), srv.URL+"/shot.png")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := img.Bounds().Size(); got != image.Pt(3, 4) {
+		t.Errorf("image size = %v, want 3x4", got)
+	}
+	if _, err := fetchImage(context.Background(), srv.Client(), srv.URL+"/missing.png"); err == nil {
+		t.Error("expected error for 404")
+	}
+	if _, mimeType, err := fetchImageBytes(context.Background(), srv.Client(), srv.URL+"/shot.png"); err != nil || mimeType != "image/png" {
+		t.Errorf("fetchImageBytes MIME type = %q, %v; want image/png", mimeType, err)
+	}
+}
+
+// pngHeader returns the start of a PNG declaring a width x height RGBA
+// canvas: enough for image.DecodeConfig, with no pixel data.
+func pngHeader(width, height uint32) []byte {
+	ihdr := []byte("IHDR")
+	ihdr = binary.BigEndian.AppendUint32(ihdr, width)
+	ihdr = binary.BigEndian.AppendUint32(ihdr, height)
+	ihdr = append(ihdr, 8, 6, 0, 0, 0)
+	data := []byte("\x89PNG\r\n\x1a\n")
+	data = binary.BigEndian.AppendUint32(data, uint32(len(ihdr)-4))
+	data = append(data, ihdr...)
+	return binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(ihdr))
+}
+
+func TestDecodeImageRejectsHugeCanvas(t *testing.T) {
+	for _, size := range [][2]uint32{{100000, 100000}, {1 << 30, 20}, {20, 1 << 30}} {
+		if _, err := decodeImage(pngHeader(size[0], size[1])); err == nil || !strings.Contains(err.Error(), "exceeds") {
+			t.Errorf("%dx%d: expected the size to be refused, got %v", size[0], size[1], err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1290, 2796))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decodeImage(buf.Bytes()); err != nil {
+		t.Errorf("expected a phone-sized screenshot to decode, got %v", err)
+	}
+}
+
+func TestHeatCells(t *testing.T) {
+	got := heatCells([]*pidgrv1.HeatmapDataPoint{
+		{XPct: 0.25, YPct: 0.75, Value: 4},
+		{XPct: 0.01, YPct: 0.01, Value: 1},
+	}, 0.1)
+	want := []heatCell{{X: 0.2, Y: 0.7, Weight: 4}, {X: 0, Y: 0, Weight: 1}}
+	if len(got) != len(want) {
+		t.Fatalf("heatCells returned %d cells, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if math.Abs(got[i].X-want[i].X) > 1e-6 || math.Abs(got[i].Y-want[i].Y) > 1e-6 || got[i].Weight != want[i].Weight {
+			t.Errorf("cell %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestTotalTouches(t *testing.T) {
+	resp := &pidgrv1.QueryHeatmapDataResponse{
+		DataPoints:      []*pidgrv1.HeatmapDataPoint{{Value: 2}, {Value: 3}},
+		UserTouchCounts: []*pidgrv1.UserTouchCount{{Count: 7}, {Count: 4}},
+	}
+	if got := totalTouches(resp); got != 11 {
+		t.Errorf("totalTouches with user counts = %d, want 11", got)
+	}
+	resp.UserTouchCounts = nil
+	if got := totalTouches(resp); got != 5 {
+		t.Errorf("totalTouches without user counts = %d, want 5", got)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/pidgr/pidgr-mcp/internal/transport"
+	pidgrv1 "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1"
+	"github.com/pidgr/pidgr-proto/gen/go/pidgr/v1/pidgrv1connect"
+)
+
+// fakeHeatmapService answers QueryHeatmapData with the points registered
+// for the request's user, or for its campaign when no user is set.
+type fakeHeatmapService struct {
+	pidgrv1connect.UnimplementedHeatmapServiceHandler
+	points map[string][]*pidgrv1.HeatmapDataPoint
+}
+
+func (f *fakeHeatmapService) QueryHeatmapData(_ context.Context, req *connect.Request[pidgrv1.QueryHeatmapDataRequest]) (*connect.Response[pidgrv1.QueryHeatmapDataResponse], error) {
+	key := req.Msg.GetCampaignId()
+	if req.Msg.GetUserId() != "" {
+		if req.Msg.GetMode() != pidgrv1.HeatmapMode_HEATMAP_MODE_USER_SPECIFIC {
+			return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("user_id requires USER_SPECIFIC mode"))
+		}
+		key = req.Msg.GetUserId()
+	}
+	return connect.NewResponse(&pidgrv1.QueryHeatmapDataResponse{
+		DataPoints: f.points[key],
+	}), nil
+}
+
+// fakeGroupService lists the members registered for each group.
+type fakeGroupService struct {
+	pidgrv1connect.UnimplementedGroupServiceHandler
+	members map[string][]string
+}
+
+func (f *fakeGroupService) ListGroupMembers(_ context.Context, req *connect.Request[pidgrv1.ListGroupMembersRequest]) (*connect.Response[pidgrv1.ListGroupMembersResponse], error) {
+	resp := &pidgrv1.ListGroupMembersResponse{}
+	for _, id := range f.members[req.Msg.GetGroupId()] {
+		resp.Users = append(resp.Users, &pidgrv1.User{Id: id})
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func newFakeHeatmapClients(t *testing.T, svc *fakeHeatmapService, groups *fakeGroupService) *transport.Clients {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.Handle(pidgrv1connect.NewHeatmapServiceHandler(svc))
+	if groups != nil {
+		mux.Handle(pidgrv1connect.NewGroupServiceHandler(groups))
+	}
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return &transport.Clients{
+		Heatmaps: pidgrv1connect.NewHeatmapServiceClient(srv.Client(), srv.URL),
+		Groups:   pidgrv1connect.NewGroupServiceClient(srv.Client(), srv.URL),
+	}
+}
+
+func TestCompareHeatmapQueries(t *testing.T) {
+	c := newFakeHeatmapClients(t, &fakeHeatmapService{points: map[string][]*pidgrv1.HeatmapDataPoint{
+		"a": {{XPct: 0.05, YPct: 0.05, Value: 3}, {XPct: 0.95, YPct: 0.95, Value: 1}},
+		"b": {{XPct: 0.95, YPct: 0.95, Value: 4}},
+	}}, nil)
+
+	result, err := compareHeatmapQueries(context.Background(), c,
+		"before", QueryHeatmapDataInput{ScreenName: "home", CampaignID: "a", GridResolution: 0.1},
+		"after", QueryHeatmapDataInput{ScreenName: "home", CampaignID: "b", GridResolution: 0.1},
+		0)
+	if err != nil {
+		t.Fatalf("compareHeatmapQueries error: %v", err)
+	}
+	if result.A.TotalEvents != 4 || result.B.TotalEvents != 4 {
+		t.Errorf("totals = %d, %d, want 4, 4", result.A.TotalEvents, result.B.TotalEvents)
+	}
+	if result.TotalCells != 2 {
+		t.Fatalf("TotalCells = %d, want 2", result.TotalCells)
+	}
+	if result.Shift != 0.75 {
+		t.Errorf("Shift = %v, want 0.75", result.Shift)
+	}
+	if got := result.Cells[0]; got.Delta != -0.75 || got.X != 0 || got.Y != 0 {
+		t.Errorf("largest change = %+v, want -0.75 at (0, 0)", got)
+	}
+}
+
+func TestQueryHeatmapCohort(t *testing.T) {
+	c := newFakeHeatmapClients(t, &fakeHeatmapService{points: map[string][]*pidgrv1.HeatmapDataPoint{
+		"u1": {{XPct: 0.05, YPct: 0.05, Value: 2}},
+		"u2": {{XPct: 0.05, YPct: 0.05, Value: 1}, {XPct: 0.55, YPct: 0.55, Value: 3}},
+	}}, &fakeGroupService{members: map[string][]string{"g1": {"u1", "u2"}}})
+
+	var reported int
+	progress := func(done, total int) { reported = done }
+	grid, err := queryHeatCells(context.Background(), c, QueryHeatmapDataInput{ScreenName: "home", GroupID: "g1", GridResolution: 0.1}, progress)
+	if err != nil {
+		t.Fatalf("queryHeatCells error: %v", err)
+	}
+	if grid.totalEvents != 6 {
+		t.Errorf("totalEvents = %d, want 6", grid.totalEvents)
+	}
+	if len(grid.cells) != 2 {
+		t.Errorf("got %d cells, want the members' points merged into 2", len(grid.cells))
+	}
+	shares := cellShares(grid.cells, grid.resolution)
+	if got := shares[cellKey{0, 0}]; math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("share of top-left cell = %v, want 0.5", got)
+	}
+	if got := shares[cellKey{5, 5}]; math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("share of center cell = %v, want 0.5", got)
+	}
+	if reported != 2 {
+		t.Errorf("progress reported %d members, want 2", reported)
+	}
+
+	for _, input := range []QueryHeatmapDataInput{
+		{ScreenName: "home", GroupID: "g1", Mode: "MEDIAN"},
+		{ScreenName: "home", GroupID: "g1", UserID: "u1"},
+		{ScreenName: "home", GroupID: "g1", TeamID: "t1"},
+	} {
+		if _, err := queryHeatmap(context.Background(), c, input, nil); connect.CodeOf(err) != connect.CodeInvalidArgument {
+			t.Errorf("%+v: expected InvalidArgument, got %v", input, err)
+		}
+	}
+}
+
+func TestHeatmapExportURIRoundTrip(t *testing.T) {
+	for _, input := range []ExportHeatmapDataInput{
+		{ScreenName: "home", Format: "csv"},
+		{ScreenName: "Order Details/a+b.v2", Format: "json", DateFrom: "2026-01-01T00:00:00Z", GroupID: "g1", GridResolution: 0.05, EventTypes: []string{"TAP", "SCROLL"}},
+		{ScreenName: "home", Format: "csv", UserID: "u1", Mode: "USER_SPECIFIC", CampaignID: "c1", DateTo: "2026-02-01T00:00:00Z", TeamID: "t1"},
+	} {
+		uri := heatmapExportURI(input)
+		got, ok := parseHeatmapExportURI(uri)
+		if !ok {
+			t.Errorf("%s: not parsed", uri)
+			continue
+		}
+		if !reflect.DeepEqual(got, input) {
+			t.Errorf("%s: got %+v, want %+v", uri, got, input)
+		}
+	}
+
+	for _, uri := range []string{
+		"pidgr://heatmaps/home",
+		"pidgr://heatmaps/home.png",
+		"pidgr://heatmaps/home.csv?grid_resolution=fine",
+		"pidgr://results/home.csv",
+	} {
+		if _, ok := parseHeatmapExportURI(uri); ok {
+			t.Errorf("%s: parsed, want rejected", uri)
+		}
+	}
+}
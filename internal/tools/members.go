@@ -5,6 +5,8 @@ package tools
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"connectrpc.com/connect"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -35,13 +37,55 @@ type InviteUserInput struct {
 	Profile *UserProfileInput `json:"profile,omitempty" jsonschema:"Optional profile attributes to pre-fill"`
 }
 
+type BulkInviteUsersInput struct {
+	Invites []InviteUserInput `json:"invites" jsonschema:"Users to invite (max 1000). Each entry takes the same fields as invite_user"`
+}
+
 type GetUserInput struct {
-	UserID string `json:"user_id" jsonschema:"User UUID to retrieve"`
+	UserID       string   `json:"user_id" jsonschema:"User UUID to retrieve"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+}
+
+type GetUserByEmailInput struct {
+	Email        string   `json:"email" jsonschema:"Email address of the user (case-insensitive)"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+}
+
+type GetUserByEmployeeIDInput struct {
+	EmployeeID   string   `json:"employee_id" jsonschema:"Organization employee ID of the user"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
 }
 
 type ListUsersInput struct {
-	PageSize  int32  `json:"page_size,omitempty" jsonschema:"Max items per page"`
-	PageToken string `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
+	PageSize     int32    `json:"page_size,omitempty" jsonschema:"Max items per page"`
+	PageToken    string   `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
+	FetchAll     bool     `json:"fetch_all,omitempty" jsonschema:"Fetch every page server-side and return all items together, up to 20 pages; nextPageToken is set if more remain"`
+	RoleID       string   `json:"role_id,omitempty" jsonschema:"Only users with this role UUID"`
+	Status       string   `json:"status,omitempty" jsonschema:"Only users with this status: ACTIVE, DEACTIVATED, or PENDING (invited but not yet registered)"`
+	Department   string   `json:"department,omitempty" jsonschema:"Only users in this department"`
+	SortBy       string   `json:"sort_by,omitempty" jsonschema:"Sort field: NAME, EMAIL, or CREATED_AT (default: backend order)"`
+	SortDesc     bool     `json:"sort_desc,omitempty" jsonschema:"Sort in descending order"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+	SummaryOnly  bool     `json:"summary_only,omitempty" jsonschema:"Return only the item count, breakdowns by status and other enum fields, and the first few items"`
+}
+
+type SearchUsersInput struct {
+	Query        string   `json:"query,omitempty" jsonschema:"Case-insensitive substring to match against email, name, or employee ID"`
+	Department   string   `json:"department,omitempty" jsonschema:"Only users whose department contains this text (case-insensitive)"`
+	Title        string   `json:"title,omitempty" jsonschema:"Only users whose job title contains this text (case-insensitive)"`
+	Location     string   `json:"location,omitempty" jsonschema:"Only users whose location contains this text (case-insensitive)"`
+	Limit        int32    `json:"limit,omitempty" jsonschema:"Max users to return (default 20, max 100)"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+	SummaryOnly  bool     `json:"summary_only,omitempty" jsonschema:"Return only the item count, breakdowns by status and other enum fields, and the first few items"`
+}
+
+type ExportUsersInput struct {
+	IncludeGroups bool `json:"include_groups,omitempty" jsonschema:"Add a groups column listing each user's group names (slower on large organizations)"`
 }
 
 type UpdateUserRoleInput struct {
@@ -57,9 +101,52 @@ type ReactivateUserInput struct {
 	UserID string `json:"user_id" jsonschema:"User UUID to reactivate"`
 }
 
+type ListPendingInvitesInput struct {
+	Limit        int32    `json:"limit,omitempty" jsonschema:"Max invites to return (default 20, max 100)"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+	SummaryOnly  bool     `json:"summary_only,omitempty" jsonschema:"Return only the item count, breakdowns by status and other enum fields, and the first few items"`
+}
+
 type UpdateUserProfileInput struct {
+	UserID      string           `json:"user_id" jsonschema:"User UUID to update"`
+	Profile     UserProfileInput `json:"profile" jsonschema:"Profile attributes to set"`
+	Merge       bool             `json:"merge,omitempty" jsonschema:"Keep existing fields and custom attributes that are not set in profile instead of replacing the whole profile"`
+	ClearFields []string         `json:"clear_fields,omitempty" jsonschema:"Fields to blank when merging, e.g. department or custom_attributes.cost_center (implies merge)"`
+}
+
+type ProfileUpdateEntry struct {
 	UserID  string           `json:"user_id" jsonschema:"User UUID to update"`
-	Profile UserProfileInput `json:"profile" jsonschema:"Profile attributes to set"`
+	Profile UserProfileInput `json:"profile" jsonschema:"Profile attributes to set; empty fields are left unchanged"`
+}
+
+type BulkUpdateUserProfilesInput struct {
+	Updates []ProfileUpdateEntry `json:"updates,omitempty" jsonschema:"Profile updates keyed by user UUID (max 1000 combined with csv rows)"`
+	CSV     string               `json:"csv,omitempty" jsonschema:"CSV content with a header row (e.g. an HRIS export). Rows are matched by an email or employee_id column; profile columns such as department, title, location, manager, and custom_attributes.<key> are applied"`
+}
+
+// ── Output types ────────────────────────────────────────────────────────────
+
+// InviteResult is the outcome of one invitation in a bulk invite.
+type InviteResult struct {
+	Row    int    `json:"row"`
+	Email  string `json:"email"`
+	UserID string `json:"userId,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BulkInviteUsersResult struct {
+	Requested int            `json:"requested"`
+	Invited   int            `json:"invited"`
+	Failed    int            `json:"failed"`
+	Results   []InviteResult `json:"results"`
+}
+
+type BulkUpdateUserProfilesResult struct {
+	Requested      int          `json:"requested"`
+	Updated        int          `json:"updated"`
+	Failures       []RowFailure `json:"failures,omitempty"`
+	IgnoredColumns []string     `json:"ignoredColumns,omitempty"`
 }
 
 // ── Registration ────────────────────────────────────────────────────────────
@@ -82,6 +169,94 @@ func toProtoProfile(p *UserProfileInput) *pidgrv1.UserProfile {
 	}
 }
 
+// listAllUsers fetches every user in the organization.
+func listAllUsers(ctx context.Context, c *transport.Clients) ([]*pidgrv1.User, error) {
+	return fetchAll(ctx, func(ctx context.Context, p *pidgrv1.Pagination) ([]*pidgrv1.User, string, error) {
+		resp, err := c.Members.ListUsers(ctx, connect.NewRequest(&pidgrv1.ListUsersRequest{
+			Pagination: p,
+		}))
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Msg.GetUsers(), resp.Msg.GetPaginationMeta().GetNextPageToken(), nil
+	})
+}
+
+// findUser returns the first user for which match returns true, or a
+// NotFound error describing the lookup.
+func findUser(ctx context.Context, c *transport.Clients, what string, match func(*pidgrv1.User) bool) (*pidgrv1.User, error) {
+	users, err := listAllUsers(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		if match(u) {
+			return u, nil
+		}
+	}
+	return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no user with %s", what))
+}
+
+// parseUserStatus maps a status name to a UserStatus. PENDING is accepted as
+// an alias for INVITED.
+func parseUserStatus(status string) pidgrv1.UserStatus {
+	status = strings.ToUpper(strings.TrimSpace(status))
+	if status == "PENDING" {
+		status = "INVITED"
+	}
+	if v, ok := pidgrv1.UserStatus_value[status]; ok {
+		return pidgrv1.UserStatus(v)
+	}
+	if v, ok := pidgrv1.UserStatus_value["USER_STATUS_"+status]; ok {
+		return pidgrv1.UserStatus(v)
+	}
+	return pidgrv1.UserStatus_USER_STATUS_UNSPECIFIED
+}
+
+// userGroupNames maps each user UUID to the names of the groups they belong
+// to, in group listing order.
+func userGroupNames(ctx context.Context, c *transport.Clients) (map[string][]string, error) {
+	groups, err := listAllGroups(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	members := make([][]string, len(groups))
+	errs := forEach(ctx, len(groups), func(ctx context.Context, i int) error {
+		ids, err := listAllGroupMemberIDs(ctx, c, groups[i].GetId())
+		members[i] = ids
+		return err
+	}, nil, nil, nil)
+	names := make(map[string][]string)
+	for i, g := range groups {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		for _, id := range members[i] {
+			names[id] = append(names[id], g.GetName())
+		}
+	}
+	return names, nil
+}
+
+// matchesUserSearch reports whether u satisfies every non-empty criterion in
+// input. All comparisons are case-insensitive substring matches.
+func matchesUserSearch(u *pidgrv1.User, input SearchUsersInput) bool {
+	contains := func(field, sub string) bool {
+		return strings.Contains(strings.ToLower(field), strings.ToLower(sub))
+	}
+	p := u.GetProfile()
+	if input.Query != "" &&
+		!contains(u.GetEmail(), input.Query) &&
+		!contains(u.GetName(), input.Query) &&
+		!contains(p.GetFirstName()+" "+p.GetLastName(), input.Query) &&
+		!contains(p.GetEmployeeId(), input.Query) {
+		return false
+	}
+	return contains(p.GetDepartment(), input.Department) &&
+		contains(p.GetTitle(), input.Title) &&
+		contains(p.GetLocation(), input.Location)
+}
+
 func registerMemberTools(s *mcp.Server, c *transport.Clients) {
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "invite_user",
@@ -97,7 +272,77 @@ func registerMemberTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "bulk_invite_users",
+		Description: "Invite many users in one call (e.g. onboarding a new office). Each invite is sent individually; one failing invite does not stop the rest, and a per-invite result with the new user UUID or the error is returned. Use list_roles to find role UUIDs.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input BulkInviteUsersInput) (*mcp.CallToolResult, any, error) {
+		if len(input.Invites) == 0 {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("invites is required")))
+			return r, nil, nil
+		}
+		if len(input.Invites) > maxBatchSize {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("batch size %d exceeds maximum of %d", len(input.Invites), maxBatchSize)))
+			return r, nil, nil
+		}
+
+		result := BulkInviteUsersResult{
+			Requested: len(input.Invites),
+			Results:   make([]InviteResult, len(input.Invites)),
+		}
+		forEach(ctx, len(input.Invites), func(ctx context.Context, i int) error {
+			inv := input.Invites[i]
+			resp, err := c.Members.InviteUser(ctx, connect.NewRequest(&pidgrv1.InviteUserRequest{
+				Email:   inv.Email,
+				Name:    inv.Name,
+				RoleId:  inv.RoleID,
+				Profile: toProtoProfile(inv.Profile),
+			}))
+			if err != nil {
+				return err
+			}
+			result.Results[i].UserID = resp.Msg.GetUser().GetId()
+			return nil
+		}, func(i int, err error) {
+			entry := &result.Results[i]
+			entry.Row = i + 1
+			entry.Email = input.Invites[i].Email
+			if err != nil {
+				entry.Error = convert.ErrorMessage(err)
+				result.Failed++
+			} else {
+				result.Invited++
+			}
+		}, nil, toolProgress(ctx, req))
+
+		r, err := convert.JSONResult(result)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "list_pending_invites",
+		Description: "List users who have been invited but have not yet completed registration (status INVITED).",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListPendingInvitesInput) (*mcp.CallToolResult, any, error) {
+		users, err := listAllUsers(ctx, c)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		limit := int(clampPageSize(input.Limit))
+		var pending []*pidgrv1.User
+		for _, u := range users {
+			if u.GetStatus() != pidgrv1.UserStatus_USER_STATUS_INVITED {
+				continue
+			}
+			pending = append(pending, u)
+			if len(pending) == limit {
+				break
+			}
+		}
+		r, err := convert.ProtoResult(ctx, &pidgrv1.ListUsersResponse{Users: pending})
 		return r, nil, err
 	})
 
@@ -112,28 +357,162 @@ func registerMemberTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "get_user_by_email",
+		Description: "Retrieve a user by email address. Use this when you have an email but not the user UUID.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input GetUserByEmailInput) (*mcp.CallToolResult, any, error) {
+		email := strings.TrimSpace(input.Email)
+		if email == "" {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("email is required")))
+			return r, nil, nil
+		}
+		user, err := findUser(ctx, c, "email "+email, func(u *pidgrv1.User) bool {
+			return strings.EqualFold(u.GetEmail(), email)
+		})
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		r, err := convert.ProtoResult(ctx, &pidgrv1.GetUserResponse{User: user})
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "get_user_by_employee_id",
+		Description: "Retrieve a user by their organization employee ID (the employee_id profile field).",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input GetUserByEmployeeIDInput) (*mcp.CallToolResult, any, error) {
+		employeeID := strings.TrimSpace(input.EmployeeID)
+		if employeeID == "" {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("employee_id is required")))
+			return r, nil, nil
+		}
+		user, err := findUser(ctx, c, "employee ID "+employeeID, func(u *pidgrv1.User) bool {
+			return u.GetProfile().GetEmployeeId() == employeeID
+		})
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		r, err := convert.ProtoResult(ctx, &pidgrv1.GetUserResponse{User: user})
 		return r, nil, err
 	})
 
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "list_users",
-		Description: "List all users in the organization with pagination. Call this first to discover user UUIDs before using other user tools.",
+		Description: "List users in the organization with pagination, optionally filtered by role, status, and department and sorted by name, email, or creation date (e.g. all deactivated users with an admin role). Filters and sorting apply to the users in the pages fetched, so a page may hold fewer than page_size matches; set fetch_all to filter and sort the whole directory. Call this first to discover user UUIDs before using other user tools.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListUsersInput) (*mcp.CallToolResult, any, error) {
-		resp, err := c.Members.ListUsers(ctx, connect.NewRequest(&pidgrv1.ListUsersRequest{
-			Pagination: &pidgrv1.Pagination{
-				PageSize:  clampPageSize(input.PageSize),
-				PageToken: input.PageToken,
-			},
-		}))
+		filter, err := newUserFilter(input.RoleID, input.Status, input.Department, input.SortBy, input.SortDesc)
+		if err != nil {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, err))
+			return r, nil, nil
+		}
+		msg, err := collectPages(ctx, input.PageSize, input.PageToken, input.FetchAll, func(ctx context.Context, p *pidgrv1.Pagination) (*pidgrv1.ListUsersResponse, string, error) {
+			resp, err := c.Members.ListUsers(ctx, connect.NewRequest(&pidgrv1.ListUsersRequest{
+				Pagination: p,
+			}))
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Msg, resp.Msg.GetPaginationMeta().GetNextPageToken(), nil
+		})
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		msg.Users = filter.apply(msg.GetUsers())
+		r, err := convert.ProtoResult(ctx, msg)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "search_users",
+		Description: "Find users by email, name, or employee ID, optionally narrowed by department, title, and location (e.g. query \"maria\" with department \"finance\"). Prefer this over paginating through list_users.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input SearchUsersInput) (*mcp.CallToolResult, any, error) {
+		users, err := listAllUsers(ctx, c)
 		if err != nil {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		limit := int(clampPageSize(input.Limit))
+		var matches []*pidgrv1.User
+		for _, u := range users {
+			if !matchesUserSearch(u, input) {
+				continue
+			}
+			matches = append(matches, u)
+			if len(matches) == limit {
+				break
+			}
+		}
+		r, err := convert.ProtoResult(ctx, &pidgrv1.ListUsersResponse{Users: matches})
 		return r, nil, err
 	})
 
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "export_users",
+		Description: "Export every user in the organization as a CSV resource with profile fields, role, and status, for compliance reports and HR reconciliation. Set include_groups to add each user's group names.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ExportUsersInput) (*mcp.CallToolResult, any, error) {
+		users, err := listAllUsers(ctx, c)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		roles, err := c.Roles.ListRoles(ctx, connect.NewRequest(&pidgrv1.ListRolesRequest{}))
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		roleNames := make(map[string]string, len(roles.Msg.GetRoles()))
+		for _, role := range roles.Msg.GetRoles() {
+			roleNames[role.GetId()] = role.GetName()
+		}
+		var groups map[string][]string
+		if input.IncludeGroups {
+			if groups, err = userGroupNames(ctx, c); err != nil {
+				r, _ := convert.ErrorResult(err)
+				return r, nil, nil
+			}
+		}
+
+		header := []string{
+			"user_id", "email", "name", "status", "role_id", "role",
+			"first_name", "last_name", "department", "title", "phone", "location",
+			"employee_id", "manager_name", "start_date",
+		}
+		if input.IncludeGroups {
+			header = append(header, "groups")
+		}
+		rows := make([][]string, len(users))
+		for i, u := range users {
+			p := u.GetProfile()
+			row := []string{
+				u.GetId(), u.GetEmail(), u.GetName(),
+				convert.EnumValue(u.GetStatus().String(), "USER_STATUS_"),
+				u.GetRoleId(), roleNames[u.GetRoleId()],
+				p.GetFirstName(), p.GetLastName(), p.GetDepartment(), p.GetTitle(), p.GetPhone(), p.GetLocation(),
+				p.GetEmployeeId(), p.GetManagerName(), p.GetStartDate(),
+			}
+			if input.IncludeGroups {
+				row = append(row, strings.Join(groups[u.GetId()], ";"))
+			}
+			rows[i] = row
+		}
+		data, err := writeCSV(header, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		return convert.ResourceResult(
+			fmt.Sprintf("Exported %d users", len(rows)),
+			"pidgr://users.csv",
+			"text/csv",
+			data,
+		), nil, nil
+	})
+
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "update_user_role",
 		Description: "Change a user's role. Use list_users to find the user UUID and list_roles to find role UUIDs.",
@@ -146,13 +525,13 @@ func registerMemberTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "deactivate_user",
-		Description: "Deactivate a user (they will no longer receive messages). Use list_users to find the user UUID.",
+		Description: "Deactivate a user (they will no longer receive messages). This is reversible with reactivate_user. Use list_users to find the user UUID.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input DeactivateUserInput) (*mcp.CallToolResult, any, error) {
 		resp, err := c.Members.DeactivateUser(ctx, connect.NewRequest(&pidgrv1.DeactivateUserRequest{
 			UserId: input.UserID,
@@ -161,7 +540,7 @@ func registerMemberTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -176,23 +555,148 @@ func registerMemberTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "bulk_update_user_profiles",
+		Description: "Update many user profiles in one call, from a list of {user_id, profile} entries or CSV content keyed by email or employee_id (e.g. an HRIS sync). Each update is merged into the existing profile, so empty fields are left unchanged. Per-row failures are reported without stopping the rest.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input BulkUpdateUserProfilesInput) (*mcp.CallToolResult, any, error) {
+		type pendingUpdate struct {
+			Row     int
+			Key     string
+			UserID  string
+			Profile UserProfileInput
+		}
+
+		var header []string
+		var records []csvRecord
+		if input.CSV != "" {
+			var err error
+			header, records, err = parseRecordCSV(input.CSV)
+			if err != nil {
+				r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, err))
+				return r, nil, nil
+			}
+		}
+		total := len(input.Updates) + len(records)
+		if total == 0 {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("updates or csv is required")))
+			return r, nil, nil
+		}
+		if total > maxBatchSize {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("batch size %d exceeds maximum of %d", total, maxBatchSize)))
+			return r, nil, nil
+		}
+
+		result := BulkUpdateUserProfilesResult{Requested: total}
+		keyColumn := ""
+		if len(records) > 0 {
+			if i := headerIndex(header, emailHeaders); i >= 0 {
+				keyColumn = header[i]
+			} else if headerIndex(header, map[string]bool{"employee_id": true}) >= 0 {
+				keyColumn = "employee_id"
+			} else {
+				r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("csv needs an email or employee_id column")))
+				return r, nil, nil
+			}
+			for _, h := range header {
+				if h != keyColumn && !isProfileColumn(h) {
+					result.IgnoredColumns = append(result.IgnoredColumns, h)
+				}
+			}
+		}
+
+		users, err := listAllUsers(ctx, c)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		byID := make(map[string]*pidgrv1.User, len(users))
+		byKey := make(map[string]*pidgrv1.User, len(users))
+		for _, u := range users {
+			byID[u.GetId()] = u
+			key := strings.ToLower(u.GetEmail())
+			if keyColumn == "employee_id" {
+				key = strings.ToLower(u.GetProfile().GetEmployeeId())
+			}
+			if key != "" {
+				byKey[key] = u
+			}
+		}
+
+		var pending []pendingUpdate
+		for i, e := range input.Updates {
+			if byID[e.UserID] == nil {
+				result.Failures = append(result.Failures, RowFailure{Row: i + 1, Value: e.UserID, Error: "no user with this ID"})
+				continue
+			}
+			pending = append(pending, pendingUpdate{Row: i + 1, Key: e.UserID, UserID: e.UserID, Profile: e.Profile})
+		}
+		for _, rec := range records {
+			key := rec.Fields[keyColumn]
+			if key == "" {
+				result.Failures = append(result.Failures, RowFailure{Row: rec.Row, Error: "missing " + keyColumn})
+				continue
+			}
+			u := byKey[strings.ToLower(key)]
+			if u == nil {
+				result.Failures = append(result.Failures, RowFailure{Row: rec.Row, Value: key, Error: "no user with this " + keyColumn})
+				continue
+			}
+			pending = append(pending, pendingUpdate{Row: rec.Row, Key: key, UserID: u.GetId(), Profile: profileFromRecord(rec.Fields)})
+		}
+
+		errs := forEach(ctx, len(pending), func(ctx context.Context, i int) error {
+			upd := pending[i]
+			profile, _ := mergeProfile(fromProtoProfile(byID[upd.UserID].GetProfile()), upd.Profile, nil)
+			_, err := c.Members.UpdateUserProfile(ctx, connect.NewRequest(&pidgrv1.UpdateUserProfileRequest{
+				UserId:  upd.UserID,
+				Profile: toProtoProfile(&profile),
+			}))
+			return err
+		}, nil, nil, toolProgress(ctx, req))
+		for i, upd := range pending {
+			if errs[i] != nil {
+				result.Failures = append(result.Failures, RowFailure{Row: upd.Row, Value: upd.Key, Error: convert.ErrorMessage(errs[i])})
+			} else {
+				result.Updated++
+			}
+		}
+
+		r, err := convert.JSONResult(result)
 		return r, nil, err
 	})
 
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "update_user_profile",
-		Description: "Update a user's profile attributes (department, title, etc.). Use list_users to find the user UUID.",
+		Description: "Update a user's profile attributes (department, title, etc.). By default the profile is replaced; set merge=true to change only the fields given (e.g. a single custom attribute) and clear_fields to blank specific fields. Use list_users to find the user UUID.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input UpdateUserProfileInput) (*mcp.CallToolResult, any, error) {
+		profile := input.Profile
+		if input.Merge || len(input.ClearFields) > 0 {
+			current, err := c.Members.GetUser(ctx, connect.NewRequest(&pidgrv1.GetUserRequest{
+				UserId: input.UserID,
+			}))
+			if err != nil {
+				r, _ := convert.ErrorResult(err)
+				return r, nil, nil
+			}
+			profile, err = mergeProfile(fromProtoProfile(current.Msg.GetUser().GetProfile()), input.Profile, input.ClearFields)
+			if err != nil {
+				r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, err))
+				return r, nil, nil
+			}
+		}
 		resp, err := c.Members.UpdateUserProfile(ctx, connect.NewRequest(&pidgrv1.UpdateUserProfileRequest{
 			UserId:  input.UserID,
-			Profile: toProtoProfile(&input.Profile),
+			Profile: toProtoProfile(&profile),
 		}))
 		if err != nil {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 }
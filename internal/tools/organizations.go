@@ -22,7 +22,10 @@ type CreateOrganizationInput struct {
 	CompanySize string `json:"company_size,omitempty" jsonschema:"Employee count: 1_200/200_500/500_1000/1000_5000/5000_PLUS"`
 }
 
-type GetOrganizationInput struct{}
+type GetOrganizationInput struct {
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+}
 
 type UpdateOrganizationInput struct {
 	Name            string                      `json:"name,omitempty" jsonschema:"New organization name"`
@@ -40,6 +43,11 @@ type UpdateSsoAttributeMappingsInput struct {
 	SsoAttributeMappings []SsoMappingInput `json:"sso_attribute_mappings" jsonschema:"Complete list of SSO mappings (replaces all existing)"`
 }
 
+type TestSsoAttributeMappingsInput struct {
+	Claims               map[string]any    `json:"claims" jsonschema:"Sample IdP claims as a JSON object (e.g. a decoded ID token)"`
+	SsoAttributeMappings []SsoMappingInput `json:"sso_attribute_mappings,omitempty" jsonschema:"Proposed mappings to test; omit to test the organization's current mappings"`
+}
+
 // ── Registration ────────────────────────────────────────────────────────────
 
 func registerOrganizationTools(s *mcp.Server, c *transport.Clients) {
@@ -69,7 +77,7 @@ func registerOrganizationTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -82,7 +90,7 @@ func registerOrganizationTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -112,7 +120,7 @@ func registerOrganizationTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -134,7 +142,34 @@ func registerOrganizationTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "test_sso_attribute_mappings",
+		Description: "Dry-run SSO attribute mappings against sample IdP claims and show the resulting profile fields, which mappings found no claim, and which claims are unmapped. Tests the current mappings unless proposed ones are given. Nothing is saved; use update_sso_attribute_mappings to apply.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input TestSsoAttributeMappingsInput) (*mcp.CallToolResult, any, error) {
+		if len(input.Claims) == 0 {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("claims is required")))
+			return r, nil, nil
+		}
+		mappings := input.SsoAttributeMappings
+		source := "proposed"
+		if mappings == nil {
+			resp, err := c.Organizations.GetOrganization(ctx, connect.NewRequest(&pidgrv1.GetOrganizationRequest{}))
+			if err != nil {
+				r, _ := convert.ErrorResult(err)
+				return r, nil, nil
+			}
+			source = "current"
+			for _, m := range resp.Msg.GetOrganization().GetSsoAttributeMappings() {
+				mappings = append(mappings, SsoMappingInput{IdpClaim: m.GetIdpClaim(), ProfileField: m.GetProfileField()})
+			}
+		}
+		result := applySsoMappings(input.Claims, mappings)
+		result.Source = source
+		r, err := convert.JSONResult(result)
 		return r, nil, err
 	})
 }
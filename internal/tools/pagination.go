@@ -0,0 +1,131 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	pidgrv1 "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// PageResult is the pagination block of typed list results. It is named
+// paginationMeta, like the block of proto list results, so the fields,
+// summary_only, and ndjson options and result truncation treat it the same.
+type PageResult struct {
+	NextPageToken string `json:"nextPageToken,omitempty"`
+}
+
+// pageResult returns the pagination block for a page, or nil on the last page.
+func pageResult(nextPageToken string) *PageResult {
+	if nextPageToken == "" {
+		return nil
+	}
+	return &PageResult{NextPageToken: nextPageToken}
+}
+
+// maxFetchPages bounds server-side pagination loops so a backend that keeps
+// returning page tokens cannot pin a tool call indefinitely.
+const maxFetchPages = 500
+
+// pageFunc fetches one page using the given pagination and returns the page's
+// items along with the next page token ("" when there are no more pages).
+type pageFunc[T any] func(ctx context.Context, p *pidgrv1.Pagination) ([]T, string, error)
+
+// fetchAll walks every page of a paginated list RPC and returns all items.
+func fetchAll[T any](ctx context.Context, fetch pageFunc[T]) ([]T, error) {
+	var all []T
+	token := ""
+	for range maxFetchPages {
+		items, next, err := fetch(ctx, &pidgrv1.Pagination{
+			PageSize:  maxPageSize,
+			PageToken: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if next == "" {
+			return all, nil
+		}
+		token = next
+	}
+	return nil, fmt.Errorf("pagination exceeded %d pages", maxFetchPages)
+}
+
+// fetch_all bounds: a list tool asked for every page stops after this many
+// pages, or once the aggregated response reaches this size, and returns a
+// next page token for the rest.
+const (
+	maxFetchAllPages = 20
+	maxFetchAllBytes = 1 << 20
+)
+
+// responsePageFunc fetches one page of a list RPC and returns the response
+// along with the next page token ("" when there are no more pages).
+type responsePageFunc[R proto.Message] func(ctx context.Context, p *pidgrv1.Pagination) (R, string, error)
+
+// collectPages fetches the requested page of a list RPC or, when all is set,
+// walks pages from pageToken and merges them into one response: repeated
+// fields are concatenated, and the pagination block ends up with the token
+// to continue from, or none once the list is exhausted.
+func collectPages[R proto.Message](ctx context.Context, pageSize int32, pageToken string, all bool, fetch responsePageFunc[R]) (R, error) {
+	if !all {
+		msg, _, err := fetch(ctx, &pidgrv1.Pagination{
+			PageSize:  clampPageSize(pageSize),
+			PageToken: pageToken,
+		})
+		return msg, err
+	}
+
+	var merged R
+	token := pageToken
+	for page := 0; page < maxFetchAllPages; page++ {
+		msg, next, err := fetch(ctx, &pidgrv1.Pagination{
+			PageSize:  maxPageSize,
+			PageToken: token,
+		})
+		if err != nil {
+			var zero R
+			return zero, err
+		}
+		if page == 0 {
+			merged = msg
+		} else {
+			proto.Merge(merged, msg)
+		}
+		token = next
+		if token == "" || proto.Size(merged) >= maxFetchAllBytes {
+			break
+		}
+	}
+	setNextPageToken(merged, token)
+	return merged, nil
+}
+
+// setNextPageToken sets the next page token in a list response's
+// pagination_meta block. Merging pages cannot clear it, since proto.Merge
+// skips empty strings.
+func setNextPageToken(msg proto.Message, token string) {
+	m := msg.ProtoReflect()
+	fd := m.Descriptor().Fields().ByName("pagination_meta")
+	if fd == nil || fd.Message() == nil {
+		return
+	}
+	if token == "" && !m.Has(fd) {
+		return
+	}
+	pm := m.Mutable(fd).Message()
+	next := pm.Descriptor().Fields().ByName("next_page_token")
+	if next == nil {
+		return
+	}
+	if token == "" {
+		pm.Clear(next)
+	} else {
+		pm.Set(next, protoreflect.ValueOfString(token))
+	}
+}
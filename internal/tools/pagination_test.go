@@ -0,0 +1,217 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	pidgrv1 "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestFetchAll(t *testing.T) {
+	pages := map[string]struct {
+		items []int
+		next  string
+	}{
+		"":   {[]int{1, 2}, "p2"},
+		"p2": {[]int{3}, "p3"},
+		"p3": {[]int{4, 5}, ""},
+	}
+
+	var sizes []int32
+	got, err := fetchAll(context.Background(), func(ctx context.Context, p *pidgrv1.Pagination) ([]int, string, error) {
+		sizes = append(sizes, p.PageSize)
+		page := pages[p.PageToken]
+		return page.items, page.next, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(got) != "[1 2 3 4 5]" {
+		t.Errorf("fetchAll = %v, want [1 2 3 4 5]", got)
+	}
+	for _, s := range sizes {
+		if s != maxPageSize {
+			t.Errorf("page size = %d, want %d", s, maxPageSize)
+		}
+	}
+}
+
+func TestFetchAllError(t *testing.T) {
+	calls := 0
+	_, err := fetchAll(context.Background(), func(ctx context.Context, p *pidgrv1.Pagination) ([]int, string, error) {
+		calls++
+		if calls == 2 {
+			return nil, "", fmt.Errorf("boom")
+		}
+		return []int{calls}, "next", nil
+	})
+	if err == nil {
+		t.Fatal("expected error from second page")
+	}
+}
+
+func TestFetchAllPageCap(t *testing.T) {
+	_, err := fetchAll(context.Background(), func(ctx context.Context, p *pidgrv1.Pagination) ([]int, string, error) {
+		return nil, "forever", nil
+	})
+	if err == nil {
+		t.Fatal("expected error when pagination never terminates")
+	}
+}
+
+// listResponseType builds a list response message shaped like the backend's:
+// repeated items plus a pagination_meta block with a next page token.
+func listResponseType(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("list_test.proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("PaginationMeta"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("next_page_token"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), JsonName: proto.String("nextPageToken")},
+				},
+			},
+			{
+				Name: proto.String("ListResponse"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("items"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(), JsonName: proto.String("items")},
+					{Name: proto.String("pagination_meta"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(), TypeName: proto.String(".test.PaginationMeta"), JsonName: proto.String("paginationMeta")},
+				},
+			},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("build descriptor: %v", err)
+	}
+	return fd.Messages().ByName("ListResponse")
+}
+
+// listPage returns a fetch function over pages of items keyed by token.
+func listPage(md protoreflect.MessageDescriptor, pages map[string][]string, next map[string]string, sizes *[]int32) responsePageFunc[*dynamicpb.Message] {
+	return func(ctx context.Context, p *pidgrv1.Pagination) (*dynamicpb.Message, string, error) {
+		*sizes = append(*sizes, p.PageSize)
+		msg := dynamicpb.NewMessage(md)
+		items := msg.Mutable(md.Fields().ByName("items")).List()
+		for _, item := range pages[p.PageToken] {
+			items.Append(protoreflect.ValueOfString(item))
+		}
+		if token := next[p.PageToken]; token != "" {
+			pf := md.Fields().ByName("pagination_meta")
+			pm := msg.Mutable(pf).Message()
+			pm.Set(pf.Message().Fields().ByName("next_page_token"), protoreflect.ValueOfString(token))
+		}
+		return msg, next[p.PageToken], nil
+	}
+}
+
+func listItems(msg *dynamicpb.Message) ([]string, string) {
+	md := msg.Descriptor()
+	var items []string
+	list := msg.Get(md.Fields().ByName("items")).List()
+	for i := range list.Len() {
+		items = append(items, list.Get(i).String())
+	}
+	pf := md.Fields().ByName("pagination_meta")
+	token := msg.Get(pf).Message().Get(pf.Message().Fields().ByName("next_page_token")).String()
+	return items, token
+}
+
+func TestCollectPages(t *testing.T) {
+	md := listResponseType(t)
+	pages := map[string][]string{"": {"a", "b"}, "p2": {"c"}, "p3": {"d"}}
+	next := map[string]string{"": "p2", "p2": "p3"}
+
+	var sizes []int32
+	msg, err := collectPages(context.Background(), 2, "", false, listPage(md, pages, next, &sizes))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if items, token := listItems(msg); len(items) != 2 || token != "p2" || sizes[0] != 2 {
+		t.Errorf("single page: got %v, token %q, size %v", items, token, sizes)
+	}
+
+	sizes = nil
+	msg, err = collectPages(context.Background(), 2, "", true, listPage(md, pages, next, &sizes))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	items, token := listItems(msg)
+	if fmt.Sprint(items) != "[a b c d]" || token != "" {
+		t.Errorf("fetch_all: got %v, token %q; want all items and no token", items, token)
+	}
+	if len(sizes) != 3 || sizes[0] != maxPageSize {
+		t.Errorf("fetch_all: got page sizes %v, want 3 pages of %d", sizes, maxPageSize)
+	}
+}
+
+func TestCollectPagesCap(t *testing.T) {
+	md := listResponseType(t)
+	pages := map[string][]string{}
+	next := map[string]string{}
+	for i := range maxFetchAllPages + 5 {
+		token := ""
+		if i > 0 {
+			token = fmt.Sprintf("p%d", i)
+		}
+		pages[token] = []string{fmt.Sprint(i)}
+		next[token] = fmt.Sprintf("p%d", i+1)
+	}
+
+	var sizes []int32
+	msg, err := collectPages(context.Background(), 0, "", true, listPage(md, pages, next, &sizes))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	items, token := listItems(msg)
+	if len(items) != maxFetchAllPages {
+		t.Errorf("got %d items, want %d", len(items), maxFetchAllPages)
+	}
+	if want := fmt.Sprintf("p%d", maxFetchAllPages); token != want {
+		t.Errorf("got next page token %q, want %q to continue from", token, want)
+	}
+}
+
+func TestCollectPagesGeneratedResponse(t *testing.T) {
+	next := map[string]string{"": "p2", "p2": "p3"}
+	fetch := func(ctx context.Context, p *pidgrv1.Pagination) (*pidgrv1.ListCampaignsResponse, string, error) {
+		resp := &pidgrv1.ListCampaignsResponse{
+			Campaigns:      []*pidgrv1.Campaign{{Id: "c-" + p.PageToken}},
+			PaginationMeta: &pidgrv1.PaginationMeta{NextPageToken: next[p.PageToken], TotalCount: 3},
+		}
+		return resp, resp.GetPaginationMeta().GetNextPageToken(), nil
+	}
+
+	msg, err := collectPages(context.Background(), 0, "", true, fetch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(msg.GetCampaigns()) != 3 {
+		t.Errorf("got %d campaigns, want 3", len(msg.GetCampaigns()))
+	}
+	if token := msg.GetPaginationMeta().GetNextPageToken(); token != "" {
+		t.Errorf("got next page token %q after the last page, want none", token)
+	}
+
+	msg, err = collectPages(context.Background(), 0, "p2", true, func(ctx context.Context, p *pidgrv1.Pagination) (*pidgrv1.ListCampaignsResponse, string, error) {
+		resp, _, err := fetch(ctx, p)
+		return resp, "p9", err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token := msg.GetPaginationMeta().GetNextPageToken(); token != "p9" {
+		t.Errorf("got next page token %q, want p9 to continue from", token)
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	pidgrv1 "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1"
+)
+
+// customAttributePrefix selects a single custom attribute in clear_fields,
+// e.g. "custom_attributes.cost_center".
+const customAttributePrefix = "custom_attributes."
+
+// profileColumns maps normalized CSV column names to UserProfileInput JSON
+// field names.
+var profileColumns = map[string]string{
+	"first_name":      "first_name",
+	"given_name":      "first_name",
+	"last_name":       "last_name",
+	"surname":         "last_name",
+	"family_name":     "last_name",
+	"department":      "department",
+	"title":           "title",
+	"job_title":       "title",
+	"phone":           "phone",
+	"phone_number":    "phone",
+	"location":        "location",
+	"office":          "location",
+	"employee_id":     "employee_id",
+	"employee_number": "employee_id",
+	"manager_name":    "manager_name",
+	"manager":         "manager_name",
+	"start_date":      "start_date",
+	"hire_date":       "start_date",
+}
+
+// isProfileColumn reports whether a normalized CSV column maps to a profile
+// field or a custom attribute.
+func isProfileColumn(name string) bool {
+	_, ok := profileColumns[name]
+	return ok || (strings.HasPrefix(name, customAttributePrefix) && len(name) > len(customAttributePrefix))
+}
+
+// stringFields returns pointers to the profile's plain string fields, keyed
+// by their JSON names.
+func (p *UserProfileInput) stringFields() map[string]*string {
+	return map[string]*string{
+		"first_name":   &p.FirstName,
+		"last_name":    &p.LastName,
+		"department":   &p.Department,
+		"title":        &p.Title,
+		"phone":        &p.Phone,
+		"location":     &p.Location,
+		"employee_id":  &p.EmployeeID,
+		"manager_name": &p.ManagerName,
+		"start_date":   &p.StartDate,
+	}
+}
+
+// profileFromRecord builds a profile from the recognized columns of a CSV
+// record. Columns named "custom_attributes.<key>" set custom attributes.
+// Other columns are ignored.
+func profileFromRecord(fields map[string]string) UserProfileInput {
+	var p UserProfileInput
+	targets := p.stringFields()
+	for name, v := range fields {
+		if field, ok := profileColumns[name]; ok {
+			if v != "" {
+				*targets[field] = v
+			}
+			continue
+		}
+		if key := strings.TrimPrefix(name, customAttributePrefix); key != name && key != "" && v != "" {
+			if p.CustomAttributes == nil {
+				p.CustomAttributes = make(map[string]string)
+			}
+			p.CustomAttributes[key] = v
+		}
+	}
+	return p
+}
+
+// fromProtoProfile is the inverse of toProtoProfile.
+func fromProtoProfile(p *pidgrv1.UserProfile) UserProfileInput {
+	out := UserProfileInput{
+		FirstName:   p.GetFirstName(),
+		LastName:    p.GetLastName(),
+		Department:  p.GetDepartment(),
+		Title:       p.GetTitle(),
+		Phone:       p.GetPhone(),
+		Location:    p.GetLocation(),
+		EmployeeID:  p.GetEmployeeId(),
+		ManagerName: p.GetManagerName(),
+		StartDate:   p.GetStartDate(),
+	}
+	if attrs := p.GetCustomAttributes(); len(attrs) > 0 {
+		out.CustomAttributes = make(map[string]string, len(attrs))
+		for k, v := range attrs {
+			out.CustomAttributes[k] = v
+		}
+	}
+	return out
+}
+
+// mergeProfile overlays the non-empty fields of update onto base, merging
+// custom attributes key by key, then blanks every field named in clear.
+// Field names match the snake_case JSON names of UserProfileInput; use
+// "custom_attributes" to drop all custom attributes or
+// "custom_attributes.<key>" to drop one.
+func mergeProfile(base, update UserProfileInput, clear []string) (UserProfileInput, error) {
+	out := base
+	fields := out.stringFields()
+	for name, v := range update.stringFields() {
+		if *v != "" {
+			*fields[name] = *v
+		}
+	}
+
+	attrs := make(map[string]string, len(base.CustomAttributes)+len(update.CustomAttributes))
+	for k, v := range base.CustomAttributes {
+		attrs[k] = v
+	}
+	for k, v := range update.CustomAttributes {
+		attrs[k] = v
+	}
+
+	for _, name := range clear {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "custom_attributes":
+			attrs = map[string]string{}
+		case strings.HasPrefix(name, customAttributePrefix):
+			delete(attrs, strings.TrimPrefix(name, customAttributePrefix))
+		default:
+			field, ok := fields[name]
+			if !ok {
+				return UserProfileInput{}, fmt.Errorf("unknown profile field %q in clear_fields", name)
+			}
+			*field = ""
+		}
+	}
+
+	out.CustomAttributes = nil
+	if len(attrs) > 0 {
+		out.CustomAttributes = attrs
+	}
+	return out, nil
+}
+
+// profileChanges returns the sorted names of the fields that differ between
+// two profiles. Custom attributes are reported as "custom_attributes.<key>".
+func profileChanges(before, after UserProfileInput) []string {
+	var changed []string
+	afterFields := after.stringFields()
+	for name, v := range before.stringFields() {
+		if *v != *afterFields[name] {
+			changed = append(changed, name)
+		}
+	}
+	for k, v := range before.CustomAttributes {
+		if av, ok := after.CustomAttributes[k]; !ok || av != v {
+			changed = append(changed, customAttributePrefix+k)
+		}
+	}
+	for k := range after.CustomAttributes {
+		if _, ok := before.CustomAttributes[k]; !ok {
+			changed = append(changed, customAttributePrefix+k)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
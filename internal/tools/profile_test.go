@@ -0,0 +1,109 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeProfile(t *testing.T) {
+	base := UserProfileInput{
+		FirstName:        "Maria",
+		Department:       "Finance",
+		Title:            "Analyst",
+		CustomAttributes: map[string]string{"cost_center": "42", "shift": "day"},
+	}
+	update := UserProfileInput{
+		Title:            "Senior Analyst",
+		CustomAttributes: map[string]string{"shift": "night"},
+	}
+
+	got, err := mergeProfile(base, update, []string{"department", "custom_attributes.cost_center"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := UserProfileInput{
+		FirstName:        "Maria",
+		Title:            "Senior Analyst",
+		CustomAttributes: map[string]string{"shift": "night"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeProfile = %+v, want %+v", got, want)
+	}
+	if base.CustomAttributes["cost_center"] != "42" {
+		t.Error("mergeProfile modified the base custom attributes")
+	}
+}
+
+func TestMergeProfileClearAllCustomAttributes(t *testing.T) {
+	base := UserProfileInput{CustomAttributes: map[string]string{"a": "1"}}
+	got, err := mergeProfile(base, UserProfileInput{}, []string{"custom_attributes"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.CustomAttributes != nil {
+		t.Errorf("custom attributes = %v, want nil", got.CustomAttributes)
+	}
+}
+
+func TestMergeProfileUnknownField(t *testing.T) {
+	if _, err := mergeProfile(UserProfileInput{}, UserProfileInput{}, []string{"nickname"}); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+func TestProfileFromRecord(t *testing.T) {
+	got := profileFromRecord(map[string]string{
+		"email":                         "ana@example.com",
+		"job_title":                     "Analyst",
+		"manager":                       "Bo",
+		"department":                    "",
+		"custom_attributes.cost_center": "42",
+	})
+	want := UserProfileInput{
+		Title:            "Analyst",
+		ManagerName:      "Bo",
+		CustomAttributes: map[string]string{"cost_center": "42"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("profileFromRecord = %+v, want %+v", got, want)
+	}
+}
+
+func TestProfileFromRecordMixedCaseCustomAttribute(t *testing.T) {
+	_, records, err := parseRecordCSV("Email,Custom_Attributes.CostCenter\nana@example.com,42\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	base := UserProfileInput{CustomAttributes: map[string]string{"CostCenter": "7"}}
+	got, err := mergeProfile(base, profileFromRecord(records[0].Fields), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := map[string]string{"CostCenter": "42"}; !reflect.DeepEqual(got.CustomAttributes, want) {
+		t.Errorf("custom attributes = %v, want %v", got.CustomAttributes, want)
+	}
+}
+
+func TestProfileChanges(t *testing.T) {
+	before := UserProfileInput{
+		Department:       "Finance",
+		Title:            "Analyst",
+		CustomAttributes: map[string]string{"cost_center": "42", "shift": "day"},
+	}
+	after := UserProfileInput{
+		Department:       "Finance",
+		Title:            "Senior Analyst",
+		CustomAttributes: map[string]string{"shift": "night", "badge": "7"},
+	}
+	got := profileChanges(before, after)
+	want := []string{"custom_attributes.badge", "custom_attributes.cost_center", "custom_attributes.shift", "title"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("profileChanges = %v, want %v", got, want)
+	}
+	if got := profileChanges(before, before); got != nil {
+		t.Errorf("profileChanges of identical profiles = %v, want nil", got)
+	}
+}
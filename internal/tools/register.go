@@ -5,16 +5,24 @@ package tools
 
 import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/pidgr/pidgr-mcp/internal/convert"
+	"github.com/pidgr/pidgr-mcp/internal/observability"
 	"github.com/pidgr/pidgr-mcp/internal/transport"
 )
 
-// RegisterAll registers all 49 MCP tools on the server.
+// RegisterAll registers all 84 MCP tools on the server, along with the
+// middleware that traces tool calls, assigns correlation IDs, hides tools
+// excluded by the tool filter (see SetToolFilter), and applies per-call
+// result options.
 func RegisterAll(s *mcp.Server, c *transport.Clients) {
+	s.AddReceivingMiddleware(observability.TracingMiddleware, observability.CorrelationMiddleware, toolFilterMiddleware, convert.OptionsMiddleware)
 	registerCampaignTools(s, c)
 	registerTemplateTools(s, c)
 	registerGroupTools(s, c)
 	registerTeamTools(s, c)
+	registerAudienceTools(s, c)
 	registerMemberTools(s, c)
+	registerDirectoryTools(s, c)
 	registerOrganizationTools(s, c)
 	registerRoleTools(s, c)
 	registerApiKeyTools(s, c)
@@ -18,7 +18,7 @@ func TestRegisterAll(t *testing.T) {
 	}, nil)
 
 	// Create clients with a dummy URL (we won't actually make calls).
-	clients := transport.NewStaticTokenClients("http://localhost:50051", "test-key")
+	clients := transport.NewStaticTokenClients("http://localhost:50051", "test-key", transport.DefaultOptions())
 
 	// Register all tools.
 	RegisterAll(server, clients)
@@ -43,7 +43,7 @@ func TestRegisterAll(t *testing.T) {
 		t.Fatalf("ListTools error: %v", err)
 	}
 
-	want := 50
+	want := 84
 	if got := len(result.Tools); got != want {
 		t.Errorf("RegisterAll registered %d tools, want %d", got, want)
 		for _, tool := range result.Tools {
@@ -58,7 +58,7 @@ func TestToolNames(t *testing.T) {
 		Version: "test",
 	}, nil)
 
-	clients := transport.NewStaticTokenClients("http://localhost:50051", "test-key")
+	clients := transport.NewStaticTokenClients("http://localhost:50051", "test-key", transport.DefaultOptions())
 	RegisterAll(server, clients)
 
 	client := mcp.NewClient(&mcp.Implementation{
@@ -84,26 +84,60 @@ func TestToolNames(t *testing.T) {
 		// Campaign (7)
 		"create_campaign", "update_campaign", "start_campaign", "get_campaign",
 		"list_campaigns", "cancel_campaign", "list_deliveries",
-		// Template (4)
+		// Template (6)
 		"create_template", "update_template", "get_template", "list_templates",
-		// Group (9)
+		"import_template",
+		"get_template_usage",
+		// Group (17)
 		"create_group", "get_group", "list_groups", "update_group", "delete_group",
 		"add_group_members", "remove_group_members", "list_group_members", "get_user_group_memberships",
-		// Team (8)
+		"bulk_add_group_members",
+		"search_groups",
+		"sync_group_from_team",
+		"merge_groups",
+		"export_group_members",
+		"diff_group_members",
+		"clone_group",
+		"remove_all_group_members",
+		// Team (10)
 		"create_team", "get_team", "list_teams", "update_team", "delete_team",
 		"add_team_members", "remove_team_members", "list_team_members",
-		// Member (7)
+		"get_user_team_memberships",
+		"search_teams",
+		// Audience (1)
+		"get_audience_sizes",
+		// Member (14)
 		"invite_user", "get_user", "list_users", "update_user_role", "deactivate_user", "reactivate_user", "update_user_profile",
-		// Organization (4)
+		"bulk_invite_users",
+		"search_users",
+		"list_pending_invites",
+		"export_users",
+		"bulk_update_user_profiles",
+		"get_user_by_email", "get_user_by_employee_id",
+		// Directory (1)
+		"sync_users",
+		// Organization (5)
 		"create_organization", "get_organization", "update_organization", "update_sso_attribute_mappings",
-		// Role (4)
+		"test_sso_attribute_mappings",
+		// Role (8)
 		"list_roles", "create_role", "update_role", "delete_role",
-		// ApiKey (3)
+		"list_permissions",
+		"get_role", "list_role_members",
+		"clone_role",
+		// ApiKey (4)
 		"create_api_key", "list_api_keys", "revoke_api_key",
-		// Heatmap (2)
+		"rotate_api_key",
+		// Heatmap (8)
 		"query_heatmap_data", "list_screenshots",
-		// Replay (2)
+		"render_heatmap",
+		"get_screenshot",
+		"compare_heatmaps",
+		"export_heatmap_data",
+		"compare_cohorts",
+		"detect_heatmap_anomalies",
+		// Replay (3)
 		"list_session_recordings", "get_session_snapshots",
+		"summarize_session_recording",
 	}
 
 	registered := make(map[string]bool)
@@ -5,6 +5,7 @@ package tools
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"connectrpc.com/connect"
@@ -18,15 +19,52 @@ import (
 // ── Input types ─────────────────────────────────────────────────────────────
 
 type ListSessionRecordingsInput struct {
-	CampaignID string `json:"campaign_id,omitempty" jsonschema:"Filter by campaign UUID"`
-	DateFrom   string `json:"date_from,omitempty" jsonschema:"Start of time range (RFC 3339)"`
-	DateTo     string `json:"date_to,omitempty" jsonschema:"End of time range (RFC 3339)"`
-	PageSize   int32  `json:"page_size,omitempty" jsonschema:"Max items per page"`
-	PageToken  string `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
+	CampaignID    string   `json:"campaign_id,omitempty" jsonschema:"Filter by campaign UUID"`
+	UserID        string   `json:"user_id,omitempty" jsonschema:"Only recordings with this analytics user ID"`
+	MinDurationMs int64    `json:"min_duration_ms,omitempty" jsonschema:"Minimum recording duration in milliseconds"`
+	DateFrom      string   `json:"date_from,omitempty" jsonschema:"Start of time range (RFC 3339)"`
+	DateTo        string   `json:"date_to,omitempty" jsonschema:"End of time range (RFC 3339)"`
+	PageSize      int32    `json:"page_size,omitempty" jsonschema:"Max items per page"`
+	PageToken     string   `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
+	FetchAll      bool     `json:"fetch_all,omitempty" jsonschema:"Fetch every page server-side and return all items together, up to 20 pages; nextPageToken is set if more remain"`
+	Fields        []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat  string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+	SummaryOnly   bool     `json:"summary_only,omitempty" jsonschema:"Return only the item count, breakdowns by status and other enum fields, and the first few items"`
 }
 
 type GetSessionSnapshotsInput struct {
 	RecordingID string `json:"recording_id" jsonschema:"Recording ID"`
+	Offset      int    `json:"offset,omitempty" jsonschema:"Index of the first snapshot to return (use nextOffset from the previous chunk)"`
+	Limit       int    `json:"limit,omitempty" jsonschema:"Max snapshots per chunk (default 200, max 1000)"`
+	FromMs      int64  `json:"from_ms,omitempty" jsonschema:"Only include snapshots at or after this many milliseconds into the recording"`
+	ToMs        int64  `json:"to_ms,omitempty" jsonschema:"Only include snapshots at or before this many milliseconds into the recording"`
+}
+
+type SummarizeSessionRecordingInput struct {
+	RecordingID string `json:"recording_id" jsonschema:"Recording ID"`
+	MaxLines    int    `json:"max_lines,omitempty" jsonschema:"Max timeline entries to return (default 200)"`
+}
+
+// ── Helpers ─────────────────────────────────────────────────────────────────
+
+// filterRecordings keeps the recordings by userID (any user if empty) that
+// last at least minDurationMs. ListSessionRecordings filters only by
+// campaign and time range, so these filters run on the fetched pages.
+// SessionRecording carries no screen names or event count, so recordings
+// cannot be filtered by screen or event count without downloading each
+// one's snapshots.
+func filterRecordings(recordings []*pidgrv1.SessionRecording, userID string, minDurationMs int64) []*pidgrv1.SessionRecording {
+	var out []*pidgrv1.SessionRecording
+	for _, rec := range recordings {
+		if userID != "" && rec.GetAnalyticsUserId() != userID {
+			continue
+		}
+		if int64(rec.GetDurationSeconds())*1000 < minDurationMs {
+			continue
+		}
+		out = append(out, rec)
+	}
+	return out
 }
 
 // ── Registration ────────────────────────────────────────────────────────────
@@ -34,14 +72,10 @@ type GetSessionSnapshotsInput struct {
 func registerReplayTools(s *mcp.Server, c *transport.Clients) {
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "list_session_recordings",
-		Description: "List session recordings with optional campaign and time range filters. Use list_campaigns to find campaign UUIDs for filtering.",
+		Description: "List session recordings with optional campaign, user, minimum duration, and time range filters. The user and duration filters apply to each fetched page, so a page may hold fewer than page_size recordings; set fetch_all to search every page. Use list_campaigns to find campaign UUIDs.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListSessionRecordingsInput) (*mcp.CallToolResult, any, error) {
 		protoReq := &pidgrv1.ListSessionRecordingsRequest{
 			CampaignId: input.CampaignID,
-			Pagination: &pidgrv1.Pagination{
-				PageSize:  clampPageSize(input.PageSize),
-				PageToken: input.PageToken,
-			},
 		}
 
 		if input.DateFrom != "" {
@@ -55,19 +89,53 @@ func registerReplayTools(s *mcp.Server, c *transport.Clients) {
 			}
 		}
 
-		resp, err := c.Replays.ListSessionRecordings(ctx, connect.NewRequest(protoReq))
+		msg, err := collectPages(ctx, input.PageSize, input.PageToken, input.FetchAll, func(ctx context.Context, p *pidgrv1.Pagination) (*pidgrv1.ListSessionRecordingsResponse, string, error) {
+			protoReq.Pagination = p
+			resp, err := c.Replays.ListSessionRecordings(ctx, connect.NewRequest(protoReq))
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Msg, resp.Msg.GetPaginationMeta().GetNextPageToken(), nil
+		})
 		if err != nil {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		msg.Recordings = filterRecordings(msg.GetRecordings(), input.UserID, input.MinDurationMs)
+		r, err := convert.ProtoResult(ctx, msg)
 		return r, nil, err
 	})
 
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "get_session_snapshots",
-		Description: "Fetch snapshot data for a session recording. Use list_session_recordings to find recording IDs.",
+		Description: "Fetch raw rrweb snapshot data for a session recording in chunks. Recordings can be very large, so results are paged by offset and limit, optionally within a from_ms/to_ms time window; the response reports totalChunks and nextOffset for fetching the rest. Use summarize_session_recording for an overview and list_session_recordings to find recording IDs.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input GetSessionSnapshotsInput) (*mcp.CallToolResult, any, error) {
+		if input.ToMs > 0 && input.FromMs > input.ToMs {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("from_ms must not be after to_ms")))
+			return r, nil, nil
+		}
+		resp, err := c.Replays.GetSessionSnapshots(ctx, connect.NewRequest(&pidgrv1.GetSessionSnapshotsRequest{
+			RecordingId: input.RecordingID,
+		}))
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		events, err := parseSnapshotData(resp.Msg.GetSnapshotData())
+		if err != nil {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInternal, err))
+			return r, nil, nil
+		}
+		chunk := chunkSnapshots(events, input.Offset, input.Limit, input.FromMs, input.ToMs)
+		chunk.RecordingID = input.RecordingID
+		r, err := convert.JSONResult(chunk)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "summarize_session_recording",
+		Description: "Summarize a session recording as a compact, chronological narrative: screens visited with time spent, taps, scrolls, text input, and errors. Prefer this over get_session_snapshots, whose raw data is usually too large to reason over. Use list_session_recordings to find recording IDs.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input SummarizeSessionRecordingInput) (*mcp.CallToolResult, any, error) {
 		resp, err := c.Replays.GetSessionSnapshots(ctx, connect.NewRequest(&pidgrv1.GetSessionSnapshotsRequest{
 			RecordingId: input.RecordingID,
 		}))
@@ -75,7 +143,14 @@ func registerReplayTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		events, err := parseSnapshotData(resp.Msg.GetSnapshotData())
+		if err != nil {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInternal, err))
+			return r, nil, nil
+		}
+		summary := summarizeRRWeb(events, input.MaxLines)
+		summary.RecordingID = input.RecordingID
+		r, err := convert.JSONResult(summary)
 		return r, nil, err
 	})
 }
@@ -0,0 +1,286 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// rrweb event types and incremental snapshot sources, from the rrweb
+// recording format.
+const (
+	rrwebFullSnapshot        = 2
+	rrwebIncrementalSnapshot = 3
+	rrwebMeta                = 4
+	rrwebCustom              = 5
+	rrwebPlugin              = 6
+
+	rrwebSourceMouseInteraction = 2
+	rrwebSourceScroll           = 3
+	rrwebSourceInput            = 5
+
+	rrwebMouseClick    = 2
+	rrwebMouseDblClick = 4
+	rrwebTouchEnd      = 9
+)
+
+const (
+	// defaultSummaryLines caps the narrative timeline of a session summary.
+	defaultSummaryLines = 200
+	// defaultSnapshotChunk and maxSnapshotChunk bound how many raw snapshots
+	// get_session_snapshots returns per call.
+	defaultSnapshotChunk = 200
+	maxSnapshotChunk     = 1000
+)
+
+// ScreenVisit is one contiguous stay on a screen during a session.
+type ScreenVisit struct {
+	Screen     string `json:"screen"`
+	StartMs    int64  `json:"startMs"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// SessionActivity counts user interactions in a session.
+type SessionActivity struct {
+	Taps    int `json:"taps"`
+	Scrolls int `json:"scrolls"`
+	Inputs  int `json:"inputs"`
+	Errors  int `json:"errors"`
+}
+
+// SessionSummary is a compact, chronological description of a recording.
+type SessionSummary struct {
+	RecordingID string          `json:"recordingId,omitempty"`
+	DurationMs  int64           `json:"durationMs"`
+	EventCount  int             `json:"eventCount"`
+	Screens     []ScreenVisit   `json:"screens"`
+	Activity    SessionActivity `json:"activity"`
+	Timeline    []string        `json:"timeline"`
+	Truncated   bool            `json:"truncated,omitempty"`
+}
+
+// parseSnapshotData decodes a recording's snapshot_data, a JSON array of
+// rrweb events. Empty data means a recording with no events.
+func parseSnapshotData(data string) ([]map[string]any, error) {
+	if strings.TrimSpace(data) == "" {
+		return nil, nil
+	}
+	var events []map[string]any
+	if err := json.Unmarshal([]byte(data), &events); err != nil {
+		return nil, fmt.Errorf("decoding rrweb snapshot data: %w", err)
+	}
+	return events, nil
+}
+
+// summarizeRRWeb condenses rrweb events into a SessionSummary. Consecutive
+// scrolls on the same screen are collapsed into one timeline line, and the
+// timeline is cut off after maxLines entries.
+func summarizeRRWeb(events []map[string]any, maxLines int) SessionSummary {
+	if maxLines <= 0 {
+		maxLines = defaultSummaryLines
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return numberField(events[i], "timestamp") < numberField(events[j], "timestamp")
+	})
+
+	summary := SessionSummary{
+		EventCount: len(events),
+		Screens:    []ScreenVisit{},
+		Timeline:   []string{},
+	}
+	if len(events) == 0 {
+		return summary
+	}
+	start := int64(numberField(events[0], "timestamp"))
+	end := int64(numberField(events[len(events)-1], "timestamp"))
+	summary.DurationMs = end - start
+
+	screen := ""
+	pendingScrolls := 0
+	var scrollAt int64
+	add := func(at int64, format string, args ...any) {
+		if len(summary.Timeline) >= maxLines {
+			summary.Truncated = true
+			return
+		}
+		summary.Timeline = append(summary.Timeline, formatOffset(at)+" "+fmt.Sprintf(format, args...))
+	}
+	flushScrolls := func() {
+		if pendingScrolls == 0 {
+			return
+		}
+		if pendingScrolls == 1 {
+			add(scrollAt, "scrolled")
+		} else {
+			add(scrollAt, "scrolled %d times", pendingScrolls)
+		}
+		pendingScrolls = 0
+	}
+	enter := func(at int64, name string) {
+		if name == "" || name == screen {
+			return
+		}
+		flushScrolls()
+		if n := len(summary.Screens); n > 0 {
+			summary.Screens[n-1].DurationMs = at - summary.Screens[n-1].StartMs
+		}
+		screen = name
+		summary.Screens = append(summary.Screens, ScreenVisit{Screen: name, StartMs: at})
+		add(at, "opened %s", name)
+	}
+
+	for _, e := range events {
+		at := int64(numberField(e, "timestamp")) - start
+		data, _ := e["data"].(map[string]any)
+		switch int(numberField(e, "type")) {
+		case rrwebMeta:
+			enter(at, stringField(data, "href"))
+		case rrwebCustom:
+			tag := strings.ToLower(stringField(data, "tag"))
+			payload, _ := data["payload"].(map[string]any)
+			switch {
+			case strings.Contains(tag, "screen") || strings.Contains(tag, "navigation"):
+				name := stringField(payload, "name")
+				if name == "" {
+					name = stringField(payload, "screen")
+				}
+				if name == "" {
+					name = stringField(payload, "route")
+				}
+				enter(at, name)
+			case strings.Contains(tag, "error"):
+				flushScrolls()
+				summary.Activity.Errors++
+				add(at, "error: %s", truncateText(stringField(payload, "message"), 120))
+			}
+		case rrwebPlugin:
+			payload, _ := data["payload"].(map[string]any)
+			if strings.Contains(stringField(data, "plugin"), "console") && stringField(payload, "level") == "error" {
+				flushScrolls()
+				summary.Activity.Errors++
+				msg := ""
+				if parts, ok := payload["payload"].([]any); ok && len(parts) > 0 {
+					msg, _ = parts[0].(string)
+				}
+				add(at, "console error: %s", truncateText(strings.Trim(msg, `"`), 120))
+			}
+		case rrwebIncrementalSnapshot:
+			switch int(numberField(data, "source")) {
+			case rrwebSourceMouseInteraction:
+				switch int(numberField(data, "type")) {
+				case rrwebMouseClick, rrwebMouseDblClick, rrwebTouchEnd:
+					flushScrolls()
+					summary.Activity.Taps++
+					add(at, "tapped at (%d, %d)", int(numberField(data, "x")), int(numberField(data, "y")))
+				}
+			case rrwebSourceScroll:
+				if pendingScrolls == 0 {
+					scrollAt = at
+				}
+				pendingScrolls++
+				summary.Activity.Scrolls++
+			case rrwebSourceInput:
+				flushScrolls()
+				summary.Activity.Inputs++
+				add(at, "entered text")
+			}
+		}
+	}
+	flushScrolls()
+	if n := len(summary.Screens); n > 0 {
+		summary.Screens[n-1].DurationMs = summary.DurationMs - summary.Screens[n-1].StartMs
+	}
+	return summary
+}
+
+// SnapshotChunk is one page of a recording's raw rrweb snapshots.
+type SnapshotChunk struct {
+	RecordingID    string           `json:"recordingId,omitempty"`
+	Offset         int              `json:"offset"`
+	Limit          int              `json:"limit"`
+	Chunk          int              `json:"chunk"`
+	TotalChunks    int              `json:"totalChunks"`
+	TotalSnapshots int              `json:"totalSnapshots"`
+	NextOffset     *int             `json:"nextOffset,omitempty"`
+	Snapshots      []map[string]any `json:"snapshots"`
+}
+
+// chunkSnapshots returns the events between fromMs and toMs (offsets from the
+// first event, toMs <= 0 meaning the end of the recording), then pages them
+// by offset and limit. Totals and chunk numbers describe the time window.
+func chunkSnapshots(events []map[string]any, offset, limit int, fromMs, toMs int64) SnapshotChunk {
+	if limit <= 0 {
+		limit = defaultSnapshotChunk
+	}
+	if limit > maxSnapshotChunk {
+		limit = maxSnapshotChunk
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return numberField(events[i], "timestamp") < numberField(events[j], "timestamp")
+	})
+
+	window := events
+	if len(events) > 0 && (fromMs > 0 || toMs > 0) {
+		start := int64(numberField(events[0], "timestamp"))
+		window = make([]map[string]any, 0, len(events))
+		for _, e := range events {
+			at := int64(numberField(e, "timestamp")) - start
+			if at < fromMs || (toMs > 0 && at > toMs) {
+				continue
+			}
+			window = append(window, e)
+		}
+	}
+
+	chunk := SnapshotChunk{
+		Offset:         offset,
+		Limit:          limit,
+		Chunk:          offset/limit + 1,
+		TotalChunks:    (len(window) + limit - 1) / limit,
+		TotalSnapshots: len(window),
+		Snapshots:      []map[string]any{},
+	}
+	if offset >= len(window) {
+		return chunk
+	}
+	end := min(offset+limit, len(window))
+	chunk.Snapshots = window[offset:end]
+	if end < len(window) {
+		chunk.NextOffset = &end
+	}
+	return chunk
+}
+
+// formatOffset renders a millisecond offset as m:ss.
+func formatOffset(ms int64) string {
+	s := ms / 1000
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// numberField returns m[key] as a float64, or 0 if it is missing or not a number.
+func numberField(m map[string]any, key string) float64 {
+	v, _ := m[key].(float64)
+	return v
+}
+
+// stringField returns m[key] as a string, or "" if it is missing or not a string.
+func stringField(m map[string]any, key string) string {
+	v, _ := m[key].(string)
+	return v
+}
+
+// truncateText shortens s to at most n runes, marking the cut with "…".
+func truncateText(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func rrwebEvent(ts float64, typ int, data map[string]any) map[string]any {
+	return map[string]any{"timestamp": ts, "type": float64(typ), "data": data}
+}
+
+func TestSummarizeRRWeb(t *testing.T) {
+	events := []map[string]any{
+		rrwebEvent(1000, rrwebMeta, map[string]any{"href": "/home"}),
+		rrwebEvent(1500, rrwebFullSnapshot, map[string]any{}),
+		rrwebEvent(3000, rrwebIncrementalSnapshot, map[string]any{"source": float64(rrwebSourceMouseInteraction), "type": float64(rrwebMouseClick), "x": float64(120), "y": float64(340)}),
+		rrwebEvent(4000, rrwebIncrementalSnapshot, map[string]any{"source": float64(rrwebSourceScroll)}),
+		rrwebEvent(4100, rrwebIncrementalSnapshot, map[string]any{"source": float64(rrwebSourceScroll)}),
+		rrwebEvent(5000, rrwebCustom, map[string]any{"tag": "screen_view", "payload": map[string]any{"name": "Announcement"}}),
+		rrwebEvent(6000, rrwebCustom, map[string]any{"tag": "error", "payload": map[string]any{"message": "image failed to load"}}),
+		rrwebEvent(7000, rrwebPlugin, map[string]any{"plugin": "rrweb/console@1", "payload": map[string]any{"level": "error", "payload": []any{`"boom"`}}}),
+		// Out of order on purpose: events are sorted by timestamp.
+		rrwebEvent(2000, rrwebIncrementalSnapshot, map[string]any{"source": float64(rrwebSourceInput)}),
+		rrwebEvent(71000, rrwebIncrementalSnapshot, map[string]any{"source": float64(1)}),
+	}
+
+	got := summarizeRRWeb(events, 0)
+
+	if got.DurationMs != 70000 || got.EventCount != 10 {
+		t.Errorf("duration/events = %d/%d, want 70000/10", got.DurationMs, got.EventCount)
+	}
+	wantScreens := []ScreenVisit{{Screen: "/home", StartMs: 0, DurationMs: 4000}, {Screen: "Announcement", StartMs: 4000, DurationMs: 66000}}
+	if !reflect.DeepEqual(got.Screens, wantScreens) {
+		t.Errorf("screens = %+v, want %+v", got.Screens, wantScreens)
+	}
+	if want := (SessionActivity{Taps: 1, Scrolls: 2, Inputs: 1, Errors: 2}); got.Activity != want {
+		t.Errorf("activity = %+v, want %+v", got.Activity, want)
+	}
+	wantTimeline := []string{
+		"0:00 opened /home",
+		"0:01 entered text",
+		"0:02 tapped at (120, 340)",
+		"0:03 scrolled 2 times",
+		"0:04 opened Announcement",
+		"0:05 error: image failed to load",
+		"0:06 console error: boom",
+	}
+	if !reflect.DeepEqual(got.Timeline, wantTimeline) {
+		t.Errorf("timeline = %q, want %q", got.Timeline, wantTimeline)
+	}
+}
+
+func TestSummarizeRRWebTruncates(t *testing.T) {
+	var events []map[string]any
+	for i := 0; i < 10; i++ {
+		events = append(events, rrwebEvent(float64(i*1000), rrwebIncrementalSnapshot, map[string]any{"source": float64(rrwebSourceInput)}))
+	}
+	got := summarizeRRWeb(events, 3)
+	if len(got.Timeline) != 3 || !got.Truncated {
+		t.Errorf("timeline len %d truncated %v, want 3 true", len(got.Timeline), got.Truncated)
+	}
+	if got.Activity.Inputs != 10 {
+		t.Errorf("inputs = %d, want all 10 counted", got.Activity.Inputs)
+	}
+}
+
+func TestSummarizeRRWebEmpty(t *testing.T) {
+	got := summarizeRRWeb(nil, 0)
+	if got.EventCount != 0 || got.Screens == nil || got.Timeline == nil {
+		t.Errorf("empty summary = %+v", got)
+	}
+}
+
+func TestParseSnapshotData(t *testing.T) {
+	events, err := parseSnapshotData(`[{"type":4,"timestamp":1000,"data":{"href":"/home"}},{"type":3,"timestamp":1500,"data":{"source":3}}]`)
+	if err != nil {
+		t.Fatalf("parseSnapshotData error: %v", err)
+	}
+	want := []map[string]any{
+		rrwebEvent(1000, rrwebMeta, map[string]any{"href": "/home"}),
+		rrwebEvent(1500, rrwebIncrementalSnapshot, map[string]any{"source": float64(3)}),
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("parseSnapshotData = %v, want %v", events, want)
+	}
+
+	if events, err := parseSnapshotData(""); err != nil || events != nil {
+		t.Errorf("parseSnapshotData(\"\") = %v, %v, want nil, nil", events, err)
+	}
+	if _, err := parseSnapshotData(`{"type":4}`); err == nil {
+		t.Error("parseSnapshotData accepted a JSON object")
+	}
+}
+
+func TestChunkSnapshots(t *testing.T) {
+	var events []map[string]any
+	for i := 0; i < 5; i++ {
+		events = append(events, rrwebEvent(float64(10000+i*1000), rrwebIncrementalSnapshot, nil))
+	}
+
+	got := chunkSnapshots(events, 2, 2, 0, 0)
+	if got.TotalSnapshots != 5 || got.TotalChunks != 3 || got.Chunk != 2 || len(got.Snapshots) != 2 {
+		t.Errorf("chunk = %+v, want chunk 2 of 3 with 2 snapshots", got)
+	}
+	if got.NextOffset == nil || *got.NextOffset != 4 {
+		t.Errorf("nextOffset = %v, want 4", got.NextOffset)
+	}
+
+	last := chunkSnapshots(events, 4, 2, 0, 0)
+	if len(last.Snapshots) != 1 || last.NextOffset != nil {
+		t.Errorf("last chunk = %+v, want 1 snapshot and no nextOffset", last)
+	}
+
+	past := chunkSnapshots(events, 10, 2, 0, 0)
+	if past.Snapshots == nil || len(past.Snapshots) != 0 {
+		t.Errorf("offset past end = %+v, want empty snapshots", past)
+	}
+}
+
+func TestChunkSnapshotsWindow(t *testing.T) {
+	var events []map[string]any
+	for i := 0; i < 10; i++ {
+		events = append(events, rrwebEvent(float64(10000+i*1000), rrwebIncrementalSnapshot, nil))
+	}
+	got := chunkSnapshots(events, 0, 0, 2000, 4000)
+	if got.TotalSnapshots != 3 || got.TotalChunks != 1 || got.Limit != defaultSnapshotChunk {
+		t.Errorf("window chunk = %+v, want 3 snapshots in 1 chunk", got)
+	}
+	if ts := numberField(got.Snapshots[0], "timestamp"); ts != 12000 {
+		t.Errorf("first snapshot at %v, want 12000", ts)
+	}
+}
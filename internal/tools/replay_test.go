@@ -0,0 +1,47 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"testing"
+
+	pidgrv1 "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1"
+)
+
+func TestFilterRecordings(t *testing.T) {
+	recordings := []*pidgrv1.SessionRecording{
+		{Id: "r1", AnalyticsUserId: "u1", DurationSeconds: 30},
+		{Id: "r2", AnalyticsUserId: "u2", DurationSeconds: 90},
+		{Id: "r3", AnalyticsUserId: "u1", DurationSeconds: 120},
+	}
+	tests := []struct {
+		name          string
+		userID        string
+		minDurationMs int64
+		want          []string
+	}{
+		{"no filters", "", 0, []string{"r1", "r2", "r3"}},
+		{"user", "u1", 0, []string{"r1", "r3"}},
+		{"duration", "", 60000, []string{"r2", "r3"}},
+		{"user and duration", "u1", 60000, []string{"r3"}},
+		{"no match", "u3", 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, rec := range filterRecordings(recordings, tt.userID, tt.minDurationMs) {
+				got = append(got, rec.GetId())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("filterRecordings = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("filterRecordings = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
@@ -5,6 +5,9 @@ package tools
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 
 	"connectrpc.com/connect"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -15,11 +18,33 @@ import (
 
 // ── Input types ─────────────────────────────────────────────────────────────
 
-type ListRolesInput struct{}
+type ListRolesInput struct {
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+	SummaryOnly  bool     `json:"summary_only,omitempty" jsonschema:"Return only the item count, breakdowns by status and other enum fields, and the first few items"`
+}
+
+type ListPermissionsInput struct{}
+
+type GetRoleInput struct {
+	RoleID       string   `json:"role_id" jsonschema:"Role UUID to retrieve"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+}
+
+type ListRoleMembersInput struct {
+	RoleID       string   `json:"role_id" jsonschema:"Role UUID whose assigned users to list"`
+	PageSize     int32    `json:"page_size,omitempty" jsonschema:"Max items per page"`
+	PageToken    string   `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
+	FetchAll     bool     `json:"fetch_all,omitempty" jsonschema:"Fetch every page server-side and return all items together, up to 20 pages; nextPageToken is set if more remain"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+	SummaryOnly  bool     `json:"summary_only,omitempty" jsonschema:"Return only the item count, breakdowns by status and other enum fields, and the first few items"`
+}
 
 type CreateRoleInput struct {
 	Name        string   `json:"name" jsonschema:"Role display name (e.g. Team Lead)"`
-	Permissions []string `json:"permissions" jsonschema:"Permission names (e.g. PERMISSION_CAMPAIGNS_READ or CAMPAIGNS_READ)"`
+	Permissions []string `json:"permissions" jsonschema:"Permission names (e.g. PERMISSION_CAMPAIGNS_READ or CAMPAIGNS_READ); use list_permissions for valid names"`
 }
 
 type UpdateRoleInput struct {
@@ -28,22 +53,86 @@ type UpdateRoleInput struct {
 	Permissions []string `json:"permissions,omitempty" jsonschema:"New permission set (replaces existing)"`
 }
 
+type CloneRoleInput struct {
+	RoleID            string   `json:"role_id" jsonschema:"Role UUID to copy permissions from (system roles can be cloned)"`
+	Name              string   `json:"name" jsonschema:"Display name for the new role"`
+	AddPermissions    []string `json:"add_permissions,omitempty" jsonschema:"Extra permission names to grant on the new role"`
+	RemovePermissions []string `json:"remove_permissions,omitempty" jsonschema:"Permission names to leave out of the new role"`
+}
+
 type DeleteRoleInput struct {
 	RoleID string `json:"role_id" jsonschema:"Role UUID to delete"`
 }
 
+// ── Output types ────────────────────────────────────────────────────────────
+
+// PermissionInfo describes one grantable permission.
+type PermissionInfo struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description,omitempty"`
+	DefaultRoles []string `json:"defaultRoles"`
+}
+
+type ListPermissionsResult struct {
+	Permissions []PermissionInfo `json:"permissions"`
+}
+
 // ── Helpers ─────────────────────────────────────────────────────────────────
 
-func toProtoPermissions(perms []string) []pidgrv1.Permission {
+// permissionDescriptions explains each permission, keyed by enum name.
+var permissionDescriptions = map[string]string{
+	"PERMISSION_CAMPAIGNS_READ":  "View campaigns and their delivery status",
+	"PERMISSION_CAMPAIGNS_WRITE": "Create, update, start, and cancel campaigns",
+	"PERMISSION_TEMPLATES_READ":  "View message templates",
+	"PERMISSION_TEMPLATES_WRITE": "Create and update message templates",
+	"PERMISSION_GROUPS_READ":     "View groups and their members",
+	"PERMISSION_GROUPS_WRITE":    "Create, update, and delete groups and manage group membership",
+	"PERMISSION_TEAMS_ALL_READ":  "View all teams and their members",
+	"PERMISSION_TEAMS_ALL_WRITE": "Create, update, and delete any team and manage team membership",
+	"PERMISSION_MEMBERS_READ":    "View users and their profiles",
+	"PERMISSION_MEMBERS_MANAGE":  "Invite, update, and deactivate users and change their roles",
+	"PERMISSION_ROLES_MANAGE":    "Create, update, and delete roles",
+	"PERMISSION_API_KEYS_MANAGE": "Create, list, and revoke API keys",
+	"PERMISSION_ORG_MANAGE":      "Update organization settings and SSO configuration",
+	"PERMISSION_HEATMAPS_READ":   "Query touch heatmap data",
+	"PERMISSION_REPLAYS_READ":    "List and view session recordings",
+}
+
+// findRole looks up a role by UUID in the organization's role list.
+func findRole(ctx context.Context, c *transport.Clients, roleID string) (*pidgrv1.Role, error) {
+	resp, err := c.Roles.ListRoles(ctx, connect.NewRequest(&pidgrv1.ListRolesRequest{}))
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range resp.Msg.GetRoles() {
+		if role.GetId() == roleID {
+			return role, nil
+		}
+	}
+	return nil, connect.NewError(connect.CodeNotFound, fmt.Errorf("no role with ID %s", roleID))
+}
+
+// toProtoPermissions converts permission names, with or without the
+// PERMISSION_ prefix, to enum values. Unknown names are an InvalidArgument
+// error listing them, so a typo cannot silently drop a permission.
+func toProtoPermissions(perms []string) ([]pidgrv1.Permission, error) {
 	result := make([]pidgrv1.Permission, 0, len(perms))
+	var unknown []string
 	for _, p := range perms {
 		if v, ok := pidgrv1.Permission_value[p]; ok {
 			result = append(result, pidgrv1.Permission(v))
 		} else if v, ok := pidgrv1.Permission_value["PERMISSION_"+p]; ok {
 			result = append(result, pidgrv1.Permission(v))
+		} else {
+			unknown = append(unknown, p)
 		}
 	}
-	return result
+	if len(unknown) > 0 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf(
+			"unknown permissions %s; use list_permissions for valid names", strings.Join(unknown, ", "),
+		))
+	}
+	return result, nil
 }
 
 // ── Registration ────────────────────────────────────────────────────────────
@@ -58,7 +147,93 @@ func registerRoleTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "get_role",
+		Description: "Retrieve a single role with its permission set. Use list_roles to find role UUIDs.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input GetRoleInput) (*mcp.CallToolResult, any, error) {
+		role, err := findRole(ctx, c, input.RoleID)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		r, err := convert.ProtoResult(ctx, role)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "list_role_members",
+		Description: "List the users assigned to a role with pagination, e.g. to check who would be affected before changing the role's permissions. Each page of users is filtered to the role, so a page may hold fewer than page_size members; set fetch_all to see them all. Use list_roles to find role UUIDs.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListRoleMembersInput) (*mcp.CallToolResult, any, error) {
+		if input.RoleID == "" {
+			r, _ := convert.ErrorResult(connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("role_id is required")))
+			return r, nil, nil
+		}
+		msg, err := collectPages(ctx, input.PageSize, input.PageToken, input.FetchAll, func(ctx context.Context, p *pidgrv1.Pagination) (*pidgrv1.ListUsersResponse, string, error) {
+			resp, err := c.Members.ListUsers(ctx, connect.NewRequest(&pidgrv1.ListUsersRequest{
+				Pagination: p,
+			}))
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Msg, resp.Msg.GetPaginationMeta().GetNextPageToken(), nil
+		})
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		msg.Users = userFilter{roleID: input.RoleID}.apply(msg.GetUsers())
+		r, err := convert.ProtoResult(ctx, msg)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "list_permissions",
+		Description: "List every permission that can be granted to roles and API keys, with a description and the default (system) roles that include it. Use these names with create_role, update_role, and create_api_key.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListPermissionsInput) (*mcp.CallToolResult, any, error) {
+		resp, err := c.Roles.ListRoles(ctx, connect.NewRequest(&pidgrv1.ListRolesRequest{}))
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		defaultRoles := make(map[pidgrv1.Permission][]string)
+		for _, role := range resp.Msg.GetRoles() {
+			if !role.GetIsSystem() {
+				continue
+			}
+			for _, p := range role.GetPermissions() {
+				defaultRoles[p] = append(defaultRoles[p], role.GetName())
+			}
+		}
+
+		values := make([]int32, 0, len(pidgrv1.Permission_name))
+		for v := range pidgrv1.Permission_name {
+			if v != 0 {
+				values = append(values, v)
+			}
+		}
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+		result := ListPermissionsResult{Permissions: make([]PermissionInfo, 0, len(values))}
+		for _, v := range values {
+			name := pidgrv1.Permission_name[v]
+			if strings.HasSuffix(name, "_UNSPECIFIED") {
+				continue
+			}
+			roles := defaultRoles[pidgrv1.Permission(v)]
+			if roles == nil {
+				roles = []string{}
+			}
+			result.Permissions = append(result.Permissions, PermissionInfo{
+				Name:         name,
+				Description:  permissionDescriptions[name],
+				DefaultRoles: roles,
+			})
+		}
+		r, err := convert.JSONResult(result)
 		return r, nil, err
 	})
 
@@ -66,15 +241,65 @@ func registerRoleTools(s *mcp.Server, c *transport.Clients) {
 		Name:        "create_role",
 		Description: "Create a new custom role with permissions. Use list_roles first to check if a similar role already exists.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input CreateRoleInput) (*mcp.CallToolResult, any, error) {
+		perms, err := toProtoPermissions(input.Permissions)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
 		resp, err := c.Roles.CreateRole(ctx, connect.NewRequest(&pidgrv1.CreateRoleRequest{
 			Name:        input.Name,
-			Permissions: toProtoPermissions(input.Permissions),
+			Permissions: perms,
 		}))
 		if err != nil {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "clone_role",
+		Description: "Create a new custom role with the same permissions as an existing role, optionally adding or removing permissions (e.g. like Manager but without API key management). Use list_roles to find the source role UUID and list_permissions for permission names.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input CloneRoleInput) (*mcp.CallToolResult, any, error) {
+		add, err := toProtoPermissions(input.AddPermissions)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		removed, err := toProtoPermissions(input.RemovePermissions)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		source, err := findRole(ctx, c, input.RoleID)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		remove := make(map[pidgrv1.Permission]bool)
+		for _, p := range removed {
+			remove[p] = true
+		}
+		seen := make(map[pidgrv1.Permission]bool)
+		var perms []pidgrv1.Permission
+		for _, p := range append(source.GetPermissions(), add...) {
+			if remove[p] || seen[p] {
+				continue
+			}
+			seen[p] = true
+			perms = append(perms, p)
+		}
+
+		resp, err := c.Roles.CreateRole(ctx, connect.NewRequest(&pidgrv1.CreateRoleRequest{
+			Name:        input.Name,
+			Permissions: perms,
+		}))
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -82,16 +307,21 @@ func registerRoleTools(s *mcp.Server, c *transport.Clients) {
 		Name:        "update_role",
 		Description: "Update a role's name and/or permissions. System roles cannot be updated. Use list_roles to find the role UUID.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input UpdateRoleInput) (*mcp.CallToolResult, any, error) {
+		perms, err := toProtoPermissions(input.Permissions)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
 		resp, err := c.Roles.UpdateRole(ctx, connect.NewRequest(&pidgrv1.UpdateRoleRequest{
 			RoleId:      input.RoleID,
 			Name:        input.Name,
-			Permissions: toProtoPermissions(input.Permissions),
+			Permissions: perms,
 		}))
 		if err != nil {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
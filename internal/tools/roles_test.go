@@ -0,0 +1,34 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	pidgrv1 "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1"
+)
+
+func TestToProtoPermissions(t *testing.T) {
+	got, err := toProtoPermissions([]string{"PERMISSION_CAMPAIGNS_READ", "MEMBERS_READ"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []pidgrv1.Permission{pidgrv1.Permission_PERMISSION_CAMPAIGNS_READ, pidgrv1.Permission_PERMISSION_MEMBERS_READ}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("toProtoPermissions = %v, want %v", got, want)
+	}
+}
+
+func TestToProtoPermissionsUnknown(t *testing.T) {
+	_, err := toProtoPermissions([]string{"CAMPAIGNS_READ", "CAMPAIGN_WRITE", "MEMBERS_MANGE"})
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Fatalf("expected an InvalidArgument error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "CAMPAIGN_WRITE, MEMBERS_MANGE") {
+		t.Errorf("expected the unknown names in the error, got %v", err)
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"bytes"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	// Inline and image links in Markdown: [text](url) and ![alt](url). The
+	// URL may follow whitespace, as in [text]( url).
+	markdownLinkRe = regexp.MustCompile(`(!?\[[^\]]*\]\(\s*)([^)\s]+)`)
+	// Reference-style link definitions in Markdown: [id]: url.
+	markdownRefRe = regexp.MustCompile(`(?m)^( {0,3}\[[^\]]+\]:[ \t]*)(\S+)`)
+	// Tags that indicate the content is HTML rather than Markdown.
+	htmlTagRe = regexp.MustCompile(`(?i)<(html|body|div|p|table|a|br|span|h[1-6]|img|ul|ol|li)\b[^>]*>`)
+	// CSS constructs that can execute script in some mail clients, checked
+	// in style attributes.
+	unsafeStyleRe = regexp.MustCompile(`(?i)expression\s*\(|javascript:|vbscript:|behavior\s*:|-moz-binding`)
+)
+
+// allowedElements are the tags kept by sanitizeHTML. Anything else is
+// dropped, but its text content is kept unless listed in droppedElements.
+var allowedElements = map[string]bool{
+	"a": true, "abbr": true, "b": true, "blockquote": true, "body": true,
+	"br": true, "caption": true, "center": true, "code": true, "col": true,
+	"colgroup": true, "div": true, "em": true, "font": true, "h1": true,
+	"h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "head": true,
+	"hr": true, "html": true, "i": true, "img": true, "li": true, "ol": true,
+	"p": true, "pre": true, "s": true, "small": true, "span": true,
+	"strong": true, "sub": true, "sup": true, "table": true,
+	"tbody": true, "td": true, "tfoot": true, "th": true, "thead": true,
+	"title": true, "tr": true, "u": true, "ul": true,
+}
+
+// droppedElements are removed together with everything inside them. This
+// includes every element whose content the tokenizer reads as raw text,
+// since that text could hold markup that is live once the element is gone.
+// Style sheets are dropped too: filtering CSS for script is not reliable.
+var droppedElements = map[string]bool{
+	"applet": true, "frameset": true, "iframe": true, "math": true,
+	"noembed": true, "noframes": true, "noscript": true, "object": true,
+	"plaintext": true, "script": true, "style": true, "svg": true,
+	"template": true, "textarea": true, "xmp": true,
+}
+
+// allowedAttrs are the attributes kept on allowed elements. URL-valued
+// attributes are listed in urlAttrs and checked with sanitizeURL.
+var allowedAttrs = map[string]bool{
+	"align": true, "alt": true, "bgcolor": true, "border": true,
+	"cellpadding": true, "cellspacing": true, "class": true, "color": true,
+	"colspan": true, "dir": true, "face": true, "height": true, "href": true,
+	"id": true, "lang": true, "name": true, "rowspan": true, "size": true,
+	"src": true, "style": true, "target": true, "title": true, "valign": true,
+	"width": true,
+}
+
+var urlAttrs = map[string]bool{"href": true, "src": true}
+
+// looksLikeHTML reports whether content appears to be an HTML document or fragment.
+func looksLikeHTML(content string) bool {
+	return htmlTagRe.MatchString(content)
+}
+
+// sanitizeHTML keeps only allowlisted elements and attributes, drops
+// comments and active content, and resolves relative links against baseURL.
+// Attribute values are checked after entity decoding.
+func sanitizeHTML(content, baseURL string) string {
+	return strings.TrimSpace(filterHTML(normalizeNewlines(content), baseURL))
+}
+
+// sanitizeMarkdown filters embedded HTML like sanitizeHTML and resolves
+// relative link targets against baseURL. Links with unsafe schemes are
+// replaced with "#".
+func sanitizeMarkdown(content, baseURL string) string {
+	out := filterHTML(normalizeNewlines(content), baseURL)
+	rewrite := func(re *regexp.Regexp) func(string) string {
+		return func(link string) string {
+			m := re.FindStringSubmatch(link)
+			resolved, ok := sanitizeURL(m[2], baseURL)
+			if !ok {
+				resolved = "#"
+			}
+			return m[1] + resolved
+		}
+	}
+	out = markdownLinkRe.ReplaceAllStringFunc(out, rewrite(markdownLinkRe))
+	out = markdownRefRe.ReplaceAllStringFunc(out, rewrite(markdownRefRe))
+	return strings.TrimSpace(out)
+}
+
+// filterHTML re-emits content token by token, keeping text as written and
+// rebuilding allowed tags from their decoded attributes.
+func filterHTML(content, baseURL string) string {
+	var out bytes.Buffer
+	z := html.NewTokenizer(strings.NewReader(content))
+	// depth counts open droppedElements; their content is skipped.
+	depth := 0
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			// io.EOF, or a read error from a strings.Reader, which cannot happen.
+			break
+		}
+		switch tt {
+		case html.TextToken:
+			if depth == 0 {
+				out.Write(z.Raw())
+			}
+		case html.DoctypeToken:
+			if depth == 0 {
+				out.WriteString(z.Token().String())
+			}
+		case html.CommentToken:
+			// Comments can hide conditional markup for some mail clients.
+		case html.StartTagToken, html.SelfClosingTagToken, html.EndTagToken:
+			tok := z.Token()
+			if droppedElements[tok.Data] {
+				switch {
+				case tt == html.StartTagToken:
+					depth++
+				case tt == html.EndTagToken && depth > 0:
+					depth--
+				}
+				continue
+			}
+			if depth > 0 || !allowedElements[tok.Data] {
+				continue
+			}
+			if tt == html.EndTagToken {
+				out.WriteString("</" + tok.Data + ">")
+				continue
+			}
+			out.WriteString(renderTag(tok, baseURL))
+		}
+	}
+	return out.String()
+}
+
+// renderTag writes a start tag with only its safe attributes.
+func renderTag(tok html.Token, baseURL string) string {
+	var b strings.Builder
+	b.WriteString("<" + tok.Data)
+	for _, attr := range tok.Attr {
+		key := strings.ToLower(attr.Key)
+		if attr.Namespace != "" || !allowedAttrs[key] {
+			continue
+		}
+		val := attr.Val
+		if urlAttrs[key] {
+			resolved, ok := sanitizeURL(val, baseURL)
+			if !ok {
+				continue
+			}
+			val = resolved
+		}
+		if key == "style" && unsafeStyleRe.MatchString(val) {
+			continue
+		}
+		b.WriteString(" " + key + `="` + html.EscapeString(val) + `"`)
+	}
+	if tok.Type == html.SelfClosingTagToken {
+		b.WriteString(" /")
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+// safeSchemes are the URL schemes kept by sanitizeURL.
+var safeSchemes = map[string]bool{"http": true, "https": true, "mailto": true, "tel": true}
+
+// sanitizeURL keeps only http(s), mailto, tel and raster data:image URLs and
+// resolves relative URLs against baseURL. Fragments and template placeholders
+// are returned unchanged. The bool is false if the URL must be dropped.
+func sanitizeURL(raw, baseURL string) (string, bool) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "{{") {
+		return trimmed, true
+	}
+	// Browsers decode entities and ignore whitespace and control characters
+	// inside the scheme, so check the scheme the way they will read it.
+	decoded := strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, html.UnescapeString(trimmed))
+	u, err := url.Parse(decoded)
+	if err != nil {
+		return "", false
+	}
+	if scheme := strings.ToLower(u.Scheme); scheme != "" && !safeSchemes[scheme] {
+		if scheme != "data" || !isRasterDataURL(decoded) {
+			return "", false
+		}
+		return trimmed, true
+	}
+	if u.IsAbs() || baseURL == "" {
+		return trimmed, true
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil || !base.IsAbs() {
+		return trimmed, true
+	}
+	rel, err := url.Parse(trimmed)
+	if err != nil {
+		return "", false
+	}
+	return base.ResolveReference(rel).String(), true
+}
+
+// isRasterDataURL reports whether a data: URL holds a non-scriptable image.
+func isRasterDataURL(u string) bool {
+	lower := strings.ToLower(u)
+	for _, prefix := range []string{"data:image/png", "data:image/gif", "data:image/jpeg", "data:image/webp"} {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeNewlines converts CRLF and lone CR line endings to LF.
+func normalizeNewlines(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\r", "\n")
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLooksLikeHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"html document", "<html><body>Hi</body></html>", true},
+		{"fragment", "<p>Hello {{first_name}}</p>", true},
+		{"markdown", "# Welcome\n\nHello **there**", false},
+		{"markdown with angle brackets", "Use a -> b when x < y", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := looksLikeHTML(tt.content); got != tt.want {
+				t.Errorf("looksLikeHTML(%q) = %v, want %v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeHTML(t *testing.T) {
+	in := "<div onclick=\"steal()\">\r\n<script>alert(1)</script>" +
+		`<a href="javascript:alert(1)">bad</a>` +
+		`<a href='/news'>news</a>` +
+		`<img src="img/logo.png">` +
+		`<a href="https://example.com/x">abs</a>` +
+		`<a href="{{link}}">var</a>` +
+		`<iframe src="https://evil.example"></iframe></div>`
+
+	got := sanitizeHTML(in, "https://old.example.com/mail/")
+
+	for _, banned := range []string{"<script", "alert(1)", "onclick", "javascript:", "<iframe", "\r"} {
+		if strings.Contains(got, banned) {
+			t.Errorf("sanitized output still contains %q: %s", banned, got)
+		}
+	}
+	for _, want := range []string{
+		`href="https://old.example.com/news"`,
+		`src="https://old.example.com/mail/img/logo.png"`,
+		`href="https://example.com/x"`,
+		`href="{{link}}"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("sanitized output missing %q: %s", want, got)
+		}
+	}
+}
+
+func TestSanitizeHTMLWithoutBaseURL(t *testing.T) {
+	got := sanitizeHTML(`<a href="/news">news</a>`, "")
+	if !strings.Contains(got, `href="/news"`) {
+		t.Errorf("relative link should be kept as-is without base URL: %s", got)
+	}
+}
+
+func TestSanitizeMarkdown(t *testing.T) {
+	in := "# Hi\n\n[docs](guide.html) ![logo](/logo.png) [x](javascript:alert(1)) [y](#top)\n<script>bad()</script>"
+
+	got := sanitizeMarkdown(in, "https://old.example.com/mail/")
+
+	for _, want := range []string{
+		"[docs](https://old.example.com/mail/guide.html)",
+		"![logo](https://old.example.com/logo.png)",
+		"[x](#",
+		"[y](#top)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("sanitized output missing %q: %s", want, got)
+		}
+	}
+	if strings.Contains(got, "<script") || strings.Contains(got, "javascript:") {
+		t.Errorf("sanitized output still contains active content: %s", got)
+	}
+}
+
+func TestSanitizeHTMLBypasses(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		banned []string
+		want   string
+	}{
+		{
+			name:   "svg onload without space",
+			in:     `<p>hi</p><svg/onload=alert(1)>`,
+			banned: []string{"<svg", "onload", "alert(1)"},
+			want:   "<p>hi</p>",
+		},
+		{
+			name:   "entity-encoded javascript href",
+			in:     `<a href="jav&#x61;script:alert(1)">x</a>`,
+			banned: []string{"href", "script:", "&#x61;"},
+			want:   "<a>x</a>",
+		},
+		{
+			name:   "form action",
+			in:     `<form action="javascript:alert(1)"><p>Sign up</p></form>`,
+			banned: []string{"<form", "action", "javascript:"},
+			want:   "<p>Sign up</p>",
+		},
+		{
+			name:   "meta refresh",
+			in:     `<head><meta http-equiv="refresh" content="0;url=javascript:alert(1)"></head>`,
+			banned: []string{"<meta", "refresh", "javascript:"},
+			want:   "<head></head>",
+		},
+		{
+			name:   "whitespace in scheme",
+			in:     "<a href=\"java\tscript:alert(1)\">x</a>",
+			banned: []string{"href", "script:"},
+			want:   "<a>x</a>",
+		},
+		{
+			name:   "style expression",
+			in:     `<div style="width: expression(alert(1))">x</div>`,
+			banned: []string{"style", "expression"},
+			want:   "<div>x</div>",
+		},
+		{
+			name:   "markup in xmp",
+			in:     `<p>a</p><xmp><script>alert(1)</script></xmp>`,
+			banned: []string{"<script", "alert(1)", "xmp"},
+			want:   "<p>a</p>",
+		},
+		{
+			name:   "markup in noembed",
+			in:     `<p>a</p><noembed><img src=x onerror=alert(1)></noembed>`,
+			banned: []string{"<img", "onerror", "noembed"},
+			want:   "<p>a</p>",
+		},
+		{
+			name:   "markup in noframes",
+			in:     `<p>a</p><noframes><script>alert(1)</script></noframes>`,
+			banned: []string{"<script", "alert(1)", "noframes"},
+			want:   "<p>a</p>",
+		},
+		{
+			name:   "plaintext to end of input",
+			in:     `<p>a</p><plaintext><script>alert(1)</script>`,
+			banned: []string{"<script", "alert(1)", "plaintext"},
+			want:   "<p>a</p>",
+		},
+		{
+			name:   "style sheet",
+			in:     `<style>p { background: url(javascript:alert(1)) }</style><p>a</p>`,
+			banned: []string{"<style", "javascript:", "background"},
+			want:   "<p>a</p>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sanitizeHTML(tt.in, "")
+			for _, banned := range tt.banned {
+				if strings.Contains(got, banned) {
+					t.Errorf("sanitized output still contains %q: %s", banned, got)
+				}
+			}
+			if got != tt.want {
+				t.Errorf("sanitizeHTML(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeMarkdownEncodedLink(t *testing.T) {
+	got := sanitizeMarkdown("[x](jav&#x61;script:alert(1))\n\n[ref]: javascript:alert(1)\n<svg/onload=alert(1)>\n[y]( javascript:alert(1))\n![z](\tjavascript:alert(1))", "")
+	for _, banned := range []string{"script:", "<svg", "onload"} {
+		if strings.Contains(got, banned) {
+			t.Errorf("sanitized output still contains %q: %s", banned, got)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// outputSchema returns the published output schema for a typed tool result.
+// Output types are a compatibility contract: fields may be added, but are
+// never renamed, retyped, or removed. The schema marks no field as required
+// and allows extra properties, so results trimmed by the fields option or
+// reshaped by summary_only still conform.
+func outputSchema[T any]() *jsonschema.Schema {
+	schema, err := jsonschema.For[T](nil)
+	if err != nil {
+		panic("output schema: " + err.Error())
+	}
+	relaxSchema(schema)
+	return schema
+}
+
+func relaxSchema(s *jsonschema.Schema) {
+	if s == nil {
+		return
+	}
+	s.Required = nil
+	s.AdditionalProperties = nil
+	for _, p := range s.Properties {
+		relaxSchema(p)
+	}
+	relaxSchema(s.Items)
+}
@@ -0,0 +1,43 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOutputSchemaIsRelaxed(t *testing.T) {
+	schema := outputSchema[ListCampaignsResult]()
+	if len(schema.Required) != 0 || schema.AdditionalProperties != nil {
+		t.Errorf("expected no required fields and open properties, got %+v", schema)
+	}
+	item := schema.Properties["campaigns"].Items
+	if item == nil || item.Properties["status"] == nil {
+		t.Fatal("expected campaign item properties in schema")
+	}
+	if len(item.Required) != 0 || item.AdditionalProperties != nil {
+		t.Errorf("expected nested schemas to be relaxed, got %+v", item)
+	}
+
+	// A summary_only result adds a field and omits others; it must validate.
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	var result any
+	_ = json.Unmarshal([]byte(`{"summary":{"count":1},"campaigns":[{"id":"c1"}]}`), &result)
+	if err := resolved.Validate(result); err != nil {
+		t.Errorf("expected summary result to validate: %v", err)
+	}
+}
+
+func TestPageResult(t *testing.T) {
+	if pageResult("") != nil {
+		t.Error("expected no pagination block on the last page")
+	}
+	if p := pageResult("t2"); p == nil || p.NextPageToken != "t2" {
+		t.Errorf("unexpected pagination block %+v", p)
+	}
+}
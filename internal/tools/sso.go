@@ -0,0 +1,100 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SsoMappingOutcome is the result of applying one mapping to sample claims.
+type SsoMappingOutcome struct {
+	IdpClaim     string `json:"idpClaim"`
+	ProfileField string `json:"profileField"`
+	Value        string `json:"value,omitempty"`
+	Found        bool   `json:"found"`
+}
+
+// SsoMappingTestResult shows how a set of mappings would populate a profile.
+type SsoMappingTestResult struct {
+	Source         string              `json:"source"`
+	Profile        map[string]string   `json:"profile"`
+	Mappings       []SsoMappingOutcome `json:"mappings"`
+	UnmappedClaims []string            `json:"unmappedClaims"`
+}
+
+// applySsoMappings evaluates mappings against IdP claims. Claim names may use
+// dots to reach into nested objects (e.g. "address.locality"). When several
+// mappings target the same profile field, the last one with a value wins.
+func applySsoMappings(claims map[string]any, mappings []SsoMappingInput) SsoMappingTestResult {
+	result := SsoMappingTestResult{
+		Profile:        map[string]string{},
+		Mappings:       make([]SsoMappingOutcome, 0, len(mappings)),
+		UnmappedClaims: []string{},
+	}
+	used := make(map[string]bool)
+	for _, m := range mappings {
+		outcome := SsoMappingOutcome{IdpClaim: m.IdpClaim, ProfileField: m.ProfileField}
+		if v, ok := lookupClaim(claims, m.IdpClaim); ok {
+			outcome.Found = true
+			outcome.Value = claimString(v)
+			result.Profile[m.ProfileField] = outcome.Value
+			if _, literal := claims[m.IdpClaim]; literal {
+				used[m.IdpClaim] = true
+			} else {
+				head, _, _ := strings.Cut(m.IdpClaim, ".")
+				used[head] = true
+			}
+		}
+		result.Mappings = append(result.Mappings, outcome)
+	}
+	for name := range claims {
+		if !used[name] {
+			result.UnmappedClaims = append(result.UnmappedClaims, name)
+		}
+	}
+	sort.Strings(result.UnmappedClaims)
+	return result
+}
+
+// lookupClaim resolves a claim name, trying the literal key first so claims
+// that contain dots (e.g. URIs) still match.
+func lookupClaim(claims map[string]any, name string) (any, bool) {
+	if v, ok := claims[name]; ok {
+		return v, true
+	}
+	head, rest, ok := strings.Cut(name, ".")
+	if !ok {
+		return nil, false
+	}
+	nested, isMap := claims[head].(map[string]any)
+	if !isMap {
+		return nil, false
+	}
+	return lookupClaim(nested, rest)
+}
+
+// claimString renders a claim value as a profile field string. Lists are
+// joined with commas; objects are rendered as JSON.
+func claimString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case []any:
+		parts := make([]string, len(t))
+		for i, e := range t {
+			parts[i] = claimString(e)
+		}
+		return strings.Join(parts, ",")
+	case map[string]any:
+		b, _ := json.Marshal(t)
+		return string(b)
+	default:
+		return fmt.Sprint(t)
+	}
+}
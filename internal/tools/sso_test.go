@@ -0,0 +1,58 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplySsoMappings(t *testing.T) {
+	claims := map[string]any{
+		"given_name": "Maria",
+		"dept":       "Finance",
+		"groups":     []any{"staff", "finance"},
+		"address":    map[string]any{"locality": "Berlin"},
+		"employee":   float64(1042),
+		"sub":        "abc123",
+	}
+	mappings := []SsoMappingInput{
+		{IdpClaim: "given_name", ProfileField: "first_name"},
+		{IdpClaim: "dept", ProfileField: "department"},
+		{IdpClaim: "groups", ProfileField: "teams"},
+		{IdpClaim: "address.locality", ProfileField: "location"},
+		{IdpClaim: "employee", ProfileField: "employee_id"},
+		{IdpClaim: "title", ProfileField: "title"},
+	}
+
+	got := applySsoMappings(claims, mappings)
+
+	wantProfile := map[string]string{
+		"first_name":  "Maria",
+		"department":  "Finance",
+		"teams":       "staff,finance",
+		"location":    "Berlin",
+		"employee_id": "1042",
+	}
+	if !reflect.DeepEqual(got.Profile, wantProfile) {
+		t.Errorf("profile = %v, want %v", got.Profile, wantProfile)
+	}
+	if last := got.Mappings[len(got.Mappings)-1]; last.Found {
+		t.Errorf("missing claim reported as found: %+v", last)
+	}
+	if want := []string{"sub"}; !reflect.DeepEqual(got.UnmappedClaims, want) {
+		t.Errorf("unmapped claims = %v, want %v", got.UnmappedClaims, want)
+	}
+}
+
+func TestLookupClaimLiteralDottedName(t *testing.T) {
+	claims := map[string]any{"https://example.com/dept": "Sales"}
+	if v, ok := lookupClaim(claims, "https://example.com/dept"); !ok || v != "Sales" {
+		t.Errorf("lookupClaim = %v, %v; want Sales, true", v, ok)
+	}
+	got := applySsoMappings(claims, []SsoMappingInput{{IdpClaim: "https://example.com/dept", ProfileField: "department"}})
+	if len(got.UnmappedClaims) != 0 {
+		t.Errorf("unmapped claims = %v, want none", got.UnmappedClaims)
+	}
+}
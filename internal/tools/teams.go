@@ -5,6 +5,7 @@ package tools
 
 import (
 	"context"
+	"strings"
 
 	"connectrpc.com/connect"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -21,12 +22,18 @@ type CreateTeamInput struct {
 }
 
 type GetTeamInput struct {
-	TeamID string `json:"team_id" jsonschema:"Team UUID"`
+	TeamID       string   `json:"team_id" jsonschema:"Team UUID"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
 }
 
 type ListTeamsInput struct {
-	PageSize  int32  `json:"page_size,omitempty" jsonschema:"Max items per page"`
-	PageToken string `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
+	PageSize     int32    `json:"page_size,omitempty" jsonschema:"Max items per page"`
+	PageToken    string   `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
+	FetchAll     bool     `json:"fetch_all,omitempty" jsonschema:"Fetch every page server-side and return all items together, up to 20 pages; nextPageToken is set if more remain"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+	SummaryOnly  bool     `json:"summary_only,omitempty" jsonschema:"Return only the item count, breakdowns by status and other enum fields, and the first few items"`
 }
 
 type UpdateTeamInput struct {
@@ -41,7 +48,7 @@ type DeleteTeamInput struct {
 
 type AddTeamMembersInput struct {
 	TeamID  string   `json:"team_id" jsonschema:"Team UUID"`
-	UserIDs []string `json:"user_ids" jsonschema:"User UUIDs to add (max 100)"`
+	UserIDs []string `json:"user_ids" jsonschema:"User UUIDs to add (max 1000, sent in chunks of 100)"`
 }
 
 type RemoveTeamMembersInput struct {
@@ -50,9 +57,118 @@ type RemoveTeamMembersInput struct {
 }
 
 type ListTeamMembersInput struct {
-	TeamID    string `json:"team_id" jsonschema:"Team UUID"`
-	PageSize  int32  `json:"page_size,omitempty" jsonschema:"Max items per page"`
-	PageToken string `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
+	TeamID       string   `json:"team_id" jsonschema:"Team UUID"`
+	PageSize     int32    `json:"page_size,omitempty" jsonschema:"Max items per page"`
+	PageToken    string   `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
+	FetchAll     bool     `json:"fetch_all,omitempty" jsonschema:"Fetch every page server-side and return all items together, up to 20 pages; nextPageToken is set if more remain"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+	SummaryOnly  bool     `json:"summary_only,omitempty" jsonschema:"Return only the item count, breakdowns by status and other enum fields, and the first few items"`
+}
+
+type GetUserTeamMembershipsInput struct {
+	UserIDs      []string `json:"user_ids" jsonschema:"User UUIDs to look up (max 200)"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+}
+
+// ── Output types ────────────────────────────────────────────────────────────
+
+type TeamRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type UserTeamMembership struct {
+	UserID string    `json:"userId"`
+	Teams  []TeamRef `json:"teams"`
+}
+
+type GetUserTeamMembershipsResult struct {
+	Memberships []UserTeamMembership `json:"memberships"`
+}
+
+type SearchTeamsInput struct {
+	Query        string   `json:"query,omitempty" jsonschema:"Case-insensitive substring to match against team names"`
+	MinMembers   int32    `json:"min_members,omitempty" jsonschema:"Only teams with at least this many members"`
+	Limit        int32    `json:"limit,omitempty" jsonschema:"Max teams to return (default 20, max 100)"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+	SummaryOnly  bool     `json:"summary_only,omitempty" jsonschema:"Return only the item count, breakdowns by status and other enum fields, and the first few items"`
+}
+
+// ── Helpers ─────────────────────────────────────────────────────────────────
+
+// listAllTeams fetches every team in the organization.
+func listAllTeams(ctx context.Context, c *transport.Clients) ([]*pidgrv1.Team, error) {
+	return fetchAll(ctx, func(ctx context.Context, p *pidgrv1.Pagination) ([]*pidgrv1.Team, string, error) {
+		resp, err := c.Teams.ListTeams(ctx, connect.NewRequest(&pidgrv1.ListTeamsRequest{
+			Pagination: p,
+		}))
+		if err != nil {
+			return nil, "", err
+		}
+		return resp.Msg.GetTeams(), resp.Msg.GetPaginationMeta().GetNextPageToken(), nil
+	})
+}
+
+// listAllTeamMemberIDs fetches the user UUIDs of every member of a team.
+func listAllTeamMemberIDs(ctx context.Context, c *transport.Clients, teamID string) ([]string, error) {
+	return fetchAll(ctx, func(ctx context.Context, p *pidgrv1.Pagination) ([]string, string, error) {
+		resp, err := c.Teams.ListTeamMembers(ctx, connect.NewRequest(&pidgrv1.ListTeamMembersRequest{
+			TeamId:     teamID,
+			Pagination: p,
+		}))
+		if err != nil {
+			return nil, "", err
+		}
+		ids := make([]string, len(resp.Msg.GetUsers()))
+		for i, u := range resp.Msg.GetUsers() {
+			ids[i] = u.GetId()
+		}
+		return ids, resp.Msg.GetPaginationMeta().GetNextPageToken(), nil
+	})
+}
+
+// userTeamMemberships returns the teams each of userIDs belongs to, in the
+// order given. TeamService has no per-user lookup, so it lists the members
+// of every team.
+func userTeamMemberships(ctx context.Context, c *transport.Clients, userIDs []string) (GetUserTeamMembershipsResult, error) {
+	teams, err := listAllTeams(ctx, c)
+	if err != nil {
+		return GetUserTeamMembershipsResult{}, err
+	}
+	byUser := make(map[string][]TeamRef, len(userIDs))
+	for _, id := range userIDs {
+		byUser[id] = []TeamRef{}
+	}
+	for _, t := range teams {
+		memberIDs, err := listAllTeamMemberIDs(ctx, c, t.GetId())
+		if err != nil {
+			return GetUserTeamMembershipsResult{}, err
+		}
+		for _, id := range memberIDs {
+			if refs, ok := byUser[id]; ok {
+				byUser[id] = append(refs, TeamRef{ID: t.GetId(), Name: t.GetName()})
+			}
+		}
+	}
+	result := GetUserTeamMembershipsResult{Memberships: make([]UserTeamMembership, len(userIDs))}
+	for i, id := range userIDs {
+		result.Memberships[i] = UserTeamMembership{UserID: id, Teams: byUser[id]}
+	}
+	return result, nil
+}
+
+// addTeamMembersChunked adds ids to a team in backend-sized chunks.
+func addTeamMembersChunked(ctx context.Context, c *transport.Clients, teamID string, ids []string, progress progressFunc) ([]string, []RowFailure) {
+	return applyInChunks(ctx, ids, func(ctx context.Context, chunk []string) error {
+		_, err := c.Teams.AddTeamMembers(ctx, connect.NewRequest(&pidgrv1.AddTeamMembersRequest{
+			TeamId:  teamID,
+			UserIds: chunk,
+		}))
+		return err
+	}, progress)
 }
 
 // ── Registration ────────────────────────────────────────────────────────────
@@ -70,7 +186,7 @@ func registerTeamTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -85,7 +201,7 @@ func registerTeamTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -93,17 +209,20 @@ func registerTeamTools(s *mcp.Server, c *transport.Clients) {
 		Name:        "list_teams",
 		Description: "List teams in the organization with pagination. Call this first to discover team UUIDs before using other team tools.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListTeamsInput) (*mcp.CallToolResult, any, error) {
-		resp, err := c.Teams.ListTeams(ctx, connect.NewRequest(&pidgrv1.ListTeamsRequest{
-			Pagination: &pidgrv1.Pagination{
-				PageSize:  clampPageSize(input.PageSize),
-				PageToken: input.PageToken,
-			},
-		}))
+		msg, err := collectPages(ctx, input.PageSize, input.PageToken, input.FetchAll, func(ctx context.Context, p *pidgrv1.Pagination) (*pidgrv1.ListTeamsResponse, string, error) {
+			resp, err := c.Teams.ListTeams(ctx, connect.NewRequest(&pidgrv1.ListTeamsRequest{
+				Pagination: p,
+			}))
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Msg, resp.Msg.GetPaginationMeta().GetNextPageToken(), nil
+		})
 		if err != nil {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, msg)
 		return r, nil, err
 	})
 
@@ -120,7 +239,7 @@ func registerTeamTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -140,12 +259,23 @@ func registerTeamTools(s *mcp.Server, c *transport.Clients) {
 
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "add_team_members",
-		Description: "Add users to a team (idempotent). Use list_teams to find the team UUID and list_users to find user UUIDs.",
+		Description: "Add users to a team (idempotent). Lists over 100 users are split into chunks of 100 that run concurrently, and an aggregate result with any per-chunk failures is returned. Use list_teams to find the team UUID and list_users to find user UUIDs.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input AddTeamMembersInput) (*mcp.CallToolResult, any, error) {
-		if err := validateBatchSize(input.UserIDs, 100); err != nil {
+		if err := validateBatchSize(input.UserIDs, maxBatchSize); err != nil {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
+		if len(input.UserIDs) > membershipChunkSize {
+			added, failures := addTeamMembersChunked(ctx, c, input.TeamID, input.UserIDs, toolProgress(ctx, req))
+			r, err := convert.JSONResult(ChunkedMembershipResult{
+				TeamID:    input.TeamID,
+				Requested: len(input.UserIDs),
+				Added:     len(added),
+				Chunks:    len(chunkIDs(input.UserIDs, membershipChunkSize)),
+				Failures:  failures,
+			})
+			return r, nil, err
+		}
 		resp, err := c.Teams.AddTeamMembers(ctx, connect.NewRequest(&pidgrv1.AddTeamMembersRequest{
 			TeamId:  input.TeamID,
 			UserIds: input.UserIDs,
@@ -154,7 +284,7 @@ func registerTeamTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -174,7 +304,7 @@ func registerTeamTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -182,18 +312,66 @@ func registerTeamTools(s *mcp.Server, c *transport.Clients) {
 		Name:        "list_team_members",
 		Description: "List members of a team with pagination. Use list_teams to find the team UUID.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input ListTeamMembersInput) (*mcp.CallToolResult, any, error) {
-		resp, err := c.Teams.ListTeamMembers(ctx, connect.NewRequest(&pidgrv1.ListTeamMembersRequest{
-			TeamId: input.TeamID,
-			Pagination: &pidgrv1.Pagination{
-				PageSize:  clampPageSize(input.PageSize),
-				PageToken: input.PageToken,
-			},
-		}))
+		msg, err := collectPages(ctx, input.PageSize, input.PageToken, input.FetchAll, func(ctx context.Context, p *pidgrv1.Pagination) (*pidgrv1.ListTeamMembersResponse, string, error) {
+			resp, err := c.Teams.ListTeamMembers(ctx, connect.NewRequest(&pidgrv1.ListTeamMembersRequest{
+				TeamId:     input.TeamID,
+				Pagination: p,
+			}))
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Msg, resp.Msg.GetPaginationMeta().GetNextPageToken(), nil
+		})
 		if err != nil {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, msg)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "get_user_team_memberships",
+		Description: "Get team memberships for a batch of users. Lists the members of every team, so it is slower in organizations with many teams. Use list_users to find user UUIDs.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input GetUserTeamMembershipsInput) (*mcp.CallToolResult, any, error) {
+		if err := validateBatchSize(input.UserIDs, 200); err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		result, err := userTeamMemberships(ctx, c, input.UserIDs)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		r, err := convert.JSONResult(result)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "search_teams",
+		Description: "Find teams by name substring and minimum member count without paginating through list_teams.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input SearchTeamsInput) (*mcp.CallToolResult, any, error) {
+		teams, err := listAllTeams(ctx, c)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		query := strings.ToLower(input.Query)
+		limit := int(clampPageSize(input.Limit))
+		var matches []*pidgrv1.Team
+		for _, t := range teams {
+			if !strings.Contains(strings.ToLower(t.GetName()), query) {
+				continue
+			}
+			if t.GetMemberCount() < input.MinMembers {
+				continue
+			}
+			matches = append(matches, t)
+			if len(matches) == limit {
+				break
+			}
+		}
+		r, err := convert.ProtoResult(ctx, &pidgrv1.ListTeamsResponse{Teams: matches})
 		return r, nil, err
 	})
 }
@@ -5,6 +5,7 @@ package tools
 
 import (
 	"context"
+	"strings"
 
 	"connectrpc.com/connect"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -38,14 +39,47 @@ type UpdateTemplateInput struct {
 }
 
 type GetTemplateInput struct {
-	TemplateID string `json:"template_id" jsonschema:"Template UUID to retrieve"`
-	Version    int32  `json:"version,omitempty" jsonschema:"Version to retrieve (0 = latest)"`
+	TemplateID   string   `json:"template_id" jsonschema:"Template UUID to retrieve"`
+	Version      int32    `json:"version,omitempty" jsonschema:"Version to retrieve (0 = latest)"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
 }
 
 type ListTemplatesInput struct {
-	PageSize  int32  `json:"page_size,omitempty" jsonschema:"Max items per page"`
-	PageToken string `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
-	Type      string `json:"type,omitempty" jsonschema:"Filter by template type: MARKDOWN, RICH, or HTML"`
+	PageSize     int32    `json:"page_size,omitempty" jsonschema:"Max items per page"`
+	PageToken    string   `json:"page_token,omitempty" jsonschema:"Pagination token from previous response"`
+	FetchAll     bool     `json:"fetch_all,omitempty" jsonschema:"Fetch every page server-side and return all items together, up to 20 pages; nextPageToken is set if more remain"`
+	Type         string   `json:"type,omitempty" jsonschema:"Filter by template type: MARKDOWN, RICH, or HTML"`
+	Fields       []string `json:"fields,omitempty" jsonschema:"Only return these fields (e.g. id, name); nested paths use dots"`
+	OutputFormat string   `json:"output_format,omitempty" jsonschema:"Text format of the result: json (default), markdown (tables), yaml, or ndjson (list items as an attachment)"`
+	SummaryOnly  bool     `json:"summary_only,omitempty" jsonschema:"Return only the item count, breakdowns by status and other enum fields, and the first few items"`
+}
+
+type ImportTemplateInput struct {
+	Name      string                  `json:"name" jsonschema:"Template name (max 200 chars)"`
+	Title     string                  `json:"title" jsonschema:"User-facing title shown as message subject (max 200 chars)"`
+	Content   string                  `json:"content" jsonschema:"Raw HTML or Markdown content to import"`
+	Format    string                  `json:"format,omitempty" jsonschema:"Source format: HTML or MARKDOWN (inferred from content when omitted)"`
+	BaseURL   string                  `json:"base_url,omitempty" jsonschema:"Absolute URL used to resolve relative links and image sources"`
+	Variables []TemplateVariableInput `json:"variables,omitempty" jsonschema:"Variables available for substitution"`
+}
+
+type GetTemplateUsageInput struct {
+	TemplateID string `json:"template_id" jsonschema:"Template UUID to look up"`
+}
+
+// ── Output types ────────────────────────────────────────────────────────────
+
+type TemplateUsage struct {
+	TemplateID string               `json:"templateId"`
+	Campaigns  []TemplateUsageEntry `json:"campaigns"`
+}
+
+type TemplateUsageEntry struct {
+	CampaignID      string `json:"campaignId"`
+	Name            string `json:"name"`
+	Status          string `json:"status"`
+	TemplateVersion int32  `json:"templateVersion,omitempty"`
 }
 
 // ── Registration ────────────────────────────────────────────────────────────
@@ -92,7 +126,39 @@ func registerTemplateTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "import_template",
+		Description: "Import a template from raw HTML or Markdown (e.g. exported from another email tool). Only an allowlist of formatting tags and attributes is kept (scripts, style sheets, forms, SVG, event handlers and non-http links are stripped), relative links are resolved against base_url, and the type is inferred when format is omitted.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input ImportTemplateInput) (*mcp.CallToolResult, any, error) {
+		isHTML := looksLikeHTML(input.Content)
+		switch strings.ToUpper(input.Format) {
+		case "HTML", "TEMPLATE_TYPE_HTML":
+			isHTML = true
+		case "MARKDOWN", "TEMPLATE_TYPE_MARKDOWN":
+			isHTML = false
+		}
+		templateType := pidgrv1.TemplateType_TEMPLATE_TYPE_MARKDOWN
+		body := sanitizeMarkdown(input.Content, input.BaseURL)
+		if isHTML {
+			templateType = pidgrv1.TemplateType_TEMPLATE_TYPE_HTML
+			body = sanitizeHTML(input.Content, input.BaseURL)
+		}
+		resp, err := c.Templates.CreateTemplate(ctx, connect.NewRequest(&pidgrv1.CreateTemplateRequest{
+			Name:      input.Name,
+			Body:      body,
+			Variables: toProtoVariables(input.Variables),
+			Title:     input.Title,
+			Type:      templateType,
+		}))
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -109,7 +175,7 @@ func registerTemplateTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -125,7 +191,7 @@ func registerTemplateTools(s *mcp.Server, c *transport.Clients) {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, resp.Msg)
 		return r, nil, err
 	})
 
@@ -139,18 +205,46 @@ func registerTemplateTools(s *mcp.Server, c *transport.Clients) {
 		} else if t, ok := pidgrv1.TemplateType_value["TEMPLATE_TYPE_"+input.Type]; ok {
 			templateType = pidgrv1.TemplateType(t)
 		}
-		resp, err := c.Templates.ListTemplates(ctx, connect.NewRequest(&pidgrv1.ListTemplatesRequest{
-			Pagination: &pidgrv1.Pagination{
-				PageSize:  clampPageSize(input.PageSize),
-				PageToken: input.PageToken,
-			},
-			Type: templateType,
-		}))
+		msg, err := collectPages(ctx, input.PageSize, input.PageToken, input.FetchAll, func(ctx context.Context, p *pidgrv1.Pagination) (*pidgrv1.ListTemplatesResponse, string, error) {
+			resp, err := c.Templates.ListTemplates(ctx, connect.NewRequest(&pidgrv1.ListTemplatesRequest{
+				Pagination: p,
+				Type:       templateType,
+			}))
+			if err != nil {
+				return nil, "", err
+			}
+			return resp.Msg, resp.Msg.GetPaginationMeta().GetNextPageToken(), nil
+		})
 		if err != nil {
 			r, _ := convert.ErrorResult(err)
 			return r, nil, nil
 		}
-		r, err := convert.ProtoResult(resp.Msg)
+		r, err := convert.ProtoResult(ctx, msg)
+		return r, nil, err
+	})
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "get_template_usage",
+		Description: "List campaigns (with their statuses) that reference a template, to assess blast radius before updating it. Use list_templates to find the template UUID.",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input GetTemplateUsageInput) (*mcp.CallToolResult, any, error) {
+		campaigns, err := listAllCampaigns(ctx, c)
+		if err != nil {
+			r, _ := convert.ErrorResult(err)
+			return r, nil, nil
+		}
+		usage := TemplateUsage{TemplateID: input.TemplateID, Campaigns: []TemplateUsageEntry{}}
+		for _, cp := range campaigns {
+			if cp.GetTemplateId() != input.TemplateID {
+				continue
+			}
+			usage.Campaigns = append(usage.Campaigns, TemplateUsageEntry{
+				CampaignID:      cp.GetId(),
+				Name:            cp.GetName(),
+				Status:          convert.EnumValue(cp.GetStatus().String(), "CAMPAIGN_STATUS_"),
+				TemplateVersion: cp.GetTemplateVersion(),
+			})
+		}
+		r, err := convert.JSONResult(usage)
 		return r, nil, err
 	})
 }
@@ -0,0 +1,82 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	pidgrv1 "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1"
+)
+
+// userFilter selects and orders users after they are fetched: ListUsers has
+// no filter or sort fields, so list_users and list_role_members apply them
+// to the pages the backend returns.
+type userFilter struct {
+	roleID     string
+	status     pidgrv1.UserStatus
+	department string
+	sortBy     string // NAME, EMAIL, or CREATED_AT; empty keeps backend order
+	desc       bool
+}
+
+// userSortFields are the accepted sort_by values.
+var userSortFields = map[string]bool{"NAME": true, "EMAIL": true, "CREATED_AT": true}
+
+// newUserFilter validates the list_users filter inputs.
+func newUserFilter(roleID, status, department, sortBy string, desc bool) (userFilter, error) {
+	f := userFilter{
+		roleID:     roleID,
+		department: strings.TrimSpace(department),
+		sortBy:     strings.ToUpper(strings.TrimSpace(sortBy)),
+		desc:       desc,
+	}
+	if status != "" {
+		if f.status = parseUserStatus(status); f.status == pidgrv1.UserStatus_USER_STATUS_UNSPECIFIED {
+			return userFilter{}, fmt.Errorf("status must be ACTIVE, DEACTIVATED, or PENDING, got %q", status)
+		}
+	}
+	if f.sortBy != "" && !userSortFields[f.sortBy] {
+		return userFilter{}, fmt.Errorf("sort_by must be NAME, EMAIL, or CREATED_AT, got %q", sortBy)
+	}
+	return f, nil
+}
+
+// apply returns the users that match f, sorted as f requests.
+func (f userFilter) apply(users []*pidgrv1.User) []*pidgrv1.User {
+	var out []*pidgrv1.User
+	for _, u := range users {
+		if f.roleID != "" && u.GetRoleId() != f.roleID && u.GetRole().GetId() != f.roleID {
+			continue
+		}
+		if f.status != pidgrv1.UserStatus_USER_STATUS_UNSPECIFIED && u.GetStatus() != f.status {
+			continue
+		}
+		if f.department != "" && !strings.EqualFold(u.GetProfile().GetDepartment(), f.department) {
+			continue
+		}
+		out = append(out, u)
+	}
+	if f.sortBy == "" {
+		return out
+	}
+	less := func(a, b *pidgrv1.User) bool {
+		switch f.sortBy {
+		case "EMAIL":
+			return strings.ToLower(a.GetEmail()) < strings.ToLower(b.GetEmail())
+		case "CREATED_AT":
+			return a.GetCreatedAt().AsTime().Before(b.GetCreatedAt().AsTime())
+		default:
+			return strings.ToLower(a.GetName()) < strings.ToLower(b.GetName())
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if f.desc {
+			return less(out[j], out[i])
+		}
+		return less(out[i], out[j])
+	})
+	return out
+}
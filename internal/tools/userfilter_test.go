@@ -0,0 +1,62 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	pidgrv1 "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestUserFilter(t *testing.T) {
+	day := func(d int) *timestamppb.Timestamp {
+		return timestamppb.New(time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC))
+	}
+	users := []*pidgrv1.User{
+		{Id: "u1", Name: "Carol", Email: "carol@example.com", RoleId: "admin", Status: pidgrv1.UserStatus_USER_STATUS_DEACTIVATED, CreatedAt: day(3), Profile: &pidgrv1.UserProfile{Department: "Finance"}},
+		{Id: "u2", Name: "alice", Email: "alice@example.com", Role: &pidgrv1.Role{Id: "admin"}, Status: pidgrv1.UserStatus_USER_STATUS_ACTIVE, CreatedAt: day(1), Profile: &pidgrv1.UserProfile{Department: "finance"}},
+		{Id: "u3", Name: "Bob", Email: "bob@example.com", RoleId: "member", Status: pidgrv1.UserStatus_USER_STATUS_INVITED, CreatedAt: day(2)},
+	}
+	ids := func(users []*pidgrv1.User) string {
+		var out []string
+		for _, u := range users {
+			out = append(out, u.GetId())
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		name                         string
+		roleID, status, dept, sortBy string
+		desc                         bool
+		want                         string
+	}{
+		{name: "none", want: "u1,u2,u3"},
+		{name: "role", roleID: "admin", want: "u1,u2"},
+		{name: "deactivated admins", roleID: "admin", status: "deactivated", want: "u1"},
+		{name: "pending", status: "PENDING", want: "u3"},
+		{name: "department", dept: "FINANCE", sortBy: "name", want: "u2,u1"},
+		{name: "email desc", sortBy: "EMAIL", desc: true, want: "u1,u3,u2"},
+		{name: "created", sortBy: "created_at", want: "u2,u3,u1"},
+	}
+	for _, tt := range tests {
+		f, err := newUserFilter(tt.roleID, tt.status, tt.dept, tt.sortBy, tt.desc)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if got := ids(f.apply(users)); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := newUserFilter("", "retired", "", "", false); err == nil {
+		t.Error("expected an error for an unknown status")
+	}
+	if _, err := newUserFilter("", "", "", "salary", false); err == nil {
+		t.Error("expected an error for an unknown sort field")
+	}
+}
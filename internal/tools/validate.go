@@ -9,6 +9,9 @@ const (
 	maxPageSize  int32 = 100
 	defaultPageSize int32 = 20
 	maxBatchSize       = 1000
+
+	// membershipChunkSize is the backend's per-call limit for membership mutations.
+	membershipChunkSize = 100
 )
 
 // clampPageSize caps page_size at maxPageSize and defaults to defaultPageSize.
@@ -29,3 +32,16 @@ func validateBatchSize(ids []string, max int) error {
 	}
 	return nil
 }
+
+// chunkIDs splits ids into consecutive slices of at most size elements.
+func chunkIDs(ids []string, size int) [][]string {
+	var chunks [][]string
+	for len(ids) > size {
+		chunks = append(chunks, ids[:size])
+		ids = ids[size:]
+	}
+	if len(ids) > 0 {
+		chunks = append(chunks, ids)
+	}
+	return chunks
+}
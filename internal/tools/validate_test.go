@@ -4,6 +4,7 @@
 package tools
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -59,3 +60,29 @@ func TestValidateBatchSize(t *testing.T) {
 		}
 	})
 }
+
+func TestChunkIDs(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int
+		size int
+		want string
+	}{
+		{"empty", 0, 100, "[]"},
+		{"under size", 3, 100, "[3]"},
+		{"exact multiple", 200, 100, "[100 100]"},
+		{"remainder", 250, 100, "[100 100 50]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := make([]string, tt.n)
+			var sizes []int
+			for _, c := range chunkIDs(ids, tt.size) {
+				sizes = append(sizes, len(c))
+			}
+			if got := fmt.Sprint(sizes); got != tt.want {
+				t.Errorf("chunk sizes = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// BreakerPolicy controls the per-service circuit breaker, which stops calling
+// a backend service that keeps failing and lets it recover.
+type BreakerPolicy struct {
+	// Failures is how many consecutive failed calls open the circuit; zero
+	// disables the breaker.
+	Failures int
+	// Cooldown is how long an open circuit fails fast before a single probe
+	// call is let through.
+	Cooldown time.Duration
+}
+
+// DefaultBreakerPolicy opens after 5 consecutive failures for 30s.
+var DefaultBreakerPolicy = BreakerPolicy{
+	Failures: 5,
+	Cooldown: 30 * time.Second,
+}
+
+// CircuitOpenError is returned, wrapped in a Connect Unavailable error, for
+// calls rejected by an open circuit.
+type CircuitOpenError struct {
+	Service    string
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit open for %s", e.Service)
+}
+
+// UserMessage explains the rejection to users; convert.ErrorMessage shows it
+// in place of the generic "Service unavailable".
+func (e *CircuitOpenError) UserMessage() string {
+	return fmt.Sprintf("The %s is temporarily unavailable after repeated failures; retry in %d seconds", serviceLabel(e.Service), retrySeconds(e.RetryAfter))
+}
+
+// serviceLabel turns "pidgr.v1.HeatmapService" into "heatmap service".
+func serviceLabel(service string) string {
+	service = service[strings.LastIndex(service, ".")+1:]
+	name := strings.TrimSuffix(service, "Service")
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToLower(b.String()) + " service"
+}
+
+func retrySeconds(d time.Duration) int {
+	return max(int(math.Ceil(d.Seconds())), 1)
+}
+
+// isBackendFailure reports whether an error means the backend is unhealthy.
+// Client errors show it is answering, so they count as successes.
+func isBackendFailure(err error) bool {
+	switch connect.CodeOf(err) {
+	case connect.CodeUnavailable, connect.CodeDeadlineExceeded, connect.CodeInternal:
+		return true
+	}
+	return false
+}
+
+// circuit is the breaker state of one service.
+type circuit struct {
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// breakers tracks a circuit per backend service.
+type breakers struct {
+	policy BreakerPolicy
+	now    func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+func newBreakers(policy BreakerPolicy) *breakers {
+	return &breakers{policy: policy, now: time.Now, circuits: map[string]*circuit{}}
+}
+
+// allow reports whether a call to service may proceed. While a circuit is
+// open it returns how long until the next probe; after the cooldown exactly
+// one probe call is let through.
+func (b *breakers) allow(service string) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuits[service]
+	if c == nil || c.openUntil.IsZero() {
+		return true, 0
+	}
+	if wait := c.openUntil.Sub(b.now()); wait > 0 {
+		return false, wait
+	}
+	if c.probing {
+		return false, time.Second
+	}
+	c.probing = true
+	return true, 0
+}
+
+// record updates service's circuit with the outcome of a call.
+func (b *breakers) record(service string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c := b.circuits[service]
+	if c == nil {
+		c = &circuit{}
+		b.circuits[service] = c
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled) || connect.CodeOf(err) == connect.CodeCanceled:
+		// The caller gave up; this says nothing about the backend.
+		c.probing = false
+	case isBackendFailure(err):
+		c.failures++
+		if c.probing || c.failures >= b.policy.Failures {
+			c.openUntil = b.now().Add(b.policy.Cooldown)
+			c.probing = false
+			slog.Warn("circuit opened", "service", service, "failures", c.failures, "cooldown", b.policy.Cooldown)
+		}
+	default:
+		if !c.openUntil.IsZero() {
+			slog.Info("circuit closed", "service", service)
+		}
+		*c = circuit{}
+	}
+}
+
+// breakerInterceptor fails calls to a service fast while its circuit is
+// open, instead of adding load to a backend that is already failing.
+func breakerInterceptor(policy BreakerPolicy) connect.UnaryInterceptorFunc {
+	return newBreakers(policy).interceptor()
+}
+
+func (b *breakers) interceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if b.policy.Failures <= 0 {
+				return next(ctx, req)
+			}
+			service := serviceName(req.Spec().Procedure)
+			if ok, wait := b.allow(service); !ok {
+				err := connect.NewError(connect.CodeUnavailable, &CircuitOpenError{Service: service, RetryAfter: wait})
+				err.Meta().Set("Retry-After", strconv.Itoa(retrySeconds(wait)))
+				return nil, err
+			}
+			resp, err := next(ctx, req)
+			b.record(service, err)
+			return resp, err
+		}
+	}
+}
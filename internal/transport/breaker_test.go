@@ -0,0 +1,93 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+func TestBreakerInterceptor(t *testing.T) {
+	now := time.Date(2026, 3, 2, 14, 30, 0, 0, time.UTC)
+	b := newBreakers(BreakerPolicy{Failures: 3, Cooldown: 10 * time.Second})
+	b.now = func() time.Time { return now }
+	opts := connect.WithInterceptors(b.interceptor())
+
+	const procedure = "/pidgr.v1.HeatmapService/QueryHeatmapData"
+	down := func(int) error { return connect.NewError(connect.CodeUnavailable, errors.New("down")) }
+	up := func(int) error { return nil }
+
+	for i := 0; i < 3; i++ {
+		if attempts, _ := testCall(t, context.Background(), procedure, down, opts); attempts != 1 {
+			t.Fatalf("call %d: expected the backend to be called while closed", i)
+		}
+	}
+
+	attempts, err := testCall(t, context.Background(), procedure, up, opts)
+	if attempts != 0 {
+		t.Error("expected an open circuit to fail fast without calling the backend")
+	}
+	var open *CircuitOpenError
+	if connect.CodeOf(err) != connect.CodeUnavailable || !errors.As(err, &open) {
+		t.Fatalf("expected a CircuitOpenError, got %v", err)
+	}
+	if msg := open.UserMessage(); !strings.Contains(msg, "heatmap service is temporarily unavailable") || !strings.Contains(msg, "10 seconds") {
+		t.Errorf("unexpected message %q", msg)
+	}
+	var ce *connect.Error
+	if !errors.As(err, &ce) || ce.Meta().Get("Retry-After") != "10" {
+		t.Errorf("expected Retry-After 10 on %v", err)
+	}
+
+	// Other services are unaffected.
+	if attempts, err := testCall(t, context.Background(), "/pidgr.v1.CampaignService/ListCampaigns", up, opts); attempts != 1 || err != nil {
+		t.Errorf("expected other services to be called, got %d attempts, %v", attempts, err)
+	}
+
+	// After the cooldown a probe goes through, and its success closes the circuit.
+	now = now.Add(11 * time.Second)
+	if attempts, err := testCall(t, context.Background(), procedure, up, opts); attempts != 1 || err != nil {
+		t.Fatalf("expected a probe call, got %d attempts, %v", attempts, err)
+	}
+	if attempts, _ := testCall(t, context.Background(), procedure, up, opts); attempts != 1 {
+		t.Error("expected the circuit to close after a successful probe")
+	}
+}
+
+func TestBreakerProbeFailureReopens(t *testing.T) {
+	now := time.Date(2026, 3, 2, 14, 30, 0, 0, time.UTC)
+	b := newBreakers(BreakerPolicy{Failures: 1, Cooldown: time.Minute})
+	b.now = func() time.Time { return now }
+	failure := connect.NewError(connect.CodeInternal, errors.New("boom"))
+
+	const service = "pidgr.v1.ReplayService"
+	b.record(service, failure)
+	if ok, _ := b.allow(service); ok {
+		t.Fatal("expected the circuit to open")
+	}
+	now = now.Add(2 * time.Minute)
+	if ok, _ := b.allow(service); !ok {
+		t.Fatal("expected a probe after the cooldown")
+	}
+	if ok, _ := b.allow(service); ok {
+		t.Error("expected only one probe at a time")
+	}
+	b.record(service, failure)
+	if ok, wait := b.allow(service); ok || wait != time.Minute {
+		t.Errorf("expected the failed probe to reopen for a full cooldown, got ok=%v wait=%v", ok, wait)
+	}
+}
+
+func TestBreakerIgnoresClientErrors(t *testing.T) {
+	b := newBreakers(BreakerPolicy{Failures: 1, Cooldown: time.Minute})
+	b.record("pidgr.v1.CampaignService", connect.NewError(connect.CodeNotFound, errors.New("missing")))
+	if ok, _ := b.allow("pidgr.v1.CampaignService"); !ok {
+		t.Error("expected client errors not to open the circuit")
+	}
+}
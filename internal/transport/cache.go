@@ -0,0 +1,380 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"bytes"
+	"compress/gzip"
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStore holds cached backend responses. Keys start with the service
+// name and "|", so a write can drop a service's entries by prefix. The
+// in-memory store is used by default; a shared store such as Redis can be
+// plugged in by implementing this interface.
+type CacheStore interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+	DeletePrefix(prefix string)
+}
+
+// CachePolicy configures the read-through cache for read RPCs. Agent loops
+// often re-read the same campaign many times; caching those reads briefly
+// cuts backend load. Entries are per principal, so one caller never sees
+// another's results, and a write RPC to a service drops the service's
+// cached reads.
+type CachePolicy struct {
+	// TTL is how long a read is cached; zero disables the cache.
+	TTL time.Duration
+	// Revalidate keeps entries with an ETag this long after they go stale.
+	// A stale entry is refetched with If-None-Match, and served again when
+	// the backend answers not-modified.
+	Revalidate time.Duration
+	// MaxEntries bounds the number of entries in the in-memory store.
+	MaxEntries int
+	// MaxBytes bounds the total size of the in-memory store; the least
+	// recently used entries are evicted to stay under it.
+	MaxBytes int
+	// MaxEntryBytes is the largest stored response; larger ones, such as
+	// session snapshot chunks, are passed through uncached. It applies to
+	// any store.
+	MaxEntryBytes int
+	// Store overrides the in-memory store.
+	Store CacheStore
+}
+
+// DefaultCachePolicy leaves caching off, with room for 1000 reads of up to
+// 1 MiB each, 64 MiB in all, and a 10 minute revalidation window once a TTL
+// is configured.
+var DefaultCachePolicy = CachePolicy{
+	Revalidate:    10 * time.Minute,
+	MaxEntries:    1000,
+	MaxBytes:      64 << 20,
+	MaxEntryBytes: 1 << 20,
+}
+
+// cachingTransport serves read RPCs from the cache at the HTTP layer, where
+// a whole response (headers, body, and trailers) can be stored as bytes and
+// replayed to Connect.
+type cachingTransport struct {
+	base          http.RoundTripper
+	ttl           time.Duration
+	revalidate    time.Duration
+	maxEntryBytes int
+	store         CacheStore
+	now           func() time.Time
+}
+
+func newCachingTransport(base http.RoundTripper, policy CachePolicy) *cachingTransport {
+	store := policy.Store
+	if store == nil {
+		store = newMemoryCache(policy.MaxEntries, policy.MaxBytes, time.Now)
+	}
+	return &cachingTransport{
+		base:          base,
+		ttl:           policy.TTL,
+		revalidate:    policy.Revalidate,
+		maxEntryBytes: policy.MaxEntryBytes,
+		store:         store,
+		now:           time.Now,
+	}
+}
+
+// cachedResponse is the stored form of a response.
+type cachedResponse struct {
+	Status  int         `json:"status"`
+	Proto   string      `json:"proto"`
+	Major   int         `json:"major"`
+	Minor   int         `json:"minor"`
+	Header  http.Header `json:"header"`
+	Trailer http.Header `json:"trailer"`
+	Body    []byte      `json:"body"`
+	Expires time.Time   `json:"expires"`
+}
+
+func (c *cachedResponse) etag() string {
+	return c.Header.Get("Etag")
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	service, read := readPath(req.URL.Path)
+	if req.Body == nil || service == "" {
+		return t.base.RoundTrip(req)
+	}
+	if !read {
+		resp, err := t.base.RoundTrip(req)
+		// Invalidate even on failure: the write may have been applied.
+		t.store.DeletePrefix(service + "|")
+		return resp, err
+	}
+
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	key := cacheKey(service, req, body)
+
+	var stale *cachedResponse
+	if data, ok := t.store.Get(key); ok {
+		var c cachedResponse
+		if json.Unmarshal(data, &c) == nil {
+			if t.now().Before(c.Expires) {
+				return c.response(req), nil
+			}
+			stale = &c
+		}
+	}
+
+	r := req.Clone(req.Context())
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	if stale != nil {
+		r.Header.Set("If-None-Match", stale.etag())
+	}
+	resp, err := t.base.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	if stale != nil && resp.StatusCode == http.StatusNotModified {
+		_ = resp.Body.Close()
+		return t.save(key, stale, req), nil
+	}
+	// Errors are passed through unread, except in answer to a conditional
+	// request: Connect reports not-modified as an error status.
+	if resp.StatusCode != http.StatusOK && stale == nil {
+		return resp, nil
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	c := cachedResponse{
+		Status:  resp.StatusCode,
+		Proto:   resp.Proto,
+		Major:   resp.ProtoMajor,
+		Minor:   resp.ProtoMinor,
+		Header:  resp.Header,
+		Trailer: resp.Trailer,
+		Body:    respBody,
+	}
+	switch {
+	case stale != nil && notModified(resp, respBody):
+		return t.save(key, stale, req), nil
+	case resp.StatusCode == http.StatusOK && succeeded(resp, respBody) && (t.maxEntryBytes <= 0 || len(respBody) <= t.maxEntryBytes):
+		t.save(key, &c, req)
+	}
+	return c.response(req), nil
+}
+
+// save stores c fresh for the TTL and returns it as a response. Entries
+// with an ETag are kept for the revalidation window after going stale, so
+// they can be refetched conditionally. Entries larger than maxEntryBytes
+// are not stored.
+func (t *cachingTransport) save(key string, c *cachedResponse, req *http.Request) *http.Response {
+	c.Expires = t.now().Add(t.ttl)
+	keep := t.ttl
+	if c.etag() != "" {
+		keep += t.revalidate
+	}
+	if data, err := json.Marshal(c); err == nil && (t.maxEntryBytes <= 0 || len(data) <= t.maxEntryBytes) {
+		t.store.Set(key, data, keep)
+	}
+	return c.response(req)
+}
+
+// cacheKey identifies a read by service, principal, method, and request
+// body. The principal is a hash of the Authorization header, so tokens are
+// not stored in keys.
+func cacheKey(service string, req *http.Request, body []byte) string {
+	principal := sha256.Sum256([]byte(req.Header.Get("Authorization")))
+	payload := sha256.Sum256(body)
+	method := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+	return service + "|" + hex.EncodeToString(principal[:8]) + "|" + method + "|" + hex.EncodeToString(payload[:])
+}
+
+// notModified reports whether a fully read response to a conditional
+// request says the cached content is current. The backend's
+// connect.NewNotModifiedError is an Unknown error with the message "not
+// modified": over gRPC its status arrives in the trailers, over gRPC-Web in
+// the headers or the trailer frame at the end of the body, and over Connect
+// as a JSON error body. A Connect GET gets an HTTP 304 instead, handled by
+// the caller.
+func notModified(resp *http.Response, body []byte) bool {
+	contentType := resp.Header.Get("Content-Type")
+	var status, message string
+	switch {
+	case strings.HasPrefix(contentType, "application/grpc-web"):
+		status, message = resp.Header.Get("Grpc-Status"), resp.Header.Get("Grpc-Message")
+		if status == "" {
+			trailer := grpcWebTrailer(body)
+			status, message = trailer.Get("Grpc-Status"), trailer.Get("Grpc-Message")
+		}
+	case strings.HasPrefix(contentType, "application/grpc"):
+		status, message = resp.Trailer.Get("Grpc-Status"), resp.Trailer.Get("Grpc-Message")
+		if status == "" {
+			status, message = resp.Header.Get("Grpc-Status"), resp.Header.Get("Grpc-Message")
+		}
+	case resp.StatusCode != http.StatusOK:
+		var wire struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		return json.Unmarshal(body, &wire) == nil && wire.Code == "unknown" && wire.Message == "not modified"
+	}
+	return status == "2" && message == "not modified"
+}
+
+// succeeded reports whether a fully read response is a successful RPC: for
+// gRPC the status arrives in the trailers, after the body, and for gRPC-Web
+// in a trailer frame at the end of the body.
+func succeeded(resp *http.Response, body []byte) bool {
+	contentType := resp.Header.Get("Content-Type")
+	switch {
+	case strings.HasPrefix(contentType, "application/grpc-web"):
+		status := resp.Header.Get("Grpc-Status")
+		if status == "" {
+			status = grpcWebTrailer(body).Get("Grpc-Status")
+		}
+		return status == "0"
+	case strings.HasPrefix(contentType, "application/grpc"):
+		status := resp.Trailer.Get("Grpc-Status")
+		if status == "" {
+			status = resp.Header.Get("Grpc-Status")
+		}
+		return status == "0"
+	}
+	return resp.StatusCode == http.StatusOK
+}
+
+// grpcWebTrailer parses the trailer frame of a gRPC-Web response body: a
+// frame flagged 0x80 whose payload is HTTP/1-style header lines, gzipped
+// when the frame is also flagged 0x01.
+func grpcWebTrailer(body []byte) http.Header {
+	trailer := http.Header{}
+	for len(body) >= 5 {
+		flags, size := body[0], int(binary.BigEndian.Uint32(body[1:5]))
+		if len(body)-5 < size {
+			break
+		}
+		payload := body[5 : 5+size]
+		body = body[5+size:]
+		if flags&0x80 == 0 {
+			continue
+		}
+		if flags&0x01 != 0 {
+			zr, err := gzip.NewReader(bytes.NewReader(payload))
+			if err != nil {
+				break
+			}
+			payload, err = io.ReadAll(zr)
+			if err != nil {
+				break
+			}
+		}
+		for line := range strings.SplitSeq(string(payload), "\r\n") {
+			if name, value, ok := strings.Cut(line, ":"); ok {
+				trailer.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+			}
+		}
+	}
+	return trailer
+}
+
+func (c *cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(c.Status),
+		StatusCode:    c.Status,
+		Proto:         c.Proto,
+		ProtoMajor:    c.Major,
+		ProtoMinor:    c.Minor,
+		Header:        c.Header.Clone(),
+		Trailer:       c.Trailer.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(c.Body)),
+		ContentLength: int64(len(c.Body)),
+		Request:       req,
+	}
+}
+
+// memoryCache is an in-memory CacheStore with TTL expiry and LRU eviction,
+// bounded by entry count and by the total size of the stored values.
+type memoryCache struct {
+	max      int
+	maxBytes int
+	now      func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+	bytes   int
+}
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+func newMemoryCache(max, maxBytes int, now func() time.Time) *memoryCache {
+	return &memoryCache{max: max, maxBytes: maxBytes, now: now, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+func (m *memoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*memoryEntry)
+	if !m.now().Before(e.expires) {
+		m.remove(el)
+		return nil, false
+	}
+	m.order.MoveToFront(el)
+	return e.value, true
+}
+
+func (m *memoryCache) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.entries[key]; ok {
+		m.remove(el)
+	}
+	if m.maxBytes > 0 && len(value) > m.maxBytes {
+		return
+	}
+	m.entries[key] = m.order.PushFront(&memoryEntry{key: key, value: value, expires: m.now().Add(ttl)})
+	m.bytes += len(value)
+	for (m.max > 0 && m.order.Len() > m.max) || (m.maxBytes > 0 && m.bytes > m.maxBytes) {
+		m.remove(m.order.Back())
+	}
+}
+
+func (m *memoryCache) DeletePrefix(prefix string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, el := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			m.remove(el)
+		}
+	}
+}
+
+// remove drops an entry; m.mu must be held.
+func (m *memoryCache) remove(el *list.Element) {
+	e := m.order.Remove(el).(*memoryEntry)
+	delete(m.entries, e.key)
+	m.bytes -= len(e.value)
+}
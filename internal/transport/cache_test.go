@@ -0,0 +1,347 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestCachingTransport(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	handler := func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+		n := calls.Add(1)
+		if req.Msg.GetValue() == "missing" {
+			return nil, connect.NewError(connect.CodeNotFound, errors.New("not found"))
+		}
+		return connect.NewResponse(wrapperspb.String(fmt.Sprintf("%s-%d", req.Msg.GetValue(), n))), nil
+	}
+	for _, procedure := range []string{"/pidgr.v1.CampaignService/GetCampaign", "/pidgr.v1.CampaignService/UpdateCampaign"} {
+		mux.Handle(procedure, connect.NewUnaryHandler(procedure, handler))
+	}
+	ts := httptest.NewUnstartedServer(mux)
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	httpClient := &http.Client{Transport: newCachingTransport(ts.Client().Transport, CachePolicy{TTL: time.Minute, MaxEntries: 10})}
+	call := func(procedure, token, value string) (string, error) {
+		t.Helper()
+		client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](httpClient, ts.URL+procedure, connect.WithGRPC(),
+			connect.WithInterceptors(staticTokenInterceptor(newStaticToken(token))))
+		resp, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String(value)))
+		if err != nil {
+			return "", err
+		}
+		return resp.Msg.GetValue(), nil
+	}
+	const get, update = "/pidgr.v1.CampaignService/GetCampaign", "/pidgr.v1.CampaignService/UpdateCampaign"
+
+	first, err := call(get, "a", "c1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if second, _ := call(get, "a", "c1"); second != first || calls.Load() != 1 {
+		t.Errorf("expected cached read %q, got %q after %d calls", first, second, calls.Load())
+	}
+	if other, _ := call(get, "a", "c2"); other == first || calls.Load() != 2 {
+		t.Error("expected a different request to miss the cache")
+	}
+	if _, _ = call(get, "b", "c1"); calls.Load() != 3 {
+		t.Error("expected a different principal to miss the cache")
+	}
+
+	for range 2 {
+		if _, err := call(get, "a", "missing"); connect.CodeOf(err) != connect.CodeNotFound {
+			t.Fatalf("got error %v, want not_found", err)
+		}
+	}
+	if calls.Load() != 5 {
+		t.Errorf("expected errors not to be cached, got %d calls", calls.Load())
+	}
+
+	if _, err := call(update, "a", "c1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	before := calls.Load()
+	if after, _ := call(get, "a", "c1"); after == first || calls.Load() != before+1 {
+		t.Error("expected a write to invalidate the service's cached reads")
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	now := time.Now()
+	m := newMemoryCache(2, 0, func() time.Time { return now })
+
+	m.Set("CampaignService|a", []byte("1"), time.Minute)
+	m.Set("CampaignService|b", []byte("2"), time.Minute)
+	m.Get("CampaignService|a")
+	m.Set("GroupService|c", []byte("3"), time.Minute)
+	if _, ok := m.Get("CampaignService|b"); ok {
+		t.Error("expected least recently used entry to be evicted")
+	}
+	if v, ok := m.Get("CampaignService|a"); !ok || string(v) != "1" {
+		t.Errorf("got %q, %v; want recently used entry kept", v, ok)
+	}
+
+	m.DeletePrefix("CampaignService|")
+	if _, ok := m.Get("CampaignService|a"); ok {
+		t.Error("expected prefix delete to drop the service's entries")
+	}
+	if _, ok := m.Get("GroupService|c"); !ok {
+		t.Error("expected other services' entries to survive")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := m.Get("GroupService|c"); ok {
+		t.Error("expected expired entry to be dropped")
+	}
+}
+
+func TestMemoryCacheMaxBytes(t *testing.T) {
+	m := newMemoryCache(0, 10, time.Now)
+
+	m.Set("CampaignService|a", []byte("1234"), time.Minute)
+	m.Set("CampaignService|b", []byte("1234"), time.Minute)
+	m.Get("CampaignService|a")
+	m.Set("CampaignService|c", []byte("1234"), time.Minute)
+	if _, ok := m.Get("CampaignService|b"); ok {
+		t.Error("expected least recently used entry to be evicted to stay under the byte limit")
+	}
+	if _, ok := m.Get("CampaignService|a"); !ok {
+		t.Error("expected recently used entry kept")
+	}
+
+	m.Set("CampaignService|a", []byte("12345678901"), time.Minute)
+	if _, ok := m.Get("CampaignService|a"); ok {
+		t.Error("expected a value larger than the whole store not to be kept")
+	}
+	if _, ok := m.Get("CampaignService|c"); !ok {
+		t.Error("expected an oversized value not to evict other entries")
+	}
+	if m.bytes != 4 {
+		t.Errorf("store holds %d bytes, want 4", m.bytes)
+	}
+}
+
+func TestCachingTransportSkipsLargeResponses(t *testing.T) {
+	const procedure = "/pidgr.v1.ReplayService/GetSessionSnapshots"
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.Handle(procedure, connect.NewUnaryHandler(procedure, func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+		calls.Add(1)
+		// Random bytes, so the response stays large after compression.
+		payload := make([]byte, 8)
+		if req.Msg.GetValue() == "large" {
+			payload = make([]byte, 4096)
+		}
+		_, _ = rand.Read(payload)
+		return connect.NewResponse(wrapperspb.String(hex.EncodeToString(payload))), nil
+	}))
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	policy := CachePolicy{TTL: time.Minute, MaxEntries: 10, MaxEntryBytes: 1024}
+	httpClient := &http.Client{Transport: newCachingTransport(ts.Client().Transport, policy)}
+	client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](httpClient, ts.URL+procedure)
+	call := func(value string) {
+		t.Helper()
+		if _, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String(value))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	call("small")
+	call("small")
+	if calls.Load() != 1 {
+		t.Errorf("expected a small read to be cached, got %d calls", calls.Load())
+	}
+	call("large")
+	call("large")
+	if calls.Load() != 3 {
+		t.Errorf("expected a read over MaxEntryBytes not to be cached, got %d calls", calls.Load())
+	}
+}
+
+func TestCachingTransportRevalidate(t *testing.T) {
+	const procedure = "/pidgr.v1.CampaignService/GetCampaign"
+	// Each protocol reports the backend's not-modified error differently:
+	// gRPC in trailers, gRPC-Web in a trailer frame, Connect in a JSON body.
+	tests := []struct {
+		name   string
+		option connect.ClientOption
+	}{
+		{"grpc", connect.WithGRPC()},
+		{"grpcweb", connect.WithGRPCWeb()},
+		{"connect", connect.WithProtoJSON()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			var version atomic.Value
+			version.Store("v1")
+			mux := http.NewServeMux()
+			mux.Handle(procedure, connect.NewUnaryHandler(procedure, func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+				n := calls.Add(1)
+				current := version.Load().(string)
+				if req.Header().Get("If-None-Match") == current {
+					return nil, connect.NewNotModifiedError(nil)
+				}
+				resp := connect.NewResponse(wrapperspb.String(fmt.Sprintf("%s-%d", current, n)))
+				resp.Header().Set("Etag", current)
+				return resp, nil
+			}))
+			ts := httptest.NewUnstartedServer(mux)
+			ts.EnableHTTP2 = true
+			ts.StartTLS()
+			defer ts.Close()
+
+			now := time.Now()
+			clock := func() time.Time { return now }
+			transport := &cachingTransport{
+				base:       ts.Client().Transport,
+				ttl:        time.Minute,
+				revalidate: time.Hour,
+				store:      newMemoryCache(10, 0, clock),
+				now:        clock,
+			}
+			client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](&http.Client{Transport: transport}, ts.URL+procedure, tt.option)
+			call := func() string {
+				t.Helper()
+				resp, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("c1")))
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return resp.Msg.GetValue()
+			}
+
+			first := call()
+			now = now.Add(2 * time.Minute)
+			if got := call(); got != first || calls.Load() != 2 {
+				t.Errorf("expected stale entry served after not-modified, got %q after %d calls", got, calls.Load())
+			}
+			if got := call(); got != first || calls.Load() != 2 {
+				t.Errorf("expected not-modified to refresh the entry, got %q after %d calls", got, calls.Load())
+			}
+
+			version.Store("v2")
+			now = now.Add(2 * time.Minute)
+			if got := call(); got != "v2-3" {
+				t.Errorf("expected changed content to replace the entry, got %q", got)
+			}
+		})
+	}
+}
+
+func TestCachingTransportRevalidateErrors(t *testing.T) {
+	const procedure = "/pidgr.v1.CampaignService/GetCampaign"
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.Handle(procedure, connect.NewUnaryHandler(procedure, func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+		if calls.Add(1) > 1 {
+			return nil, connect.NewError(connect.CodeUnknown, errors.New("backend failure"))
+		}
+		resp := connect.NewResponse(wrapperspb.String("v1"))
+		resp.Header().Set("Etag", "v1")
+		return resp, nil
+	}))
+	ts := httptest.NewUnstartedServer(mux)
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	now := time.Now()
+	clock := func() time.Time { return now }
+	transport := &cachingTransport{
+		base:       ts.Client().Transport,
+		ttl:        time.Minute,
+		revalidate: time.Hour,
+		store:      newMemoryCache(10, 0, clock),
+		now:        clock,
+	}
+	client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](&http.Client{Transport: transport}, ts.URL+procedure, connect.WithProtoJSON())
+	if _, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("c1"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A Connect error in answer to a conditional request is read to look
+	// for not-modified; any other error must still reach the caller intact.
+	now = now.Add(2 * time.Minute)
+	_, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("c1")))
+	if connect.CodeOf(err) != connect.CodeUnknown || !strings.Contains(err.Error(), "backend failure") {
+		t.Errorf("got error %v, want the backend's unknown error", err)
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	frame := func(flags byte, payload string) []byte {
+		b := []byte{flags, 0, 0, 0, 0}
+		binary.BigEndian.PutUint32(b[1:], uint32(len(payload)))
+		return append(b, payload...)
+	}
+	tests := []struct {
+		name string
+		resp *http.Response
+		body []byte
+		want bool
+	}{
+		{
+			name: "grpc trailers",
+			resp: &http.Response{StatusCode: 200, Header: http.Header{"Content-Type": {"application/grpc"}}, Trailer: http.Header{"Grpc-Status": {"2"}, "Grpc-Message": {"not modified"}}},
+			want: true,
+		},
+		{
+			name: "grpc other error",
+			resp: &http.Response{StatusCode: 200, Header: http.Header{"Content-Type": {"application/grpc"}}, Trailer: http.Header{"Grpc-Status": {"2"}, "Grpc-Message": {"boom"}}},
+		},
+		{
+			name: "grpcweb trailer frame",
+			resp: &http.Response{StatusCode: 200, Header: http.Header{"Content-Type": {"application/grpc-web+proto"}}},
+			body: frame(0x80, "grpc-status: 2\r\ngrpc-message: not modified\r\n"),
+			want: true,
+		},
+		{
+			name: "grpcweb success",
+			resp: &http.Response{StatusCode: 200, Header: http.Header{"Content-Type": {"application/grpc-web+proto"}}},
+			body: append(frame(0, "msg"), frame(0x80, "grpc-status: 0\r\n")...),
+		},
+		{
+			name: "connect error body",
+			resp: &http.Response{StatusCode: 500, Header: http.Header{"Content-Type": {"application/json"}}},
+			body: []byte(`{"code":"unknown","message":"not modified"}`),
+			want: true,
+		},
+		{
+			name: "connect other error",
+			resp: &http.Response{StatusCode: 500, Header: http.Header{"Content-Type": {"application/json"}}},
+			body: []byte(`{"code":"unknown","message":"boom"}`),
+		},
+		{
+			name: "connect success",
+			resp: &http.Response{StatusCode: 200, Header: http.Header{"Content-Type": {"application/json"}}},
+			body: []byte(`{"code":"unknown","message":"not modified"}`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := notModified(tt.resp, tt.body); got != tt.want {
+				t.Errorf("notModified = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
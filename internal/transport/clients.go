@@ -5,11 +5,17 @@ package transport
 
 import (
 	"context"
+	"log/slog"
+	"maps"
 	"net/http"
+	"slices"
+	"sync/atomic"
 
 	"connectrpc.com/connect"
 	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/pidgr/pidgr-mcp/internal/observability"
 	pidgrv1connect "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1/pidgrv1connect"
+	"go.opentelemetry.io/otel"
 )
 
 // Clients holds Connect-Go clients for all exposed pidgr-api services.
@@ -24,45 +30,192 @@ type Clients struct {
 	ApiKeys       pidgrv1connect.ApiKeyServiceClient
 	Heatmaps      pidgrv1connect.HeatmapServiceClient
 	Replays       pidgrv1connect.ReplayServiceClient
+
+	rateLimit *rateLimiters
+	apiKey    *staticToken
+	fetch     *http.Client
+}
+
+// FetchClient returns the HTTP client for downloading URLs the backend hands
+// out, such as screenshot images. It uses the backend's proxy and TLS
+// settings.
+func (c *Clients) FetchClient() *http.Client {
+	if c.fetch == nil {
+		return &http.Client{Timeout: fetchTimeout}
+	}
+	return c.fetch
+}
+
+// SetRateLimit replaces the per-organization rate limit policy of calls
+// already in use, e.g. when the configuration is reloaded.
+func (c *Clients) SetRateLimit(policy RateLimitPolicy) {
+	if c.rateLimit != nil {
+		c.rateLimit.setPolicy(policy)
+	}
+}
+
+// Options configures how clients call the backend.
+type Options struct {
+	Retry       RetryPolicy
+	Timeouts    TimeoutPolicy
+	Breaker     BreakerPolicy
+	HTTP        HTTPConfig
+	Compression CompressionPolicy
+	Hedge       HedgePolicy
+	Failover    FailoverPolicy
+	Cache       CachePolicy
+	RateLimit   RateLimitPolicy
+	Protocol    Protocol
+	// UserAgent replaces Connect's default User-Agent when set (see
+	// UserAgent).
+	UserAgent string
+}
+
+// DefaultOptions returns the default client options. The result is a copy
+// that callers may modify.
+func DefaultOptions() Options {
+	timeouts := DefaultTimeoutPolicy
+	timeouts.Services = maps.Clone(DefaultTimeoutPolicy.Services)
+	hedge := DefaultHedgePolicy
+	hedge.Services = slices.Clone(DefaultHedgePolicy.Services)
+	return Options{
+		Retry:       DefaultRetryPolicy,
+		Timeouts:    timeouts,
+		Breaker:     DefaultBreakerPolicy,
+		HTTP:        DefaultHTTPConfig,
+		Compression: DefaultCompressionPolicy,
+		Hedge:       hedge,
+		Failover:    DefaultFailoverPolicy,
+		Cache:       DefaultCachePolicy,
+		RateLimit:   DefaultRateLimitPolicy,
+		Protocol:    DefaultProtocol,
+	}
+}
+
+// SetAPIKey replaces the API key sent by static token clients, e.g. after
+// the key is rotated. It has no effect on dynamic token clients.
+func (c *Clients) SetAPIKey(key string) {
+	if c.apiKey != nil {
+		c.apiKey.set(key)
+	}
 }
 
 // NewStaticTokenClients creates clients that inject a static API key on every request.
 // Used for stdio mode where the token comes from an environment variable.
-func NewStaticTokenClients(baseURL, apiKey string) *Clients {
-	interceptor := staticTokenInterceptor(apiKey)
-	opts := connect.WithInterceptors(interceptor)
-	return newClients(baseURL, http.DefaultClient, opts)
+func NewStaticTokenClients(baseURL, apiKey string, o Options) *Clients {
+	token := newStaticToken(apiKey)
+	limiter := newRateLimiters(o.RateLimit)
+	c := newClients(baseURL, o.httpClient(baseURL), o.clientOptions(limiter, staticTokenInterceptor(token)), limiter)
+	c.apiKey = token
+	c.fetch = o.fetchClient()
+	return c
 }
 
 // NewDynamicTokenClients creates clients that extract the JWT from the MCP auth
 // context on each request. Used for HTTP mode where the token comes from OAuth.
-func NewDynamicTokenClients(baseURL string) *Clients {
+func NewDynamicTokenClients(baseURL string, o Options) *Clients {
 	interceptor := dynamicTokenInterceptor()
-	opts := connect.WithInterceptors(interceptor)
-	return newClients(baseURL, http.DefaultClient, opts)
+	limiter := newRateLimiters(o.RateLimit)
+	c := newClients(baseURL, o.httpClient(baseURL), o.clientOptions(limiter, interceptor), limiter)
+	c.fetch = o.fetchClient()
+	return c
+}
+
+// httpClient returns the HTTP client for backend calls, caching reads,
+// hedging them, and failing them over to the secondary backend when those
+// policies are enabled.
+// The fallback URL is validated with the rest of the configuration; one
+// that still fails to parse here disables failover.
+func (o Options) httpClient(baseURL string) *http.Client {
+	client := newHTTPClient(o.HTTP)
+	if o.Failover.URL != "" {
+		failover, err := newFailoverTransport(client.Transport, baseURL, o.Failover)
+		if err != nil {
+			slog.Error("failover disabled", "error", err)
+		} else {
+			client.Transport = failover
+		}
+	}
+	if o.Hedge.Delay > 0 {
+		client.Transport = &hedgingTransport{base: client.Transport, policy: o.Hedge}
+	}
+	if o.Cache.TTL > 0 {
+		client.Transport = newCachingTransport(client.Transport, o.Cache)
+	}
+	return client
+}
+
+// fetchClient returns the HTTP client for downloading URLs the backend hands
+// out. It shares the backend's proxy, CA, and client certificate but none of
+// its caching, hedging, or failover, and bounds each download by
+// fetchTimeout since no per-RPC timeout applies.
+func (o Options) fetchClient() *http.Client {
+	client := newHTTPClient(o.HTTP)
+	client.Timeout = fetchTimeout
+	return client
+}
+
+// clientOptions chains the configured interceptors around the auth
+// interceptor and applies the compression policy and protocol. The breaker
+// is outermost so a call that exhausts its retries counts as one failure,
+// and retries wrap the rate limit, timeouts, tracing, and metrics so each
+// attempt takes its own rate limit slot, timeout, span, and measurements.
+// The limiter applies o.RateLimit; it is passed in so its policy can be
+// changed later.
+func (o Options) clientOptions(limiter *rateLimiters, auth connect.Interceptor) connect.ClientOption {
+	return connect.WithClientOptions(
+		connect.WithInterceptors(
+			breakerInterceptor(o.Breaker),
+			retryInterceptor(o.Retry),
+			limiter.interceptor(),
+			timeoutInterceptor(o.Timeouts),
+			tracingInterceptor(),
+			metricsInterceptor(otel.Meter(observability.TracerName)),
+			correlationInterceptor(),
+			userAgentInterceptor(o.UserAgent),
+			auth,
+		),
+		o.Compression.clientOptions(),
+		o.Protocol.clientOption(),
+	)
 }
 
-func newClients(baseURL string, httpClient connect.HTTPClient, opts connect.ClientOption) *Clients {
-	grpc := connect.WithGRPC()
+func newClients(baseURL string, httpClient connect.HTTPClient, opts connect.ClientOption, limiter *rateLimiters) *Clients {
 	return &Clients{
-		Campaigns:     pidgrv1connect.NewCampaignServiceClient(httpClient, baseURL, grpc, opts),
-		Templates:     pidgrv1connect.NewTemplateServiceClient(httpClient, baseURL, grpc, opts),
-		Groups:        pidgrv1connect.NewGroupServiceClient(httpClient, baseURL, grpc, opts),
-		Teams:         pidgrv1connect.NewTeamServiceClient(httpClient, baseURL, grpc, opts),
-		Members:       pidgrv1connect.NewMemberServiceClient(httpClient, baseURL, grpc, opts),
-		Organizations: pidgrv1connect.NewOrganizationServiceClient(httpClient, baseURL, grpc, opts),
-		Roles:         pidgrv1connect.NewRoleServiceClient(httpClient, baseURL, grpc, opts),
-		ApiKeys:       pidgrv1connect.NewApiKeyServiceClient(httpClient, baseURL, grpc, opts),
-		Heatmaps:      pidgrv1connect.NewHeatmapServiceClient(httpClient, baseURL, grpc, opts),
-		Replays:       pidgrv1connect.NewReplayServiceClient(httpClient, baseURL, grpc, opts),
+		Campaigns:     pidgrv1connect.NewCampaignServiceClient(httpClient, baseURL, opts),
+		Templates:     pidgrv1connect.NewTemplateServiceClient(httpClient, baseURL, opts),
+		Groups:        pidgrv1connect.NewGroupServiceClient(httpClient, baseURL, opts),
+		Teams:         pidgrv1connect.NewTeamServiceClient(httpClient, baseURL, opts),
+		Members:       pidgrv1connect.NewMemberServiceClient(httpClient, baseURL, opts),
+		Organizations: pidgrv1connect.NewOrganizationServiceClient(httpClient, baseURL, opts),
+		Roles:         pidgrv1connect.NewRoleServiceClient(httpClient, baseURL, opts),
+		ApiKeys:       pidgrv1connect.NewApiKeyServiceClient(httpClient, baseURL, opts),
+		Heatmaps:      pidgrv1connect.NewHeatmapServiceClient(httpClient, baseURL, opts),
+		Replays:       pidgrv1connect.NewReplayServiceClient(httpClient, baseURL, opts),
+		rateLimit:     limiter,
 	}
 }
 
+// staticToken is the API key of static token clients, which may be
+// replaced while calls are in flight.
+type staticToken struct {
+	key atomic.Pointer[string]
+}
+
+func newStaticToken(key string) *staticToken {
+	t := &staticToken{}
+	t.set(key)
+	return t
+}
+
+func (t *staticToken) get() string    { return *t.key.Load() }
+func (t *staticToken) set(key string) { t.key.Store(&key) }
+
 // staticTokenInterceptor returns an interceptor that adds a static Bearer token header.
-func staticTokenInterceptor(token string) connect.UnaryInterceptorFunc {
+func staticTokenInterceptor(token *staticToken) connect.UnaryInterceptorFunc {
 	return func(next connect.UnaryFunc) connect.UnaryFunc {
 		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
-			req.Header().Set("Authorization", "Bearer "+token)
+			req.Header().Set("Authorization", "Bearer "+token.get())
 			return next(ctx, req)
 		}
 	}
@@ -82,3 +235,17 @@ func dynamicTokenInterceptor() connect.UnaryInterceptorFunc {
 		}
 	}
 }
+
+// correlationInterceptor forwards the tool call's correlation ID to the
+// backend, so backend logs for the call can be found by the ID the server
+// logged (see observability.CorrelationMiddleware).
+func correlationInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if id := observability.CorrelationID(ctx); id != "" {
+				req.Header().Set(observability.CorrelationHeader, id)
+			}
+			return next(ctx, req)
+		}
+	}
+}
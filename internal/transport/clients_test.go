@@ -7,15 +7,18 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
 	"connectrpc.com/connect"
 	mcpauth "github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/pidgr/pidgr-mcp/internal/observability"
 )
 
 func TestStaticTokenInterceptor(t *testing.T) {
-	interceptor := staticTokenInterceptor("pidgr_k_test123")
+	token := newStaticToken("pidgr_k_test123")
+	interceptor := staticTokenInterceptor(token)
 
 	var capturedHeader string
 	handler := interceptor(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
@@ -30,6 +33,13 @@ func TestStaticTokenInterceptor(t *testing.T) {
 	if capturedHeader != want {
 		t.Errorf("got Authorization %q, want %q", capturedHeader, want)
 	}
+
+	// A rotated key is used by later calls.
+	token.set("pidgr_k_rotated")
+	_, _ = handler(context.Background(), req)
+	if want := "Bearer pidgr_k_rotated"; capturedHeader != want {
+		t.Errorf("after rotation got Authorization %q, want %q", capturedHeader, want)
+	}
 }
 
 func TestDynamicTokenInterceptor(t *testing.T) {
@@ -94,13 +104,33 @@ func TestDynamicTokenInterceptor(t *testing.T) {
 	})
 }
 
+func TestCorrelationInterceptor(t *testing.T) {
+	var captured string
+	handler := correlationInterceptor()(func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		captured = req.Header().Get(observability.CorrelationHeader)
+		return nil, nil
+	})
+
+	ctx := observability.WithCorrelationID(context.Background(), "req-123")
+	_, _ = handler(ctx, connect.NewRequest(&struct{}{}))
+	if captured != "req-123" {
+		t.Errorf("got %s %q, want %q", observability.CorrelationHeader, captured, "req-123")
+	}
+
+	captured = ""
+	_, _ = handler(context.Background(), connect.NewRequest(&struct{}{}))
+	if captured != "" {
+		t.Errorf("expected no header without an ID, got %q", captured)
+	}
+}
+
 func TestNewStaticTokenClients(t *testing.T) {
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	}))
 	defer ts.Close()
 
-	clients := NewStaticTokenClients(ts.URL, "test-key")
+	clients := NewStaticTokenClients(ts.URL, "test-key", DefaultOptions())
 	if clients == nil {
 		t.Fatal("expected non-nil clients")
 	}
@@ -142,7 +172,7 @@ func TestNewDynamicTokenClients(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	clients := NewDynamicTokenClients(ts.URL)
+	clients := NewDynamicTokenClients(ts.URL, DefaultOptions())
 	if clients == nil {
 		t.Fatal("expected non-nil clients")
 	}
@@ -150,3 +180,30 @@ func TestNewDynamicTokenClients(t *testing.T) {
 		t.Error("expected non-nil Campaigns client")
 	}
 }
+
+func TestFetchClientUsesHTTPConfig(t *testing.T) {
+	var target string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target = r.URL.String()
+	}))
+	defer proxy.Close()
+
+	o := DefaultOptions()
+	o.HTTP.Proxy, _ = url.Parse(proxy.URL)
+	client := NewStaticTokenClients("http://api.pidgr.invalid", "test-key", o).FetchClient()
+	if client.Timeout != fetchTimeout {
+		t.Errorf("expected a %v timeout, got %v", fetchTimeout, client.Timeout)
+	}
+	resp, err := client.Get("http://cdn.pidgr.invalid/screenshots/home.png")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+	if target != "http://cdn.pidgr.invalid/screenshots/home.png" {
+		t.Errorf("expected the download to go through the proxy, got %q", target)
+	}
+
+	if (&Clients{}).FetchClient() == nil {
+		t.Error("expected a default client when none was configured")
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import "connectrpc.com/connect"
+
+// CompressionPolicy configures gzip compression of backend calls. Large
+// audience uploads and snapshot downloads shrink substantially, which
+// matters over WAN links.
+type CompressionPolicy struct {
+	// Gzip compresses requests and accepts gzip responses. When false,
+	// neither direction is compressed.
+	Gzip bool
+	// MinBytes is the smallest request message that is compressed; smaller
+	// messages are not worth the CPU.
+	MinBytes int
+}
+
+// DefaultCompressionPolicy gzips requests of 1 KiB or more.
+var DefaultCompressionPolicy = CompressionPolicy{Gzip: true, MinBytes: 1024}
+
+// clientOptions returns the Connect options implementing the policy.
+func (p CompressionPolicy) clientOptions() connect.ClientOption {
+	if !p.Gzip {
+		// Connect accepts gzip responses by default; unregister it.
+		return connect.WithAcceptCompression("gzip", nil, nil)
+	}
+	return connect.WithClientOptions(
+		connect.WithSendGzip(),
+		connect.WithCompressMinBytes(p.MinBytes),
+	)
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestCompressionPolicy(t *testing.T) {
+	const procedure = "/pidgr.v1.AudienceService/UploadAudience"
+	tests := []struct {
+		name           string
+		policy         CompressionPolicy
+		size           int
+		wantCompressed bool
+		wantAccept     bool
+	}{
+		{"large request", DefaultCompressionPolicy, 4096, true, true},
+		{"small request", DefaultCompressionPolicy, 16, false, true},
+		{"disabled", CompressionPolicy{}, 4096, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header http.Header
+			var compressed bool
+			handler := connect.NewUnaryHandler(procedure, func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+				return connect.NewResponse(req.Msg), nil
+			})
+			ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header.Clone()
+				// The first byte of a gRPC frame flags a compressed message.
+				body, _ := io.ReadAll(r.Body)
+				compressed = len(body) > 0 && body[0] == 1
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				handler.ServeHTTP(w, r)
+			}))
+			ts.EnableHTTP2 = true
+			ts.StartTLS()
+			defer ts.Close()
+
+			client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](ts.Client(), ts.URL+procedure, connect.WithGRPC(), tt.policy.clientOptions())
+			value := strings.Repeat("a", tt.size)
+			resp, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String(value)))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Msg.GetValue() != value {
+				t.Error("response did not round-trip")
+			}
+			if compressed != tt.wantCompressed {
+				t.Errorf("got compressed %v, want %v", compressed, tt.wantCompressed)
+			}
+			if got := strings.Contains(header.Get("Grpc-Accept-Encoding"), "gzip"); got != tt.wantAccept {
+				t.Errorf("got accepts gzip %v, want %v", got, tt.wantAccept)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FailoverPolicy configures failing reads over to a secondary backend when
+// the primary is unreachable. Writes always go to the primary, so they are
+// never applied to two backends.
+type FailoverPolicy struct {
+	// URL is the secondary backend's base URL; empty disables failover.
+	URL string
+	// ProbeInterval is how often a read is sent to the primary while it is
+	// down, to detect recovery.
+	ProbeInterval time.Duration
+}
+
+// DefaultFailoverPolicy probes a failed primary every 30s once a secondary
+// URL is configured.
+var DefaultFailoverPolicy = FailoverPolicy{ProbeInterval: 30 * time.Second}
+
+// failoverTransport sends reads to the secondary backend while the primary
+// is unreachable. Failover happens at the HTTP layer, where a read's body can
+// be buffered and replayed against the other backend.
+type failoverTransport struct {
+	base     http.RoundTripper
+	primary  *url.URL
+	fallback *url.URL
+	interval time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	downUntil time.Time // zero while the primary is up
+	probing   bool
+}
+
+func newFailoverTransport(base http.RoundTripper, primaryURL string, policy FailoverPolicy) (*failoverTransport, error) {
+	primary, err := url.Parse(primaryURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse API URL: %w", err)
+	}
+	fallback, err := url.Parse(policy.URL)
+	if err != nil || fallback.Host == "" {
+		return nil, fmt.Errorf("invalid fallback API URL %q", policy.URL)
+	}
+	return &failoverTransport{
+		base:     base,
+		primary:  primary,
+		fallback: fallback,
+		interval: policy.ProbeInterval,
+		now:      time.Now,
+	}, nil
+}
+
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, read := readPath(req.URL.Path); !read || req.Body == nil {
+		return t.base.RoundTrip(req)
+	}
+	clone, err := replayable(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.usePrimary() {
+		resp, err := t.base.RoundTrip(clone(req.Context()))
+		if err != nil && req.Context().Err() != nil {
+			// The caller gave up, which says nothing about the primary.
+			t.probeDone()
+			return resp, err
+		}
+		if !unreachable(req.Context(), resp, err) {
+			t.markUp()
+			return resp, err
+		}
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		t.markDown(err, resp)
+	}
+
+	r := clone(req.Context())
+	r.URL = t.fallbackURL(req.URL)
+	r.Host = ""
+	return t.base.RoundTrip(r)
+}
+
+// usePrimary reports whether a read should try the primary: always while
+// it is up, and once per probe interval while it is down.
+func (t *failoverTransport) usePrimary() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.downUntil.IsZero() {
+		return true
+	}
+	if !t.probing && !t.now().Before(t.downUntil) {
+		t.probing = true
+		return true
+	}
+	return false
+}
+
+func (t *failoverTransport) markUp() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.downUntil.IsZero() {
+		slog.Info("primary backend recovered", "url", t.primary.Redacted())
+	}
+	t.downUntil = time.Time{}
+	t.probing = false
+}
+
+// probeDone ends an inconclusive probe without changing the primary's state,
+// so the next read after the probe interval tries it again.
+func (t *failoverTransport) probeDone() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.probing = false
+}
+
+func (t *failoverTransport) markDown(err error, resp *http.Response) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.downUntil.IsZero() {
+		reason := "error"
+		if resp != nil {
+			reason = resp.Status
+		} else if err != nil {
+			reason = err.Error()
+		}
+		slog.Warn("primary backend unreachable, failing over reads", "url", t.primary.Redacted(), "fallback", t.fallback.Redacted(), "reason", reason)
+	}
+	t.downUntil = t.now().Add(t.interval)
+	t.probing = false
+}
+
+// fallbackURL moves a primary request URL onto the secondary backend,
+// replacing the primary's path prefix with the secondary's.
+func (t *failoverTransport) fallbackURL(u *url.URL) *url.URL {
+	out := *u
+	out.Scheme = t.fallback.Scheme
+	out.Host = t.fallback.Host
+	out.Path = strings.TrimSuffix(t.fallback.Path, "/") + "/" +
+		strings.TrimPrefix(strings.TrimPrefix(u.Path, strings.TrimSuffix(t.primary.Path, "/")), "/")
+	out.RawPath = ""
+	return &out
+}
+
+// unreachable reports whether a round trip failed because the backend could
+// not be reached, rather than because the caller gave up or the backend
+// answered. Gateway errors mean a proxy could not reach the backend.
+func unreachable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
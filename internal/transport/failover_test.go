@@ -0,0 +1,157 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// backend serves every path, answering 503 while down is set. It records
+// the last request path and body.
+type backend struct {
+	*httptest.Server
+	down     atomic.Bool
+	hits     atomic.Int32
+	lastPath atomic.Value
+	lastBody atomic.Value
+}
+
+func newBackend(t *testing.T) *backend {
+	t.Helper()
+	b := &backend{}
+	b.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b.hits.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		b.lastPath.Store(r.URL.Path)
+		b.lastBody.Store(string(body))
+		if b.down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(b.Close)
+	return b
+}
+
+func TestFailoverTransport(t *testing.T) {
+	primary, secondary := newBackend(t), newBackend(t)
+	ft, err := newFailoverTransport(http.DefaultTransport, primary.URL+"/api", FailoverPolicy{URL: secondary.URL + "/v2", ProbeInterval: time.Minute})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Now()
+	ft.now = func() time.Time { return now }
+	client := &http.Client{Transport: ft}
+
+	call := func(method string) int {
+		t.Helper()
+		resp, err := client.Post(primary.URL+"/api/pidgr.v1.CampaignService/"+method, "application/grpc", strings.NewReader("body-"+method))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// Primary up: reads stay on the primary.
+	if got := call("GetCampaign"); got != http.StatusOK || primary.hits.Load() != 1 || secondary.hits.Load() != 0 {
+		t.Fatalf("expected read served by primary, got status %d", got)
+	}
+
+	// Primary down: the read is replayed on the secondary, path prefix swapped.
+	primary.down.Store(true)
+	if got := call("ListCampaigns"); got != http.StatusOK {
+		t.Fatalf("expected failover to succeed, got status %d", got)
+	}
+	if got := secondary.lastPath.Load(); got != "/v2/pidgr.v1.CampaignService/ListCampaigns" {
+		t.Errorf("got fallback path %v", got)
+	}
+	if got := secondary.lastBody.Load(); got != "body-ListCampaigns" {
+		t.Errorf("got fallback body %v, want the replayed request body", got)
+	}
+
+	// While down, reads skip the primary and writes never fail over.
+	primaryHits := primary.hits.Load()
+	call("GetCampaign")
+	if primary.hits.Load() != primaryHits {
+		t.Error("expected reads to skip the primary while it is down")
+	}
+	if got := call("CreateCampaign"); got != http.StatusServiceUnavailable || primary.hits.Load() != primaryHits+1 {
+		t.Errorf("expected write to go to the primary, got status %d", got)
+	}
+
+	// After the probe interval a read probes the primary and recovers.
+	primary.down.Store(false)
+	now = now.Add(time.Minute)
+	secondaryHits := secondary.hits.Load()
+	call("GetCampaign")
+	call("GetCampaign")
+	if secondary.hits.Load() != secondaryHits {
+		t.Error("expected reads back on the primary after recovery")
+	}
+}
+
+func TestFailoverTransportUnreachablePrimary(t *testing.T) {
+	secondary := newBackend(t)
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	ft, err := newFailoverTransport(http.DefaultTransport, closed.URL, FailoverPolicy{URL: secondary.URL, ProbeInterval: time.Minute})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := (&http.Client{Transport: ft}).Post(closed.URL+"/pidgr.v1.HeatmapService/GetHeatmap", "application/grpc", strings.NewReader("x"))
+	if err != nil {
+		t.Fatalf("expected failover on connection error, got %v", err)
+	}
+	_ = resp.Body.Close()
+	if secondary.hits.Load() != 1 {
+		t.Errorf("got %d secondary hits, want 1", secondary.hits.Load())
+	}
+}
+
+func TestFailoverTransportCanceledProbe(t *testing.T) {
+	secondary := newBackend(t)
+	hang := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		<-r.Context().Done()
+	}))
+	t.Cleanup(hang.Close)
+
+	ft, err := newFailoverTransport(http.DefaultTransport, hang.URL, FailoverPolicy{URL: secondary.URL, ProbeInterval: time.Minute})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Now()
+	ft.now = func() time.Time { return now }
+	downUntil := now.Add(-time.Second)
+	ft.downUntil = downUntil
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, hang.URL+"/pidgr.v1.CampaignService/GetCampaign", strings.NewReader("x"))
+	if _, err := (&http.Client{Transport: ft}).Do(req); err == nil {
+		t.Fatal("expected the canceled probe to fail")
+	}
+	if !ft.downUntil.Equal(downUntil) || ft.probing {
+		t.Errorf("downUntil = %v, probing = %v; want the primary still down and a new probe allowed", ft.downUntil, ft.probing)
+	}
+	if secondary.hits.Load() != 0 {
+		t.Error("expected a canceled read not to fail over")
+	}
+}
+
+func TestNewFailoverTransportInvalidURL(t *testing.T) {
+	if _, err := newFailoverTransport(http.DefaultTransport, "https://api.pidgr.com", FailoverPolicy{URL: "not a url"}); err == nil {
+		t.Error("expected an error for a fallback URL without a host")
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// HedgePolicy configures hedged requests: when a read RPC to one of the
+// listed services has not answered within Delay, a second identical attempt
+// is sent and whichever answers first wins; the other is cancelled. Heatmap
+// and replay queries have a long latency tail that hedging cuts off at the
+// cost of some duplicate backend work.
+type HedgePolicy struct {
+	// Delay is how long to wait before hedging; zero disables hedging.
+	Delay time.Duration
+	// Services lists the services whose reads are hedged, by name with or
+	// without package, e.g. "HeatmapService".
+	Services []string
+}
+
+// DefaultHedgePolicy leaves hedging off, but lists the services it targets
+// once a delay is configured.
+var DefaultHedgePolicy = HedgePolicy{
+	Services: []string{"HeatmapService", "ReplayService"},
+}
+
+// hedged reports whether the RPC at an HTTP request path is hedged.
+func (p HedgePolicy) hedged(path string) bool {
+	if p.Delay <= 0 {
+		return false
+	}
+	service, ok := readPath(path)
+	if !ok {
+		return false
+	}
+	short := service[strings.LastIndex(service, ".")+1:]
+	return slices.Contains(p.Services, service) || slices.Contains(p.Services, short)
+}
+
+// readPath returns the service of the RPC at an HTTP request path, and
+// whether the RPC is a read (see isReadRPC). The path ends in the procedure,
+// after any prefix in the backend URL.
+func readPath(path string) (service string, ok bool) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 3 {
+		return "", false
+	}
+	service, method := parts[len(parts)-2], parts[len(parts)-1]
+	return service, isReadRPC(connect.Spec{Procedure: "/" + service + "/" + method})
+}
+
+// replayable buffers a request body so the request can be sent more than
+// once, returning a function that clones req with a fresh body.
+func replayable(req *http.Request) (func(ctx context.Context) *http.Request, error) {
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context) *http.Request {
+		r := req.Clone(ctx)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.ContentLength = int64(len(body))
+		return r
+	}, nil
+}
+
+// hedgingTransport sends hedged attempts at the HTTP layer, where a buffered
+// unary request body can be replayed; Connect requests cannot be cloned once
+// in the interceptor chain. Tracing, metrics, and retries above see a single
+// attempt.
+type hedgingTransport struct {
+	base   http.RoundTripper
+	policy HedgePolicy
+}
+
+type hedgeAttempt struct {
+	n    int
+	resp *http.Response
+	err  error
+}
+
+func (t *hedgingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || !t.policy.hedged(req.URL.Path) {
+		return t.base.RoundTrip(req)
+	}
+	clone, err := replayable(req)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := make(chan hedgeAttempt, 2)
+	var cancels []context.CancelFunc
+	send := func() {
+		ctx, cancel := context.WithCancel(req.Context())
+		n := len(cancels)
+		cancels = append(cancels, cancel)
+		r := clone(ctx)
+		go func() {
+			resp, err := t.base.RoundTrip(r)
+			attempts <- hedgeAttempt{n, resp, err}
+		}()
+	}
+
+	send()
+	timer := time.NewTimer(t.policy.Delay)
+	defer timer.Stop()
+	hedge := timer.C
+	pending := 1
+	for {
+		select {
+		case <-hedge:
+			hedge = nil
+			send()
+			pending++
+		case a := <-attempts:
+			pending--
+			if a.err != nil {
+				cancels[a.n]()
+				// Without another attempt in flight the failure is final;
+				// retrying is the retry interceptor's job.
+				if pending == 0 {
+					return nil, a.err
+				}
+				continue
+			}
+			for n, cancel := range cancels {
+				if n != a.n {
+					cancel()
+				}
+			}
+			// Drain the cancelled loser so its response is released.
+			go func(pending int) {
+				for range pending {
+					if l := <-attempts; l.resp != nil {
+						_ = l.resp.Body.Close()
+					}
+				}
+			}(pending)
+			a.resp.Body = &cancelOnClose{ReadCloser: a.resp.Body, cancel: cancels[a.n]}
+			return a.resp, nil
+		}
+	}
+}
+
+// cancelOnClose releases the winning attempt's context once its response
+// body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
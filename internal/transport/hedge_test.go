@@ -0,0 +1,131 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestHedgePolicyHedged(t *testing.T) {
+	policy := HedgePolicy{Delay: time.Second, Services: []string{"HeatmapService", "pidgr.v1.ReplayService"}}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/pidgr.v1.HeatmapService/GetHeatmap", true},
+		{"/api/pidgr.v1.HeatmapService/QueryHeatmapClicks", true},
+		{"/pidgr.v1.ReplayService/ListReplays", true},
+		{"/pidgr.v1.HeatmapService/DeleteHeatmap", false},
+		{"/pidgr.v1.CampaignService/GetCampaign", false},
+		{"/", false},
+	}
+	for _, tt := range tests {
+		if got := policy.hedged(tt.path); got != tt.want {
+			t.Errorf("hedged(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+	if (HedgePolicy{Services: policy.Services}).hedged("/pidgr.v1.HeatmapService/GetHeatmap") {
+		t.Error("expected zero delay to disable hedging")
+	}
+}
+
+// hedgeServer serves procedure over gRPC, answering attempt n after
+// delay(n). It reports how many attempts arrived and whether a slow attempt
+// was cancelled.
+func hedgeServer(t *testing.T, procedure string, delay func(n int) time.Duration) (*httptest.Server, *atomic.Int32, *atomic.Bool) {
+	t.Helper()
+	var attempts atomic.Int32
+	var cancelled atomic.Bool
+	mux := http.NewServeMux()
+	mux.Handle(procedure, connect.NewUnaryHandler(procedure, func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+		n := int(attempts.Add(1))
+		select {
+		case <-time.After(delay(n)):
+		case <-ctx.Done():
+			cancelled.Store(true)
+			return nil, ctx.Err()
+		}
+		return connect.NewResponse(wrapperspb.String(req.Msg.GetValue() + "-" + string(rune('0'+n)))), nil
+	}))
+	ts := httptest.NewUnstartedServer(mux)
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	t.Cleanup(ts.Close)
+	return ts, &attempts, &cancelled
+}
+
+func TestHedgingTransport(t *testing.T) {
+	policy := HedgePolicy{Delay: 20 * time.Millisecond, Services: []string{"HeatmapService"}}
+	slowFirst := func(n int) time.Duration {
+		if n == 1 {
+			return 5 * time.Second
+		}
+		return 0
+	}
+
+	t.Run("slow read is hedged", func(t *testing.T) {
+		const procedure = "/pidgr.v1.HeatmapService/GetHeatmap"
+		ts, attempts, cancelled := hedgeServer(t, procedure, slowFirst)
+		httpClient := &http.Client{Transport: &hedgingTransport{base: ts.Client().Transport, policy: policy}}
+		client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](httpClient, ts.URL+procedure, connect.WithGRPC())
+
+		start := time.Now()
+		resp, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("page")))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if time.Since(start) > 2*time.Second {
+			t.Errorf("hedged call took %v", time.Since(start))
+		}
+		if got := resp.Msg.GetValue(); got != "page-2" {
+			t.Errorf("got %q, want the hedge's answer with the replayed body", got)
+		}
+		if got := attempts.Load(); got != 2 {
+			t.Errorf("got %d attempts, want 2", got)
+		}
+		deadline := time.Now().Add(2 * time.Second)
+		for !cancelled.Load() && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if !cancelled.Load() {
+			t.Error("expected the losing attempt to be cancelled")
+		}
+	})
+
+	t.Run("fast read is not hedged", func(t *testing.T) {
+		const procedure = "/pidgr.v1.HeatmapService/GetHeatmap"
+		ts, attempts, _ := hedgeServer(t, procedure, func(int) time.Duration { return 0 })
+		httpClient := &http.Client{Transport: &hedgingTransport{base: ts.Client().Transport, policy: policy}}
+		client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](httpClient, ts.URL+procedure, connect.WithGRPC())
+
+		if _, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("page"))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := attempts.Load(); got != 1 {
+			t.Errorf("got %d attempts, want 1", got)
+		}
+	})
+
+	t.Run("write is not hedged", func(t *testing.T) {
+		const procedure = "/pidgr.v1.HeatmapService/DeleteHeatmap"
+		ts, attempts, _ := hedgeServer(t, procedure, func(int) time.Duration { return 100 * time.Millisecond })
+		httpClient := &http.Client{Transport: &hedgingTransport{base: ts.Client().Transport, policy: policy}}
+		client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](httpClient, ts.URL+procedure, connect.WithGRPC())
+
+		if _, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("h1"))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := attempts.Load(); got != 1 {
+			t.Errorf("got %d attempts, want 1", got)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// HTTPConfig tunes the HTTP client used for backend calls. Agents issue
+// bursts of parallel tool calls, so the idle pool must be large enough that
+// connections are reused rather than re-dialed for every call.
+type HTTPConfig struct {
+	// MaxIdleConnsPerHost is how many idle connections to keep per backend
+	// host; Go's default of 2 causes churn under bursts.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost caps connections per host; zero means no limit.
+	MaxConnsPerHost int
+	// IdleConnTimeout closes connections idle for this long.
+	IdleConnTimeout time.Duration
+	// DialTimeout bounds establishing a TCP connection.
+	DialTimeout time.Duration
+	// KeepAlive is the TCP keepalive interval.
+	KeepAlive time.Duration
+	// TLSHandshakeTimeout bounds the TLS handshake.
+	TLSHandshakeTimeout time.Duration
+	// RootCAs verifies the backend's certificate; nil uses the system pool.
+	RootCAs *x509.CertPool
+	// ClientCertificate is presented to the backend when it asks for a
+	// client certificate, for mutual TLS; nil presents none.
+	ClientCertificate *ClientCertificate
+	// Proxy routes backend calls through this proxy instead of the one
+	// named by HTTP_PROXY/HTTPS_PROXY (see ProxyFunc).
+	Proxy *url.URL
+}
+
+// DefaultHTTPConfig keeps up to 32 idle connections per host for 90s.
+var DefaultHTTPConfig = HTTPConfig{
+	MaxIdleConnsPerHost: 32,
+	IdleConnTimeout:     90 * time.Second,
+	DialTimeout:         10 * time.Second,
+	KeepAlive:           30 * time.Second,
+	TLSHandshakeTimeout: 10 * time.Second,
+}
+
+// fetchTimeout bounds a download of a URL the backend hands out, such as a
+// screenshot image.
+const fetchTimeout = 15 * time.Second
+
+// newHTTPClient builds a dedicated HTTP client for backend calls. It sets no
+// overall client timeout: per-RPC timeouts (see TimeoutPolicy) bound calls.
+func newHTTPClient(cfg HTTPConfig) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   cfg.DialTimeout,
+		KeepAlive: cfg.KeepAlive,
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:               ProxyFunc(cfg.Proxy),
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        max(cfg.MaxIdleConnsPerHost*4, 100),
+			MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+			MaxConnsPerHost:     cfg.MaxConnsPerHost,
+			IdleConnTimeout:     cfg.IdleConnTimeout,
+			TLSHandshakeTimeout: cfg.TLSHandshakeTimeout,
+			TLSClientConfig: &tls.Config{
+				MinVersion:           tls.VersionTLS12,
+				RootCAs:              cfg.RootCAs,
+				GetClientCertificate: cfg.ClientCertificate.get,
+			},
+		},
+	}
+}
+
+// ClientCertificate holds the certificate presented for mutual TLS. It can
+// be replaced while the server runs, e.g. when a rotated certificate is
+// fetched again; new TLS handshakes use the current one. Safe for
+// concurrent use.
+type ClientCertificate struct {
+	cert atomic.Pointer[tls.Certificate]
+}
+
+// NewClientCertificate holds cert.
+func NewClientCertificate(cert tls.Certificate) *ClientCertificate {
+	c := &ClientCertificate{}
+	c.Set(cert)
+	return c
+}
+
+// Set replaces the certificate presented on new connections.
+func (c *ClientCertificate) Set(cert tls.Certificate) { c.cert.Store(&cert) }
+
+// get returns the current certificate for a handshake. With no certificate
+// it returns an empty one, which tells the server the client has none.
+func (c *ClientCertificate) get(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	if c == nil {
+		return &tls.Certificate{}, nil
+	}
+	return c.cert.Load(), nil
+}
+
+// ProxyFunc returns the proxy selection for outbound requests. With a nil
+// proxy it follows HTTP_PROXY, HTTPS_PROXY, and NO_PROXY; otherwise every
+// request goes through proxy except hosts matched by NO_PROXY.
+func ProxyFunc(proxy *url.URL) func(*http.Request) (*url.URL, error) {
+	if proxy == nil {
+		return http.ProxyFromEnvironment
+	}
+	cfg := httpproxy.FromEnvironment()
+	cfg.HTTPProxy = proxy.String()
+	cfg.HTTPSProxy = proxy.String()
+	fn := cfg.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return fn(req.URL)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	client := newHTTPClient(DefaultHTTPConfig)
+	if client == http.DefaultClient {
+		t.Fatal("expected a dedicated client")
+	}
+	if client.Timeout != 0 {
+		t.Errorf("expected no overall client timeout, got %v", client.Timeout)
+	}
+	tr, ok := client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected *http.Transport, got %T", client.Transport)
+	}
+	if tr.MaxIdleConnsPerHost != DefaultHTTPConfig.MaxIdleConnsPerHost {
+		t.Errorf("got MaxIdleConnsPerHost %d, want %d", tr.MaxIdleConnsPerHost, DefaultHTTPConfig.MaxIdleConnsPerHost)
+	}
+	if tr.MaxIdleConns < tr.MaxIdleConnsPerHost {
+		t.Errorf("MaxIdleConns %d is below the per-host limit %d", tr.MaxIdleConns, tr.MaxIdleConnsPerHost)
+	}
+	if tr.IdleConnTimeout != DefaultHTTPConfig.IdleConnTimeout {
+		t.Errorf("got IdleConnTimeout %v, want %v", tr.IdleConnTimeout, DefaultHTTPConfig.IdleConnTimeout)
+	}
+	if !tr.ForceAttemptHTTP2 {
+		t.Error("expected HTTP/2 to be attempted")
+	}
+	if tr.TLSClientConfig.MinVersion != tls.VersionTLS12 {
+		t.Errorf("got TLS MinVersion %x, want TLS 1.2", tr.TLSClientConfig.MinVersion)
+	}
+}
+
+func TestProxyFunc(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "http://env-proxy:3128")
+	t.Setenv("NO_PROXY", "internal.example.com")
+	req := func(rawURL string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, rawURL, nil)
+	}
+
+	explicit, _ := url.Parse("http://corp-proxy:8080")
+	proxy := ProxyFunc(explicit)
+	if got, _ := proxy(req("https://api.pidgr.com/x")); got == nil || got.Host != "corp-proxy:8080" {
+		t.Errorf("expected the explicit proxy, got %v", got)
+	}
+	if got, _ := proxy(req("https://internal.example.com/x")); got != nil {
+		t.Errorf("expected NO_PROXY hosts to bypass the proxy, got %v", got)
+	}
+}
+
+func TestNewHTTPClientProxy(t *testing.T) {
+	var target string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target = r.URL.String()
+	}))
+	defer proxy.Close()
+
+	cfg := DefaultHTTPConfig
+	cfg.Proxy, _ = url.Parse(proxy.URL)
+	resp, err := newHTTPClient(cfg).Get("http://api.pidgr.invalid/pidgr.v1.CampaignService/GetCampaign")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+	if target != "http://api.pidgr.invalid/pidgr.v1.CampaignService/GetCampaign" {
+		t.Errorf("expected the request to go through the proxy, got %q", target)
+	}
+}
+
+// selfSignedClientCert returns a client certificate for cn and a pool
+// trusting it.
+func selfSignedClientCert(t *testing.T, cn string) (tls.Certificate, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool
+}
+
+func TestNewHTTPClientMutualTLS(t *testing.T) {
+	cert, clientCAs := selfSignedClientCert(t, "pidgr-mcp")
+	rotated, _ := selfSignedClientCert(t, "pidgr-mcp-rotated")
+	clientCAs.AddCert(rotated.Leaf)
+	var peer string
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		peer = r.TLS.PeerCertificates[0].Subject.CommonName
+	}))
+	ts.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	ts.StartTLS()
+	defer ts.Close()
+
+	cfg := DefaultHTTPConfig
+	cfg.RootCAs = x509.NewCertPool()
+	cfg.RootCAs.AddCert(ts.Certificate())
+
+	if _, err := newHTTPClient(cfg).Get(ts.URL); err == nil {
+		t.Error("expected the backend to reject a client without a certificate")
+	}
+
+	cfg.ClientCertificate = NewClientCertificate(cert)
+	client := newHTTPClient(cfg)
+	get := func() {
+		t.Helper()
+		resp, err := client.Get(ts.URL)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+	get()
+	if peer != "pidgr-mcp" {
+		t.Errorf("expected the backend to see the client certificate, got %q", peer)
+	}
+
+	// A replaced certificate is used from the next handshake on.
+	cfg.ClientCertificate.Set(rotated)
+	client.CloseIdleConnections()
+	get()
+	if peer != "pidgr-mcp-rotated" {
+		t.Errorf("expected the backend to see the rotated certificate, got %q", peer)
+	}
+}
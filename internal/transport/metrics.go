@@ -0,0 +1,76 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// clientMetrics records per-RPC metrics for backend calls, labeled by
+// service and method, so a slow or failing backend service stands out.
+// Instruments come from the global meter provider; they are no-ops until a
+// provider with an exporter is installed.
+type clientMetrics struct {
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+	inFlight metric.Int64UpDownCounter
+}
+
+func newClientMetrics(meter metric.Meter) *clientMetrics {
+	var m clientMetrics
+	var err error
+	if m.duration, err = meter.Float64Histogram("rpc.client.duration",
+		metric.WithDescription("Duration of backend RPC attempts."),
+		metric.WithUnit("s"),
+	); err != nil {
+		otel.Handle(err)
+	}
+	if m.errors, err = meter.Int64Counter("rpc.client.errors",
+		metric.WithDescription("Backend RPC attempts that failed, by error code."),
+		metric.WithUnit("{error}"),
+	); err != nil {
+		otel.Handle(err)
+	}
+	if m.inFlight, err = meter.Int64UpDownCounter("rpc.client.in_flight",
+		metric.WithDescription("Backend RPC attempts in progress."),
+		metric.WithUnit("{request}"),
+	); err != nil {
+		otel.Handle(err)
+	}
+	return &m
+}
+
+// metricsInterceptor records a latency histogram, error-code counter, and
+// in-flight gauge for each backend RPC attempt.
+func metricsInterceptor(meter metric.Meter) connect.UnaryInterceptorFunc {
+	m := newClientMetrics(meter)
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			service, method, _ := strings.Cut(strings.TrimPrefix(req.Spec().Procedure, "/"), "/")
+			rpc := metric.WithAttributes(semconv.RPCService(service), semconv.RPCMethod(method))
+
+			m.inFlight.Add(ctx, 1, rpc)
+			start := time.Now()
+			resp, err := next(ctx, req)
+			elapsed := time.Since(start).Seconds()
+			m.inFlight.Add(ctx, -1, rpc)
+
+			code := "ok"
+			if err != nil {
+				code = connect.CodeOf(err).String()
+				m.errors.Add(ctx, 1, rpc, metric.WithAttributes(attribute.String("rpc.code", code)))
+			}
+			m.duration.Record(ctx, elapsed, rpc, metric.WithAttributes(attribute.String("rpc.code", code)))
+			return resp, err
+		}
+	}
+}
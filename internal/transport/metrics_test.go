@@ -0,0 +1,99 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"connectrpc.com/connect"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// fakeMeter records every measurement as "instrument{attrs}" => sum.
+type fakeMeter struct {
+	noop.Meter
+	mu   sync.Mutex
+	sums map[string]float64
+}
+
+func (m *fakeMeter) add(name string, v float64, set attribute.Set) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sums[name+"{"+set.Encoded(attribute.DefaultEncoder())+"}"] += v
+}
+
+type fakeHistogram struct {
+	noop.Float64Histogram
+	m    *fakeMeter
+	name string
+}
+
+// Record counts observations rather than summing them, since durations vary.
+func (h fakeHistogram) Record(_ context.Context, _ float64, opts ...metric.RecordOption) {
+	h.m.add(h.name, 1, metric.NewRecordConfig(opts).Attributes())
+}
+
+type fakeCounter struct {
+	noop.Int64Counter
+	m    *fakeMeter
+	name string
+}
+
+func (c fakeCounter) Add(_ context.Context, v int64, opts ...metric.AddOption) {
+	c.m.add(c.name, float64(v), metric.NewAddConfig(opts).Attributes())
+}
+
+type fakeUpDownCounter struct {
+	noop.Int64UpDownCounter
+	m    *fakeMeter
+	name string
+}
+
+func (c fakeUpDownCounter) Add(_ context.Context, v int64, opts ...metric.AddOption) {
+	c.m.add(c.name, float64(v), metric.NewAddConfig(opts).Attributes())
+}
+
+func (m *fakeMeter) Float64Histogram(name string, _ ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	return fakeHistogram{m: m, name: name}, nil
+}
+
+func (m *fakeMeter) Int64Counter(name string, _ ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return fakeCounter{m: m, name: name}, nil
+}
+
+func (m *fakeMeter) Int64UpDownCounter(name string, _ ...metric.Int64UpDownCounterOption) (metric.Int64UpDownCounter, error) {
+	return fakeUpDownCounter{m: m, name: name}, nil
+}
+
+func TestMetricsInterceptor(t *testing.T) {
+	meter := &fakeMeter{sums: map[string]float64{}}
+	interceptor := connect.WithInterceptors(metricsInterceptor(meter))
+
+	if _, err := testCall(t, context.Background(), "/pidgr.v1.HeatmapService/GetHeatmap", func(int) error { return nil }, interceptor); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := testCall(t, context.Background(), "/pidgr.v1.HeatmapService/GetHeatmap", failFirst(1, connect.CodeUnavailable), interceptor); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	const rpc = "rpc.method=GetHeatmap,rpc.service=pidgr.v1.HeatmapService"
+	want := map[string]float64{
+		"rpc.client.duration{rpc.code=ok," + rpc + "}":          1,
+		"rpc.client.duration{rpc.code=unavailable," + rpc + "}": 1,
+		"rpc.client.errors{rpc.code=unavailable," + rpc + "}":   1,
+		"rpc.client.in_flight{" + rpc + "}":                     0,
+	}
+	for key, v := range want {
+		if got, ok := meter.sums[key]; !ok || got != v {
+			t.Errorf("%s = %v (recorded %v), want %v", key, got, ok, v)
+		}
+	}
+	if len(meter.sums) != len(want) {
+		t.Errorf("unexpected measurements: %v", meter.sums)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+
+	"connectrpc.com/connect"
+	pidgrv1 "github.com/pidgr/pidgr-proto/gen/go/pidgr/v1"
+)
+
+// Preflight makes one cheap call to the backend at startup, so a
+// misconfigured deployment fails with a precise diagnostic instead of every
+// tool call returning "Service unavailable". When checkAuth is set (a static
+// API key), the call must also be authenticated; otherwise an
+// Unauthenticated answer still proves the backend is reachable.
+func Preflight(ctx context.Context, c *Clients, baseURL string, checkAuth bool) error {
+	_, err := c.Organizations.GetOrganization(ctx, connect.NewRequest(&pidgrv1.GetOrganizationRequest{}))
+	return preflightError(baseURL, checkAuth, err)
+}
+
+// preflightError interprets the outcome of the preflight call.
+func preflightError(baseURL string, checkAuth bool, err error) error {
+	switch connect.CodeOf(err) {
+	case connect.CodeUnauthenticated:
+		if !checkAuth {
+			return nil
+		}
+		return fmt.Errorf("backend %s rejected PIDGR_API_KEY: check that the key is valid and not revoked", baseURL)
+	case connect.CodePermissionDenied:
+		// Authenticated; the key just cannot read the organization.
+		return nil
+	}
+	if err == nil {
+		return nil
+	}
+	return diagnose(baseURL, err)
+}
+
+// diagnose turns a failed preflight call into an error naming the likely
+// cause and the setting to check.
+func diagnose(baseURL string, err error) error {
+	var dnsErr *net.DNSError
+	var unknownCA x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	var alert tls.AlertError
+	switch {
+	case errors.As(err, &dnsErr):
+		return fmt.Errorf("backend %s: cannot resolve host %s: check PIDGR_API_URL and DNS", baseURL, dnsErr.Name)
+	case strings.Contains(err.Error(), "proxyconnect"):
+		return fmt.Errorf("backend %s: cannot connect through the proxy: check PIDGR_PROXY_URL and HTTPS_PROXY: %w", baseURL, err)
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return fmt.Errorf("backend %s refused the connection: check PIDGR_API_URL", baseURL)
+	case errors.As(err, &unknownCA):
+		return fmt.Errorf("backend %s: certificate is signed by an unknown authority: set PIDGR_MCP_API_CA_FILE if it uses a private CA", baseURL)
+	case errors.As(err, &hostname), errors.As(err, &invalid):
+		return fmt.Errorf("backend %s: certificate is not valid: %w", baseURL, err)
+	case errors.As(err, &alert) || strings.Contains(err.Error(), "remote error: tls"):
+		return fmt.Errorf("backend %s rejected the TLS handshake: check PIDGR_MCP_API_CERT_FILE if it requires a client certificate: %w", baseURL, err)
+	case errors.Is(err, context.DeadlineExceeded) || connect.CodeOf(err) == connect.CodeDeadlineExceeded:
+		return fmt.Errorf("backend %s did not respond in time: check PIDGR_API_URL and network access", baseURL)
+	case connect.CodeOf(err) == connect.CodeUnimplemented || connect.CodeOf(err) == connect.CodeNotFound:
+		return fmt.Errorf("backend %s does not serve the pidgr API: check PIDGR_API_URL and PIDGR_MCP_API_PROTOCOL", baseURL)
+	}
+	return fmt.Errorf("backend %s is unreachable: %w", baseURL, err)
+}
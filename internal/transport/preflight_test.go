@@ -0,0 +1,115 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/pidgr/pidgr-proto/gen/go/pidgr/v1/pidgrv1connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// preflightBackend serves GetOrganization, failing with err when set.
+func preflightBackend(err error) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(pidgrv1connect.OrganizationServiceGetOrganizationProcedure, connect.NewUnaryHandler(pidgrv1connect.OrganizationServiceGetOrganizationProcedure,
+		func(context.Context, *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+			if err != nil {
+				return nil, err
+			}
+			return connect.NewResponse(&emptypb.Empty{}), nil
+		}))
+	return mux
+}
+
+func preflightOptions() Options {
+	o := DefaultOptions()
+	o.Retry.MaxAttempts = 1
+	return o
+}
+
+// preflight makes the preflight call to baseURL with the client stack o
+// configures and interprets the result.
+func preflight(baseURL string, o Options, checkAuth bool) error {
+	procedure := pidgrv1connect.OrganizationServiceGetOrganizationProcedure
+	client := connect.NewClient[emptypb.Empty, emptypb.Empty](o.httpClient(baseURL), baseURL+procedure, o.clientOptions(newRateLimiters(o.RateLimit), staticTokenInterceptor(newStaticToken("key"))))
+	_, err := client.CallUnary(context.Background(), connect.NewRequest(&emptypb.Empty{}))
+	return preflightError(baseURL, checkAuth, err)
+}
+
+func TestPreflight(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		checkAuth bool
+		want      string
+	}{
+		{name: "ok", checkAuth: true},
+		{name: "rejected key", err: connect.NewError(connect.CodeUnauthenticated, errors.New("bad key")), checkAuth: true, want: "rejected PIDGR_API_KEY"},
+		{name: "no token in http mode", err: connect.NewError(connect.CodeUnauthenticated, errors.New("no token"))},
+		{name: "permission denied", err: connect.NewError(connect.CodePermissionDenied, errors.New("no")), checkAuth: true},
+		{name: "down", err: connect.NewError(connect.CodeInternal, errors.New("boom")), checkAuth: true, want: "is unreachable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewUnstartedServer(preflightBackend(tt.err))
+			ts.EnableHTTP2 = true
+			ts.StartTLS()
+			defer ts.Close()
+
+			o := preflightOptions()
+			o.HTTP.RootCAs = ts.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+			err := preflight(ts.URL, o, tt.checkAuth)
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreflightDiagnostics(t *testing.T) {
+	refused := httptest.NewServer(http.NotFoundHandler())
+	refused.Close()
+
+	untrusted := httptest.NewTLSServer(http.NotFoundHandler())
+	defer untrusted.Close()
+
+	notPidgr := httptest.NewUnstartedServer(http.NotFoundHandler())
+	notPidgr.EnableHTTP2 = true
+	notPidgr.StartTLS()
+	defer notPidgr.Close()
+	trusted := preflightOptions()
+	trusted.HTTP.RootCAs = notPidgr.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+	tests := []struct {
+		name string
+		url  string
+		o    Options
+		want string
+	}{
+		{"connection refused", refused.URL, preflightOptions(), "refused the connection"},
+		{"unknown CA", untrusted.URL, preflightOptions(), "PIDGR_MCP_API_CA_FILE"},
+		{"not a pidgr API", notPidgr.URL, trusted, "does not serve the pidgr API"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := preflight(tt.url, tt.o, true)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
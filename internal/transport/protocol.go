@@ -0,0 +1,49 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"fmt"
+
+	"connectrpc.com/connect"
+)
+
+// Protocol is the wire protocol used for backend calls. gRPC needs a path
+// that passes HTTP/2 trailers end to end; gRPC-Web and Connect work through
+// proxies and gateways that only speak plain HTTP.
+type Protocol string
+
+const (
+	// ProtocolGRPC is gRPC over HTTP/2 with binary protobuf.
+	ProtocolGRPC Protocol = "grpc"
+	// ProtocolGRPCWeb is gRPC-Web, which carries the status in the body
+	// instead of HTTP trailers.
+	ProtocolGRPCWeb Protocol = "grpcweb"
+	// ProtocolConnect is the Connect protocol with JSON messages.
+	ProtocolConnect Protocol = "connect"
+)
+
+// DefaultProtocol is gRPC.
+const DefaultProtocol = ProtocolGRPC
+
+// ParseProtocol validates a protocol name.
+func ParseProtocol(s string) (Protocol, error) {
+	switch p := Protocol(s); p {
+	case ProtocolGRPC, ProtocolGRPCWeb, ProtocolConnect:
+		return p, nil
+	}
+	return "", fmt.Errorf("protocol must be 'grpc', 'grpcweb', or 'connect', got %q", s)
+}
+
+// clientOption returns the Connect option selecting the protocol.
+func (p Protocol) clientOption() connect.ClientOption {
+	switch p {
+	case ProtocolGRPCWeb:
+		return connect.WithGRPCWeb()
+	case ProtocolConnect:
+		// Connect is the client's default protocol; only the codec changes.
+		return connect.WithProtoJSON()
+	}
+	return connect.WithGRPC()
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestParseProtocol(t *testing.T) {
+	for _, s := range []string{"grpc", "grpcweb", "connect"} {
+		if p, err := ParseProtocol(s); err != nil || string(p) != s {
+			t.Errorf("ParseProtocol(%q) = %q, %v", s, p, err)
+		}
+	}
+	if _, err := ParseProtocol("grpc-web"); err == nil {
+		t.Error("expected an unknown protocol to be rejected")
+	}
+}
+
+func TestProtocols(t *testing.T) {
+	tests := []struct {
+		protocol    Protocol
+		contentType string
+	}{
+		{ProtocolGRPC, "application/grpc"},
+		{ProtocolGRPCWeb, "application/grpc-web"},
+		{ProtocolConnect, "application/json"},
+	}
+	const procedure = "/pidgr.v1.CampaignService/GetCampaign"
+	for _, tt := range tests {
+		t.Run(string(tt.protocol), func(t *testing.T) {
+			var calls atomic.Int32
+			var contentType atomic.Value
+			handler := connect.NewUnaryHandler(procedure, func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+				calls.Add(1)
+				if req.Msg.GetValue() == "missing" {
+					return nil, connect.NewError(connect.CodeNotFound, errors.New("not found"))
+				}
+				return connect.NewResponse(wrapperspb.String("ok")), nil
+			})
+			ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contentType.Store(r.Header.Get("Content-Type"))
+				handler.ServeHTTP(w, r)
+			}))
+			ts.EnableHTTP2 = true
+			ts.StartTLS()
+			defer ts.Close()
+
+			// Reads go through the cache, which must recognize each
+			// protocol's success and failure.
+			httpClient := &http.Client{Transport: newCachingTransport(ts.Client().Transport, CachePolicy{TTL: time.Minute, MaxEntries: 10})}
+			client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](httpClient, ts.URL+procedure, tt.protocol.clientOption())
+			for range 2 {
+				resp, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("c1")))
+				if err != nil || resp.Msg.GetValue() != "ok" {
+					t.Fatalf("got %v, %v", resp, err)
+				}
+			}
+			if got, _ := contentType.Load().(string); got != tt.contentType && got != tt.contentType+"+proto" {
+				t.Errorf("Content-Type = %q, want %s", got, tt.contentType)
+			}
+			if n := calls.Load(); n != 1 {
+				t.Errorf("expected the second read to be cached, got %d calls", n)
+			}
+
+			for range 2 {
+				if _, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("missing"))); connect.CodeOf(err) != connect.CodeNotFound {
+					t.Fatalf("got error %v, want not_found", err)
+				}
+			}
+			if n := calls.Load(); n != 3 {
+				t.Errorf("expected errors not to be cached, got %d calls", n)
+			}
+		})
+	}
+}
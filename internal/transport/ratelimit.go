@@ -0,0 +1,170 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/modelcontextprotocol/go-sdk/auth"
+)
+
+// RateLimitPolicy caps how fast each organization's tool calls reach the
+// backend, so one tenant cannot use up the server's whole backend quota.
+type RateLimitPolicy struct {
+	// Rate is the sustained backend calls per second allowed per
+	// organization; zero disables rate limiting.
+	Rate float64
+	// Burst is how many calls an organization may make at once before
+	// Rate applies.
+	Burst int
+	// MaxWait is how long a call may wait for its turn before it is
+	// rejected with ResourceExhausted.
+	MaxWait time.Duration
+}
+
+// DefaultRateLimitPolicy leaves rate limiting off; when Rate is set, orgs
+// may burst 20 calls and calls wait up to 2s for a slot.
+var DefaultRateLimitPolicy = RateLimitPolicy{
+	Burst:   20,
+	MaxWait: 2 * time.Second,
+}
+
+// maxIdleBuckets is how many organizations' buckets are kept before full
+// (idle) buckets are dropped.
+const maxIdleBuckets = 1024
+
+// RateLimitedError is returned, wrapped in a Connect ResourceExhausted error,
+// for calls rejected by the per-organization rate limit.
+type RateLimitedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return "organization rate limit exceeded"
+}
+
+// UserMessage explains the rejection to users; convert.ErrorMessage shows it
+// in place of the generic message.
+func (e *RateLimitedError) UserMessage() string {
+	return fmt.Sprintf("Too many requests for this organization; retry in %d seconds", retrySeconds(e.RetryAfter))
+}
+
+// bucket is the token bucket of one organization.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiters tracks a token bucket per organization.
+type rateLimiters struct {
+	now func() time.Time
+	key func(context.Context) string
+
+	mu      sync.Mutex
+	policy  RateLimitPolicy
+	buckets map[string]*bucket
+}
+
+func newRateLimiters(policy RateLimitPolicy) *rateLimiters {
+	return &rateLimiters{policy: policy, now: time.Now, key: orgID, buckets: map[string]*bucket{}}
+}
+
+// setPolicy replaces the policy. Existing buckets keep their tokens, capped
+// at the new burst on their next call.
+func (l *rateLimiters) setPolicy(policy RateLimitPolicy) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.policy = policy
+}
+
+// reserve takes a token from org's bucket and returns how long the caller
+// must wait before using it. When the wait would exceed MaxWait no token is
+// taken and ok is false. Calls are never delayed while rate limiting is off.
+func (l *rateLimiters) reserve(org string) (wait time.Duration, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.policy.Rate <= 0 {
+		return 0, true
+	}
+	now := l.now()
+	burst := float64(max(l.policy.Burst, 1))
+	b := l.buckets[org]
+	if b == nil {
+		if len(l.buckets) >= maxIdleBuckets {
+			l.pruneLocked(now)
+		}
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[org] = b
+	}
+	b.tokens = min(burst, b.tokens+now.Sub(b.last).Seconds()*l.policy.Rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	wait = time.Duration((1 - b.tokens) / l.policy.Rate * float64(time.Second))
+	if wait > l.policy.MaxWait {
+		return wait, false
+	}
+	b.tokens--
+	return wait, true
+}
+
+// pruneLocked drops buckets that have refilled, since a new bucket for the
+// same organization would start in the same state.
+func (l *rateLimiters) pruneLocked(now time.Time) {
+	burst := float64(max(l.policy.Burst, 1))
+	for org, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.policy.Rate >= burst {
+			delete(l.buckets, org)
+		}
+	}
+}
+
+// orgID returns the organization of the caller, from the OAuth token info.
+// Calls without one (stdio mode) share a single bucket.
+func orgID(ctx context.Context) string {
+	if ti := auth.TokenInfoFromContext(ctx); ti != nil {
+		if org, ok := ti.Extra["org_id"].(string); ok {
+			return org
+		}
+	}
+	return ""
+}
+
+// interceptor delays or rejects calls from organizations that exceed the
+// configured rate.
+func (l *rateLimiters) interceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			wait, ok := l.reserve(l.key(ctx))
+			if !ok {
+				err := connect.NewError(connect.CodeResourceExhausted, &RateLimitedError{RetryAfter: wait})
+				err.Meta().Set("Retry-After", strconv.Itoa(retrySeconds(wait)))
+				return nil, err
+			}
+			if wait > 0 {
+				t := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					t.Stop()
+					code := connect.CodeCanceled
+					if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+						code = connect.CodeDeadlineExceeded
+					}
+					return nil, connect.NewError(code, ctx.Err())
+				case <-t.C:
+				}
+			}
+			return next(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/modelcontextprotocol/go-sdk/auth"
+)
+
+type orgKey struct{}
+
+func withOrg(org string) context.Context {
+	return context.WithValue(context.Background(), orgKey{}, org)
+}
+
+// testRateLimit returns a rate limit interceptor keyed by withOrg.
+func testRateLimit(policy RateLimitPolicy) connect.Interceptor {
+	l := newRateLimiters(policy)
+	l.key = func(ctx context.Context) string {
+		org, _ := ctx.Value(orgKey{}).(string)
+		return org
+	}
+	return l.interceptor()
+}
+
+func TestOrgID(t *testing.T) {
+	verifier := func(ctx context.Context, token string, r *http.Request) (*auth.TokenInfo, error) {
+		return &auth.TokenInfo{Expiration: time.Now().Add(time.Hour), Extra: map[string]any{"org_id": "org-" + token}}, nil
+	}
+	var got string
+	handler := auth.RequireBearerToken(verifier, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = orgID(r.Context())
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	req.Header.Set("Authorization", "Bearer acme")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if got != "org-acme" {
+		t.Errorf("orgID = %q, want org-acme", got)
+	}
+	if org := orgID(context.Background()); org != "" {
+		t.Errorf("orgID without token info = %q, want empty", org)
+	}
+}
+
+func TestRateLimiterReserve(t *testing.T) {
+	now := time.Date(2026, 3, 2, 14, 30, 0, 0, time.UTC)
+	l := newRateLimiters(RateLimitPolicy{Rate: 2, Burst: 3, MaxWait: time.Second})
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if wait, ok := l.reserve("acme"); !ok || wait != 0 {
+			t.Fatalf("call %d: expected the burst to pass immediately, got %v, %v", i, wait, ok)
+		}
+	}
+	if wait, ok := l.reserve("acme"); !ok || wait != 500*time.Millisecond {
+		t.Errorf("expected to wait 500ms for the next slot, got %v, %v", wait, ok)
+	}
+	if wait, ok := l.reserve("acme"); !ok || wait != time.Second {
+		t.Errorf("expected to wait 1s for the slot after, got %v, %v", wait, ok)
+	}
+	if _, ok := l.reserve("acme"); ok {
+		t.Error("expected a call that would wait past MaxWait to be rejected")
+	}
+
+	// Other organizations have their own bucket.
+	if wait, ok := l.reserve("globex"); !ok || wait != 0 {
+		t.Errorf("expected another org to pass immediately, got %v, %v", wait, ok)
+	}
+
+	// The bucket refills at Rate.
+	now = now.Add(5 * time.Second)
+	for i := 0; i < 3; i++ {
+		if wait, ok := l.reserve("acme"); !ok || wait != 0 {
+			t.Fatalf("call %d after refill: got %v, %v", i, wait, ok)
+		}
+	}
+}
+
+func TestRateLimiterSetPolicy(t *testing.T) {
+	now := time.Date(2026, 3, 2, 14, 30, 0, 0, time.UTC)
+	l := newRateLimiters(DefaultRateLimitPolicy)
+	l.now = func() time.Time { return now }
+	for i := 0; i < 50; i++ {
+		if wait, ok := l.reserve("acme"); !ok || wait != 0 {
+			t.Fatalf("call %d: expected no limit while Rate is zero, got %v, %v", i, wait, ok)
+		}
+	}
+
+	l.setPolicy(RateLimitPolicy{Rate: 1, Burst: 1})
+	if _, ok := l.reserve("acme"); !ok {
+		t.Fatal("expected the first call under the new policy through")
+	}
+	if _, ok := l.reserve("acme"); ok {
+		t.Error("expected the new policy to reject the second call")
+	}
+
+	l.setPolicy(DefaultRateLimitPolicy)
+	if _, ok := l.reserve("acme"); !ok {
+		t.Error("expected calls through once rate limiting is off again")
+	}
+}
+
+func TestRateLimiterPrunesIdleBuckets(t *testing.T) {
+	now := time.Date(2026, 3, 2, 14, 30, 0, 0, time.UTC)
+	l := newRateLimiters(RateLimitPolicy{Rate: 1, Burst: 1, MaxWait: time.Second})
+	l.now = func() time.Time { return now }
+	for i := 0; i < maxIdleBuckets; i++ {
+		l.reserve(string(rune('a'+i%26)) + strings.Repeat("x", i/26))
+	}
+	now = now.Add(time.Minute)
+	l.reserve("new")
+	if len(l.buckets) != 1 {
+		t.Errorf("expected refilled buckets to be dropped, %d left", len(l.buckets))
+	}
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	opts := connect.WithInterceptors(testRateLimit(RateLimitPolicy{Rate: 1, Burst: 1, MaxWait: 0}))
+	const procedure = "/pidgr.v1.CampaignService/ListCampaigns"
+	up := func(int) error { return nil }
+
+	if attempts, err := testCall(t, withOrg("acme"), procedure, up, opts); attempts != 1 || err != nil {
+		t.Fatalf("expected the first call through, got %d attempts, %v", attempts, err)
+	}
+	attempts, err := testCall(t, withOrg("acme"), procedure, up, opts)
+	if attempts != 0 {
+		t.Error("expected a rate-limited call not to reach the backend")
+	}
+	var limited *RateLimitedError
+	if connect.CodeOf(err) != connect.CodeResourceExhausted || !errors.As(err, &limited) {
+		t.Fatalf("expected a RateLimitedError, got %v", err)
+	}
+	if msg := limited.UserMessage(); !strings.Contains(msg, "retry in 1 seconds") {
+		t.Errorf("unexpected message %q", msg)
+	}
+	if attempts, err := testCall(t, withOrg("globex"), procedure, up, opts); attempts != 1 || err != nil {
+		t.Errorf("expected another org to be called, got %d attempts, %v", attempts, err)
+	}
+}
+
+func TestRateLimitInterceptorWaitsForSlot(t *testing.T) {
+	opts := connect.WithInterceptors(testRateLimit(RateLimitPolicy{Rate: 5, Burst: 1, MaxWait: time.Second}))
+	const procedure = "/pidgr.v1.CampaignService/ListCampaigns"
+	up := func(int) error { return nil }
+
+	testCall(t, withOrg("acme"), procedure, up, opts)
+	start := time.Now()
+	if attempts, err := testCall(t, withOrg("acme"), procedure, up, opts); attempts != 1 || err != nil {
+		t.Fatalf("expected the call to wait and go through, got %d attempts, %v", attempts, err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Errorf("expected the call to wait for a slot, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(withOrg("acme"))
+	cancel()
+	if _, err := testCall(t, ctx, procedure, up, opts); connect.CodeOf(err) != connect.CodeCanceled {
+		t.Errorf("expected a cancelled wait to fail with Canceled, got %v", err)
+	}
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+	opts := connect.WithInterceptors(testRateLimit(DefaultRateLimitPolicy))
+	for i := 0; i < 50; i++ {
+		if attempts, err := testCall(t, withOrg("acme"), "/pidgr.v1.CampaignService/ListCampaigns", func(int) error { return nil }, opts); attempts != 1 || err != nil {
+			t.Fatalf("call %d: expected no limit by default, got %d attempts, %v", i, attempts, err)
+		}
+	}
+}
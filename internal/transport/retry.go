@@ -0,0 +1,101 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// RetryPolicy controls automatic retries of read RPCs that fail with a
+// transient error.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry; it doubles per retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff between attempts.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy retries twice, waiting up to about 100ms and 200ms,
+// which rides out backend restarts without noticeably delaying failures.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+}
+
+// readPrefixes are the RPC method name prefixes of read-only RPCs, which are
+// safe to repeat.
+var readPrefixes = []string{"Get", "List", "Search", "Query"}
+
+// isReadRPC reports whether the procedure can be repeated without side
+// effects, either by its declared idempotency level or by naming convention.
+func isReadRPC(spec connect.Spec) bool {
+	if spec.IdempotencyLevel == connect.IdempotencyNoSideEffects || spec.IdempotencyLevel == connect.IdempotencyIdempotent {
+		return true
+	}
+	method := spec.Procedure[strings.LastIndex(spec.Procedure, "/")+1:]
+	for _, prefix := range readPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTransient reports whether an error is worth retrying: the backend was
+// unreachable or timed out, not that the request was wrong.
+func isTransient(err error) bool {
+	switch connect.CodeOf(err) {
+	case connect.CodeUnavailable, connect.CodeDeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// backoff returns the wait before retry n (1-based): exponential from
+// BaseDelay, capped at MaxDelay, with full jitter so concurrent callers don't
+// retry in lockstep.
+func (p RetryPolicy) backoff(n int) time.Duration {
+	d := p.BaseDelay << (n - 1)
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return time.Duration(rand.Int64N(int64(d) + 1))
+}
+
+// retryInterceptor retries read RPCs that fail with Unavailable or
+// DeadlineExceeded, following policy. Writes are never retried, since the
+// first attempt may have been applied. Retries stop when ctx is done.
+func retryInterceptor(policy RetryPolicy) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if policy.MaxAttempts <= 1 || !isReadRPC(req.Spec()) {
+				return next(ctx, req)
+			}
+			for attempt := 1; ; attempt++ {
+				resp, err := next(ctx, req)
+				if err == nil || attempt >= policy.MaxAttempts || !isTransient(err) || ctx.Err() != nil {
+					return resp, err
+				}
+				wait := policy.backoff(attempt)
+				slog.Warn("retrying backend call", "procedure", req.Spec().Procedure, "attempt", attempt, "code", connect.CodeOf(err), "wait", wait)
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return resp, err
+				case <-timer.C:
+				}
+			}
+		}
+	}
+}
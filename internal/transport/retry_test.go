@@ -0,0 +1,97 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// testCall serves procedure with handle, which receives the 1-based attempt
+// number, and calls it once through a client with the given options. It
+// returns how many attempts reached the server and the call's error.
+func testCall(t *testing.T, ctx context.Context, procedure string, handle func(attempt int) error, opts ...connect.ClientOption) (int, error) {
+	t.Helper()
+	var attempts atomic.Int32
+	mux := http.NewServeMux()
+	mux.Handle(procedure, connect.NewUnaryHandler(procedure, func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+		if err := handle(int(attempts.Add(1))); err != nil {
+			return nil, err
+		}
+		return connect.NewResponse(&emptypb.Empty{}), nil
+	}))
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+
+	client := connect.NewClient[emptypb.Empty, emptypb.Empty](ts.Client(), ts.URL+procedure, opts...)
+	_, err := client.CallUnary(ctx, connect.NewRequest(&emptypb.Empty{}))
+	return int(attempts.Load()), err
+}
+
+var fastRetries = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+func failFirst(n int, code connect.Code) func(int) error {
+	return func(attempt int) error {
+		if attempt <= n {
+			return connect.NewError(code, errors.New("transient"))
+		}
+		return nil
+	}
+}
+
+func TestRetryInterceptor(t *testing.T) {
+	tests := []struct {
+		name      string
+		procedure string
+		handle    func(int) error
+		wantErr   bool
+		attempts  int
+	}{
+		{"read recovers", "/pidgr.v1.CampaignService/ListCampaigns", failFirst(2, connect.CodeUnavailable), false, 3},
+		{"read gives up", "/pidgr.v1.CampaignService/GetCampaign", failFirst(5, connect.CodeUnavailable), true, 3},
+		{"write not retried", "/pidgr.v1.CampaignService/CreateCampaign", failFirst(1, connect.CodeUnavailable), true, 1},
+		{"client error not retried", "/pidgr.v1.CampaignService/GetCampaign", failFirst(1, connect.CodeNotFound), true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts, err := testCall(t, context.Background(), tt.procedure, tt.handle, connect.WithInterceptors(retryInterceptor(fastRetries)))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestRetryStopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	slow := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	handle := func(int) error {
+		cancel()
+		return connect.NewError(connect.CodeUnavailable, errors.New("down"))
+	}
+	attempts, _ := testCall(t, ctx, "/pidgr.v1.CampaignService/ListCampaigns", handle, connect.WithInterceptors(retryInterceptor(slow)))
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1 after cancellation", attempts)
+	}
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: 3 * time.Second}
+	for n := 1; n < 70; n++ {
+		if d := p.backoff(n); d < 0 || d > p.MaxDelay {
+			t.Fatalf("backoff(%d) = %v, want within [0, %v]", n, d, p.MaxDelay)
+		}
+	}
+}
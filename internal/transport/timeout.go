@@ -0,0 +1,78 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// TimeoutPolicy bounds each backend RPC attempt, so a hung backend cannot
+// hold a tool call open for the whole MCP request lifetime. Connect sends the
+// resulting deadline to the backend, which can stop work the caller has
+// given up on.
+type TimeoutPolicy struct {
+	// Read applies to read RPCs (see isReadRPC).
+	Read time.Duration
+	// Write applies to all other RPCs.
+	Write time.Duration
+	// Services overrides both classes for a service, keyed by its name with
+	// or without package, e.g. "ReplayService" or "pidgr.v1.ReplayService".
+	Services map[string]time.Duration
+}
+
+// DefaultTimeoutPolicy gives reads 15s and writes 30s. Session replays are
+// large downloads, so the replay service gets 60s.
+var DefaultTimeoutPolicy = TimeoutPolicy{
+	Read:  15 * time.Second,
+	Write: 30 * time.Second,
+	Services: map[string]time.Duration{
+		"ReplayService": 60 * time.Second,
+	},
+}
+
+// timeout returns the timeout for an RPC; zero means none.
+func (p TimeoutPolicy) timeout(spec connect.Spec) time.Duration {
+	service := serviceName(spec.Procedure)
+	if d, ok := p.Services[service]; ok {
+		return d
+	}
+	if i := strings.LastIndex(service, "."); i >= 0 {
+		if d, ok := p.Services[service[i+1:]]; ok {
+			return d
+		}
+	}
+	if isReadRPC(spec) {
+		return p.Read
+	}
+	return p.Write
+}
+
+// serviceName returns the fully qualified service of a procedure, e.g.
+// "pidgr.v1.CampaignService" for "/pidgr.v1.CampaignService/ListCampaigns".
+func serviceName(procedure string) string {
+	procedure = strings.TrimPrefix(procedure, "/")
+	if i := strings.LastIndex(procedure, "/"); i >= 0 {
+		return procedure[:i]
+	}
+	return procedure
+}
+
+// timeoutInterceptor applies policy's timeout to each RPC attempt. A shorter
+// deadline already on the context wins.
+func timeoutInterceptor(policy TimeoutPolicy) connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if d := policy.timeout(req.Spec()); d > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, d)
+				defer cancel()
+			}
+			return next(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+func TestTimeoutPolicy(t *testing.T) {
+	p := TimeoutPolicy{
+		Read:     time.Second,
+		Write:    2 * time.Second,
+		Services: map[string]time.Duration{"ReplayService": 3 * time.Second, "pidgr.v1.HeatmapService": 4 * time.Second},
+	}
+	tests := map[string]time.Duration{
+		"/pidgr.v1.CampaignService/ListCampaigns":     time.Second,
+		"/pidgr.v1.CampaignService/CreateCampaign":    2 * time.Second,
+		"/pidgr.v1.ReplayService/GetSessionSnapshots": 3 * time.Second,
+		"/pidgr.v1.HeatmapService/QueryHeatmapData":   4 * time.Second,
+	}
+	for procedure, want := range tests {
+		if got := p.timeout(connect.Spec{Procedure: procedure}); got != want {
+			t.Errorf("timeout(%s) = %v, want %v", procedure, got, want)
+		}
+	}
+}
+
+func TestTimeoutInterceptor(t *testing.T) {
+	p := TimeoutPolicy{Read: 20 * time.Millisecond}
+	handle := func(int) error {
+		time.Sleep(200 * time.Millisecond)
+		return nil
+	}
+	start := time.Now()
+	_, err := testCall(t, context.Background(), "/pidgr.v1.CampaignService/GetCampaign", handle, connect.WithInterceptors(timeoutInterceptor(p)))
+	if connect.CodeOf(err) != connect.CodeDeadlineExceeded {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("call took %v, expected it to be cut off by the timeout", elapsed)
+	}
+}
+
+func TestDefaultOptionsCopiesServiceTimeouts(t *testing.T) {
+	o := DefaultOptions()
+	o.Timeouts.Services["CampaignService"] = time.Minute
+	if _, ok := DefaultTimeoutPolicy.Services["CampaignService"]; ok {
+		t.Error("modifying options changed the package defaults")
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/pidgr/pidgr-mcp/internal/observability"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingInterceptor creates a client span for each backend RPC attempt, as
+// a child of the tool-call span (see observability.TracingMiddleware), and
+// propagates the trace context to the backend in the request headers. Spans
+// are exported when an OTLP endpoint is configured.
+func tracingInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			procedure := strings.TrimPrefix(req.Spec().Procedure, "/")
+			service, method, _ := strings.Cut(procedure, "/")
+			ctx, span := otel.Tracer(observability.TracerName).Start(ctx, procedure,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					semconv.RPCSystemGRPC,
+					semconv.RPCService(service),
+					semconv.RPCMethod(method),
+				),
+			)
+			defer span.End()
+			otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header()))
+
+			resp, err := next(ctx, req)
+			if err != nil {
+				code := connect.CodeOf(err)
+				// Connect codes share gRPC's numbering.
+				span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+				span.SetStatus(codes.Error, code.String())
+				return resp, err
+			}
+			span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(0))
+			return resp, nil
+		}
+	}
+}
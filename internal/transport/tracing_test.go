@@ -0,0 +1,92 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"connectrpc.com/connect"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// recordSpans installs a tracer provider that records ended spans for the
+// duration of the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	rec := tracetest.NewSpanRecorder()
+	prevTP, prevProp := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevTP)
+		otel.SetTextMapPropagator(prevProp)
+	})
+	return rec
+}
+
+func TestTracingInterceptor(t *testing.T) {
+	rec := recordSpans(t)
+	const procedure = "/pidgr.v1.CampaignService/GetCampaign"
+	var traceparent string
+	mux := http.NewServeMux()
+	mux.Handle(procedure, connect.NewUnaryHandler(procedure, func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+		traceparent = req.Header().Get("Traceparent")
+		return nil, connect.NewError(connect.CodeNotFound, errors.New("no such campaign"))
+	}))
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "tools/call get_campaign")
+	client := connect.NewClient[emptypb.Empty, emptypb.Empty](ts.Client(), ts.URL+procedure, connect.WithInterceptors(tracingInterceptor()))
+	_, err := client.CallUnary(ctx, connect.NewRequest(&emptypb.Empty{}))
+	parent.End()
+	if connect.CodeOf(err) != connect.CodeNotFound {
+		t.Fatalf("got error %v, want not_found", err)
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "pidgr.v1.CampaignService/GetCampaign" {
+		t.Errorf("got span name %q", span.Name())
+	}
+	if span.SpanKind() != trace.SpanKindClient {
+		t.Errorf("got span kind %v, want client", span.SpanKind())
+	}
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("expected RPC span to be a child of the tool-call span")
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("got status %v, want error", span.Status().Code)
+	}
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if got := attrs["rpc.service"].AsString(); got != "pidgr.v1.CampaignService" {
+		t.Errorf("got rpc.service %q", got)
+	}
+	if got := attrs["rpc.method"].AsString(); got != "GetCampaign" {
+		t.Errorf("got rpc.method %q", got)
+	}
+	if got := attrs["rpc.grpc.status_code"].AsInt64(); got != int64(connect.CodeNotFound) {
+		t.Errorf("got rpc.grpc.status_code %d, want %d", got, connect.CodeNotFound)
+	}
+	if traceparent == "" || span.SpanContext().TraceID() != parent.SpanContext().TraceID() {
+		t.Errorf("expected trace context to reach the backend, got traceparent %q", traceparent)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"connectrpc.com/connect"
+)
+
+// UserAgent returns the User-Agent sent on backend requests, such as
+// "pidgr-mcp/v1.4.0 (stdio; linux/amd64)", so backend operators can tell
+// MCP traffic apart and track version rollout. mode is the MCP transport.
+func UserAgent(version, mode string) string {
+	if version != "" && version[0] >= '0' && version[0] <= '9' {
+		version = "v" + version
+	}
+	if version == "" {
+		version = "dev"
+	}
+	return fmt.Sprintf("pidgr-mcp/%s (%s; %s/%s)", version, mode, runtime.GOOS, runtime.GOARCH)
+}
+
+// userAgentInterceptor sets the User-Agent header, replacing Connect's
+// default. An empty ua leaves the default in place.
+func userAgentInterceptor(ua string) connect.UnaryInterceptorFunc {
+	ua = strings.TrimSpace(ua)
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if ua != "" {
+				req.Header().Set("User-Agent", ua)
+			}
+			return next(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 Pidgr, Inc. All rights reserved.
+// Licensed under the Apache License, Version 2.0.
+
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestUserAgent(t *testing.T) {
+	platform := runtime.GOOS + "/" + runtime.GOARCH
+	tests := []struct {
+		version, mode, want string
+	}{
+		{"1.4.0", "stdio", "pidgr-mcp/v1.4.0 (stdio; " + platform + ")"},
+		{"v1.4.0", "http", "pidgr-mcp/v1.4.0 (http; " + platform + ")"},
+		{"dev", "stdio", "pidgr-mcp/dev (stdio; " + platform + ")"},
+		{"", "stdio", "pidgr-mcp/dev (stdio; " + platform + ")"},
+	}
+	for _, tt := range tests {
+		if got := UserAgent(tt.version, tt.mode); got != tt.want {
+			t.Errorf("UserAgent(%q, %q) = %q, want %q", tt.version, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestUserAgentInterceptor(t *testing.T) {
+	const procedure = "/pidgr.v1.CampaignService/GetCampaign"
+	var got string
+	mux := http.NewServeMux()
+	mux.Handle(procedure, connect.NewUnaryHandler(procedure, func(ctx context.Context, req *connect.Request[emptypb.Empty]) (*connect.Response[emptypb.Empty], error) {
+		got = req.Header().Get("User-Agent")
+		return connect.NewResponse(&emptypb.Empty{}), nil
+	}))
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	const ua = "pidgr-mcp/v1.4.0 (stdio; linux/amd64)"
+	client := connect.NewClient[emptypb.Empty, emptypb.Empty](ts.Client(), ts.URL+procedure, connect.WithInterceptors(userAgentInterceptor(ua)))
+	if _, err := client.CallUnary(context.Background(), connect.NewRequest(&emptypb.Empty{})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != ua {
+		t.Errorf("got User-Agent %q, want %q", got, ua)
+	}
+}